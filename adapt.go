@@ -0,0 +1,47 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// AdaptWalkFunc returns an [fs.WalkDirFunc] calling fn, a callback written
+// for [filepath.Walk], so that existing callbacks can be passed to the walk
+// functions of this package unchanged.
+//
+// fn is passed the file info of each entry, read with the Info method of
+// its directory entry as the entry is visited. If the file info cannot be
+// read, fn is passed a nil info and the error, as filepath.Walk does for
+// files it cannot lstat, and [fs.SkipDir] returned for that entry is
+// ignored.
+func AdaptWalkFunc(fn filepath.WalkFunc) fs.WalkDirFunc {
+	return func(path string, d fs.DirEntry, err error) error {
+		if d == nil {
+			return fn(path, nil, err)
+		}
+		info, ierr := d.Info()
+		if ierr != nil {
+			if err == nil {
+				err = ierr
+			}
+			if err := fn(path, nil, err); err != fs.SkipDir {
+				return err
+			}
+			return nil
+		}
+		return fn(path, info, err)
+	}
+}
+
+// AdaptWalkDirFunc returns a [filepath.WalkFunc] calling fn, the reverse
+// of [AdaptWalkFunc], so that callbacks written for the walk functions of
+// this package can be passed to [filepath.Walk]. fn is passed nil for
+// entries filepath.Walk passes no file info for.
+func AdaptWalkDirFunc(fn fs.WalkDirFunc) filepath.WalkFunc {
+	return func(path string, info fs.FileInfo, err error) error {
+		if info == nil {
+			return fn(path, nil, err)
+		}
+		return fn(path, fs.FileInfoToDirEntry(info), err)
+	}
+}
@@ -0,0 +1,111 @@
+package bfwalk
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAdaptWalkFunc(t *testing.T) {
+	errInfo := errors.New("info failed")
+	fsys := infoErrFS{fstest.MapFS{
+		"root/a.txt":  {Data: []byte("abc")},
+		"root/bad":    {},
+		"root/dir/b":  {Data: []byte("a")},
+		"root/skip/c": {},
+	}, "bad", errInfo}
+
+	var visited []string
+	err := WalkDir(fsys, "root", AdaptWalkFunc(func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			visited = append(visited, fmt.Sprint(path, " error:", err))
+			return fs.SkipDir // Ignored for entries without info
+		}
+		visited = append(visited, fmt.Sprint(path, " ", info.Name(), " ", info.Size(), " ", info.IsDir()))
+		if info.Name() == "skip" {
+			return filepath.SkipDir
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"root root 0 true",
+		"root/a.txt a.txt 3 false",
+		"root/bad error:info failed",
+		"root/dir dir 0 true",
+		"root/skip skip 0 true",
+		"root/dir/b b 1 false",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+func TestAdaptWalkDirFunc(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "dir"), 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "dir", "file"), nil, 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var visited []string
+	err := filepath.Walk(root, AdaptWalkDirFunc(func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		visited = append(visited, fmt.Sprint(filepath.ToSlash(rel), " ", d.Name(), " ", d.IsDir()))
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{". " + filepath.Base(root) + " true", "dir dir true", "dir/file file false"}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected %v, got %v", expected, visited)
+	}
+
+	err = filepath.Walk(filepath.Join(root, "missing"), AdaptWalkDirFunc(func(path string, d fs.DirEntry, err error) error {
+		if d != nil {
+			t.Errorf("expected no entry for %s, got %v", path, d)
+		}
+		return err
+	}))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected %v, got %v", fs.ErrNotExist, err)
+	}
+}
+
+// infoErrFS is a file system whose entries named bad fail to return their
+// file info.
+type infoErrFS struct {
+	fstest.MapFS
+	bad string
+	err error
+}
+
+func (f infoErrFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	dirs, err := f.MapFS.ReadDir(name)
+	for i, d := range dirs {
+		if d.Name() == f.bad {
+			dirs[i] = infoErrEntry{d, f.err}
+		}
+	}
+	return dirs, err
+}
+
+type infoErrEntry struct {
+	fs.DirEntry
+	err error
+}
+
+func (e infoErrEntry) Info() (fs.FileInfo, error) { return nil, e.err }
@@ -0,0 +1,48 @@
+package bfwalk
+
+// arenaBlock is the number of entries of the blocks an entryArena allocates,
+// unless a directory needs a larger one.
+const arenaBlock = 64
+
+// An entryArena allocates the arrays that gather the subdirectories found
+// in a directory in blocks, and takes them back once the subdirectories have
+// been queued, so that a walk allocates a few blocks reused from directory
+// to directory and level to level, instead of growing a new array for every
+// directory. Directories visited depth-first from a directory take blocks of
+// their own while its block is in use. The zero entryArena is ready to use.
+type entryArena struct {
+	free [][]namedEntry // released blocks, empty and ready for reuse
+}
+
+// take returns an empty array with room for at least n entries, reusing a
+// released block if one is large enough.
+func (a *entryArena) take(n int) []namedEntry {
+	for i := len(a.free) - 1; i >= 0; i-- {
+		if b := a.free[i]; cap(b) >= n {
+			a.free = append(a.free[:i], a.free[i+1:]...)
+			return b
+		}
+	}
+	return make([]namedEntry, 0, max(n, arenaBlock))
+}
+
+// grow returns s with room for at least one more entry, moving its entries
+// to a block twice as large and releasing the block of s if it is full.
+func (a *entryArena) grow(s []namedEntry) []namedEntry {
+	if len(s) < cap(s) {
+		return s
+	}
+	b := append(a.take(2*cap(s)), s...)
+	a.release(s)
+	return b
+}
+
+// release gives the block of s back to the arena, clearing the entries it
+// holds so that the block keeps no directory entries alive.
+func (a *entryArena) release(s []namedEntry) {
+	if cap(s) == 0 {
+		return
+	}
+	clear(s[:cap(s)])
+	a.free = append(a.free, s[:0])
+}
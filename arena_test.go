@@ -0,0 +1,43 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEntryArena(t *testing.T) {
+	var a entryArena
+	s := a.take(0)
+	if len(s) != 0 || cap(s) != arenaBlock {
+		t.Fatalf("expected an empty block of %d entries, got %d of %d", arenaBlock, len(s), cap(s))
+	}
+	info, err := fstest.MapFS{"dir/file": {}}.Stat("dir")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range arenaBlock + 1 {
+		s = append(a.grow(s), namedEntry{name: "dir", d: fs.FileInfoToDirEntry(info)})
+	}
+	if len(s) != arenaBlock+1 || cap(s) < 2*arenaBlock {
+		t.Fatalf("expected %d entries in a block of at least %d, got %d of %d", arenaBlock+1, 2*arenaBlock, len(s), cap(s))
+	}
+
+	// Released blocks are cleared and reused
+	a.release(s)
+	large := a.take(arenaBlock + 1)
+	if &large[:1][0] != &s[0] {
+		t.Errorf("expected the released large block to be reused")
+	}
+	for i, e := range large[:cap(large)] {
+		if e.d != nil {
+			t.Fatalf("expected released entries to be cleared, got %q at %d", e.name, i)
+		}
+	}
+	if small := a.take(1); cap(small) != arenaBlock {
+		t.Errorf("expected the first block of %d entries to be reused, got %d", arenaBlock, cap(small))
+	}
+	if len(a.free) != 0 {
+		t.Errorf("expected no free blocks, got %d", len(a.free))
+	}
+}
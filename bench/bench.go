@@ -0,0 +1,170 @@
+// Package bench compares the speed and memory use of walks of file trees
+// with [fs.WalkDir] and with bfwalk in its various modes, so that the
+// choice of a walker can be based on numbers measured on trees shaped like
+// the ones it will walk:
+//
+//	results, err := bench.Run(bench.Config{
+//		Tree: bfwalktest.Config{Dirs: 100, Depth: 5, Fanout: 2},
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	bench.WriteJSON(os.Stdout, results)
+//
+// Measurements are taken with the runtime's memory statistics, which count
+// the allocations of every goroutine of the program, so nothing else should
+// run while they are taken.
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"runtime"
+	"time"
+
+	"github.com/eriicafes/bfwalk"
+	"github.com/eriicafes/bfwalk/bfwalktest"
+)
+
+// A Mode is a way of walking a file tree compared by [Run] and [Compare].
+type Mode struct {
+	Name string
+
+	// Walk walks the file tree rooted at root, calling fn for each file or
+	// directory, and returns the most directories waiting to be read at
+	// once, or 0 if the walk does not queue directories.
+	Walk func(fsys fs.FS, root string, fn fs.WalkDirFunc) (maxQueue int64, err error)
+}
+
+// StdWalkDir returns the Mode walking trees with [fs.WalkDir], depth-first,
+// as the baseline of the comparison.
+func StdWalkDir() Mode {
+	return Mode{"fs.WalkDir", func(fsys fs.FS, root string, fn fs.WalkDirFunc) (int64, error) {
+		return 0, fs.WalkDir(fsys, root, fn)
+	}}
+}
+
+// Walker returns the Mode named name walking trees with a [bfwalk.Walker]
+// configured with opts.
+func Walker(name string, opts ...bfwalk.Option) Mode {
+	return Mode{name, func(fsys fs.FS, root string, fn fs.WalkDirFunc) (int64, error) {
+		w := bfwalk.NewWalker(opts...)
+		err := w.WalkDir(fsys, root, fn)
+		return w.Stats().MaxQueue, err
+	}}
+}
+
+// DefaultModes returns the modes compared when none are given: fs.WalkDir,
+// and bfwalk by default, with [bfwalk.WithUnsorted], and with
+// [bfwalk.WithConcurrency] of 8.
+func DefaultModes() []Mode {
+	return []Mode{
+		StdWalkDir(),
+		Walker("bfwalk"),
+		Walker("bfwalk unsorted", bfwalk.WithUnsorted()),
+		Walker("bfwalk concurrent", bfwalk.WithConcurrency(8)),
+	}
+}
+
+// A Config describes a comparison run by [Run].
+type Config struct {
+	Tree  bfwalktest.Config // shape of the tree walked, see [bfwalktest.Generate]
+	Modes []Mode            // modes compared, DefaultModes if nil
+	Runs  int               // walks measured with each mode, 10 if 0
+}
+
+// A Result holds the measurements of the walks of a tree with a Mode,
+// averaged over the walks.
+type Result struct {
+	Mode          string        `json:"mode"`          // name of the Mode
+	Runs          int           `json:"runs"`          // walks measured
+	Entries       int64         `json:"entries"`       // entries passed to the callback by each walk
+	Duration      time.Duration `json:"nsPerWalk"`     // time taken by each walk
+	EntriesPerSec float64       `json:"entriesPerSec"` // entries visited per second
+	Allocs        uint64        `json:"allocsPerWalk"` // heap allocations made by each walk
+	Bytes         uint64        `json:"bytesPerWalk"`  // bytes allocated by each walk
+	MaxQueue      int64         `json:"maxQueue"`      // most directories waiting to be read at once
+}
+
+// Run generates the tree described by c.Tree and returns the results of
+// walking it with each mode of c, in order, as [Compare] does.
+func Run(c Config) ([]Result, error) {
+	fsys := bfwalktest.Generate(c.Tree)
+	root := c.Tree.Root
+	if root == "" {
+		root = "root"
+	}
+	return Compare(fsys, root, c.Modes, c.Runs)
+}
+
+// Compare walks the file tree rooted at root runs times with each of modes,
+// in order, after a first walk that is not measured, and returns the
+// results of each mode. It uses DefaultModes if modes is nil, and 10 runs if
+// runs is 0 or less. An error returned by a walk stops the comparison and is
+// returned together with the results of the modes compared before it.
+func Compare(fsys fs.FS, root string, modes []Mode, runs int) ([]Result, error) {
+	if modes == nil {
+		modes = DefaultModes()
+	}
+	if runs <= 0 {
+		runs = 10
+	}
+	var results []Result
+	for _, m := range modes {
+		r, err := measure(fsys, root, m, runs)
+		if err != nil {
+			return results, fmt.Errorf("bench: %s: %w", m.Name, err)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// measure returns the results of walking the tree rooted at root runs times
+// with m.
+func measure(fsys fs.FS, root string, m Mode, runs int) (Result, error) {
+	var entries int64
+	count := func(path string, d fs.DirEntry, err error) error {
+		entries++
+		return err
+	}
+	if _, err := m.Walk(fsys, root, count); err != nil {
+		return Result{}, err
+	}
+
+	r := Result{Mode: m.Name, Runs: runs, Entries: entries}
+	entries = 0
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for range runs {
+		maxQueue, err := m.Walk(fsys, root, count)
+		if err != nil {
+			return Result{}, err
+		}
+		r.MaxQueue = max(r.MaxQueue, maxQueue)
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	n := uint64(runs)
+	r.Duration = elapsed / time.Duration(runs)
+	r.EntriesPerSec = float64(entries) / elapsed.Seconds()
+	r.Allocs = (after.Mallocs - before.Mallocs) / n
+	r.Bytes = (after.TotalAlloc - before.TotalAlloc) / n
+	return r, nil
+}
+
+// WriteJSON writes results to w as JSON Lines, one object per result.
+func WriteJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	for _, r := range results {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
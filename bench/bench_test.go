@@ -0,0 +1,87 @@
+package bench
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/eriicafes/bfwalk"
+	"github.com/eriicafes/bfwalk/bfwalktest"
+)
+
+func TestRun(t *testing.T) {
+	tree := bfwalktest.Config{Seed: 1, Dirs: 3, Depth: 2, FilesPerDir: 4}
+	results, err := Run(Config{Tree: tree, Runs: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fsys := bfwalktest.Generate(tree)
+	entries := int64(len(bfwalktest.Visited(t, fsys, "root")))
+
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Mode)
+		if r.Entries != entries {
+			t.Errorf("%s: expected %d entries, got %d", r.Mode, entries, r.Entries)
+		}
+		if r.Runs != 2 || r.Duration <= 0 || r.EntriesPerSec <= 0 || r.Allocs == 0 || r.Bytes == 0 {
+			t.Errorf("%s: unexpected measurements %+v", r.Mode, r)
+		}
+		if r.Mode == "fs.WalkDir" {
+			if r.MaxQueue != 0 {
+				t.Errorf("%s: expected no queue, got %d", r.Mode, r.MaxQueue)
+			}
+		} else if r.MaxQueue != 3 {
+			t.Errorf("%s: expected max queue 3, got %d", r.Mode, r.MaxQueue)
+		}
+	}
+	expected := []string{"fs.WalkDir", "bfwalk", "bfwalk unsorted", "bfwalk concurrent"}
+	if !slices.Equal(names, expected) {
+		t.Errorf("expected modes %v, got %v", expected, names)
+	}
+}
+
+func TestCompareError(t *testing.T) {
+	fsys := bfwalktest.Generate(bfwalktest.Config{Dirs: 1})
+	errBad := errors.New("bad")
+	bad := Mode{"bad", func(fs.FS, string, fs.WalkDirFunc) (int64, error) { return 0, errBad }}
+
+	results, err := Compare(fsys, "root", []Mode{Walker("first", bfwalk.WithUnsorted()), bad, StdWalkDir()}, 1)
+	if !errors.Is(err, errBad) || !strings.Contains(err.Error(), "bad") {
+		t.Errorf("expected error wrapping %v, got %v", errBad, err)
+	}
+	if len(results) != 1 || results[0].Mode != "first" {
+		t.Errorf("expected the results of the first mode, got %+v", results)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	results := []Result{{Mode: "a", Runs: 1, Entries: 3}, {Mode: "b", MaxQueue: 2}}
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first, _, _ := strings.Cut(buf.String(), "\n"); !strings.Contains(first, `"entriesPerSec":0`) {
+		t.Errorf("unexpected record %s", first)
+	}
+
+	var got []Result
+	s := bufio.NewScanner(&buf)
+	for s.Scan() {
+		var r Result
+		if err := json.Unmarshal(s.Bytes(), &r); err != nil {
+			t.Fatalf("line %q: %v", s.Text(), err)
+		}
+		got = append(got, r)
+	}
+	if !slices.Equal(got, results) {
+		t.Errorf("expected %+v, got %+v", results, got)
+	}
+}
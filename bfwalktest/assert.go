@@ -0,0 +1,90 @@
+package bfwalktest
+
+import (
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+// Visited walks the file tree rooted at root with a [bfwalk.Walker]
+// configured with opts and returns the paths passed to the walk callback,
+// in order. Any error stops the test with t.Fatal.
+func Visited(t testing.TB, fsys fs.FS, root string, opts ...bfwalk.Option) []string {
+	t.Helper()
+	var visited []string
+	err := bfwalk.NewWalker(opts...).WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return visited
+}
+
+// AssertOrder walks the file tree rooted at root with a [bfwalk.Walker]
+// configured with opts and reports a test error if the paths passed to the
+// walk callback are not exactly expected, in order.
+func AssertOrder(t testing.TB, fsys fs.FS, root string, expected []string, opts ...bfwalk.Option) {
+	t.Helper()
+	visited := Visited(t, fsys, root, opts...)
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+// AssertBefore reports a test error unless a and b are both in visited,
+// with a first.
+func AssertBefore(t testing.TB, visited []string, a, b string) {
+	t.Helper()
+	i, j := slices.Index(visited, a), slices.Index(visited, b)
+	switch {
+	case i < 0:
+		t.Errorf("%s was not visited", a)
+	case j < 0:
+		t.Errorf("%s was not visited", b)
+	case i > j:
+		t.Errorf("expected %s to be visited before %s", a, b)
+	}
+}
+
+// AssertBreadthFirst reports a test error unless every path in visited
+// comes after all paths that are less deep, where depth is the number of
+// slash-separated elements of the path.
+func AssertBreadthFirst(t testing.TB, visited []string) {
+	t.Helper()
+	deepest, at := -1, ""
+	for _, name := range visited {
+		d := depth(name)
+		if d < deepest {
+			t.Errorf("expected %s to be visited before %s", name, at)
+			return
+		}
+		deepest, at = d, name
+	}
+}
+
+// depth returns the number of path elements of name.
+func depth(name string) int {
+	if name == "." {
+		return 0
+	}
+	return strings.Count(name, "/") + 1
+}
+
+// AssertDeterministic walks the file tree rooted at root twice with a
+// [bfwalk.Walker] configured with opts, and reports a test error describing
+// the first divergence if the walks do not make the same calls to the walk
+// callback, as checked by [bfwalk.VerifyDeterministic].
+func AssertDeterministic(t testing.TB, fsys fs.FS, root string, opts ...bfwalk.Option) {
+	t.Helper()
+	if err := bfwalk.VerifyDeterministic(fsys, root, 2, opts...); err != nil {
+		t.Errorf("walk is not deterministic: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package bfwalktest
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+// recorder is a testing.TB that records reported errors instead of
+// failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertOrder(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":      {Data: []byte("")},
+		"root/dirA/file1.txt": {Data: []byte("")},
+	}
+
+	AssertOrder(t, memFS, "root", []string{
+		"root",
+		"root/dirA",
+		"root/file1.txt",
+		"root/dirA/file1.txt",
+	})
+	AssertOrder(t, memFS, "root", []string{
+		".",
+		"dirA",
+		"file1.txt",
+		"dirA/file1.txt",
+	}, bfwalk.WithRelativePaths())
+
+	r := &recorder{TB: t}
+	AssertOrder(r, memFS, "root", []string{"root"})
+	if len(r.errors) != 1 {
+		t.Errorf("expected 1 error, got %v", r.errors)
+	}
+}
+
+func TestAssertBefore(t *testing.T) {
+	visited := []string{"root", "root/a", "root/b"}
+
+	r := &recorder{TB: t}
+	AssertBefore(r, visited, "root/a", "root/b")
+	if len(r.errors) != 0 {
+		t.Errorf("unexpected errors: %v", r.errors)
+	}
+	AssertBefore(r, visited, "root/b", "root/a")
+	AssertBefore(r, visited, "root/c", "root/a")
+	if len(r.errors) != 2 {
+		t.Errorf("expected 2 errors, got %v", r.errors)
+	}
+}
+
+func TestAssertBreadthFirst(t *testing.T) {
+	fsys := Generate(Config{Dirs: 3, Depth: 3, Fanout: 2})
+	AssertBreadthFirst(t, Visited(t, fsys, "root"))
+
+	r := &recorder{TB: t}
+	AssertBreadthFirst(r, []string{"root", "root/a/b", "root/c"})
+	if len(r.errors) != 1 {
+		t.Errorf("expected 1 error, got %v", r.errors)
+	}
+}
+
+// shuffleFS lists each directory in reverse order every other time it is
+// read.
+type shuffleFS struct {
+	fstest.MapFS
+	reads map[string]int
+}
+
+func (f shuffleFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	dirs, err := f.MapFS.ReadDir(name)
+	f.reads[name]++
+	if f.reads[name]%2 == 0 {
+		slices.Reverse(dirs)
+	}
+	return dirs, err
+}
+
+func TestAssertDeterministic(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":      {},
+		"root/file2.txt":      {},
+		"root/dirA/file1.txt": {},
+	}
+	AssertDeterministic(t, memFS, "root")
+
+	r := &recorder{TB: t}
+	AssertDeterministic(r, shuffleFS{memFS, make(map[string]int)}, "root")
+	if len(r.errors) != 1 {
+		t.Fatalf("expected 1 error, got %v", r.errors)
+	}
+	expected := `walk is not deterministic: bfwalk: walk diverges at call 1: got "root/file2.txt" (depth 1, type ----------), want "root/dirA" (depth 1, type d---------)`
+	if r.errors[0] != expected {
+		t.Errorf("expected %q, got %q", expected, r.errors[0])
+	}
+}
@@ -0,0 +1,118 @@
+// Package bfwalktest provides utilities for testing code built on bfwalk.
+package bfwalktest
+
+import (
+	"fmt"
+	"io/fs"
+	"math/rand/v2"
+	"path"
+	"strings"
+	"testing/fstest"
+)
+
+// DefaultPatterns are the file name patterns used by [Generate] when none
+// are configured: source files of a small web project, with a layout file
+// in some directories.
+var DefaultPatterns = []string{
+	"file%d.html",
+	"file%d.go",
+	"file%d.ts",
+	"file%d.css",
+	"layout.html",
+}
+
+// A Config describes the shape of a file tree built by [Generate].
+type Config struct {
+	Root        string   // directory holding the tree, "root" if empty
+	Seed        uint64   // seed of the random choices made by Generate
+	Dirs        int      // number of directories in Root
+	Depth       int      // levels of directories in each of them, 1 if 0
+	Fanout      int      // subdirectories of each nested directory, 1 if 0
+	FilesPerDir int      // files in each directory, 10 if 0
+	Patterns    []string // file name patterns, DefaultPatterns if nil
+	Symlinks    float64  // fraction of files that are symlinks, from 0 to 1
+}
+
+// Generate returns a file tree shaped as described by c. The same Config
+// always generates the same tree.
+//
+// Root holds c.Dirs directories, each the top of a tree of c.Depth levels
+// of directories, where every directory but those at the bottom holds
+// c.Fanout subdirectories. Directories are named dir<i>_<level>, where i is
+// the position of the top directory in Root, followed by _<j> for the j-th
+// subdirectory of its parent when c.Fanout is more than 1.
+//
+// Every directory below Root holds c.FilesPerDir files, each named after a
+// pattern picked at random from c.Patterns, with any %d verb replaced by
+// the position of the file in its directory. Names picked twice in the same
+// directory are skipped, so directories may hold fewer files. Each file is
+// turned into a symlink to a regular file of the same directory with
+// probability c.Symlinks.
+func Generate(c Config) fstest.MapFS {
+	if c.Root == "" {
+		c.Root = "root"
+	}
+	if c.Depth <= 0 {
+		c.Depth = 1
+	}
+	if c.Fanout <= 0 {
+		c.Fanout = 1
+	}
+	if c.FilesPerDir <= 0 {
+		c.FilesPerDir = 10
+	}
+	if c.Patterns == nil {
+		c.Patterns = DefaultPatterns
+	}
+	g := &generator{
+		c:    c,
+		rng:  rand.New(rand.NewPCG(c.Seed, c.Seed)),
+		fsys: fstest.MapFS{},
+	}
+	for i := range c.Dirs {
+		g.dir(path.Join(c.Root, fmt.Sprintf("dir%d_0", i)), i, 0)
+	}
+	return g.fsys
+}
+
+type generator struct {
+	c    Config
+	rng  *rand.Rand
+	fsys fstest.MapFS
+}
+
+// dir creates the directory name at the given level of the tree under the
+// i-th directory of Root, with its files and subdirectories.
+func (g *generator) dir(name string, i, level int) {
+	g.fsys[name] = &fstest.MapFile{Mode: fs.ModeDir | 0o755}
+
+	var files []string
+	for f := range g.c.FilesPerDir {
+		pattern := g.c.Patterns[g.rng.IntN(len(g.c.Patterns))]
+		if strings.Contains(pattern, "%d") {
+			pattern = fmt.Sprintf(pattern, f)
+		}
+		filePath := path.Join(name, pattern)
+		if _, ok := g.fsys[filePath]; ok {
+			continue
+		}
+		if len(files) > 0 && g.rng.Float64() < g.c.Symlinks {
+			target := files[g.rng.IntN(len(files))]
+			g.fsys[filePath] = &fstest.MapFile{Data: []byte(target), Mode: fs.ModeSymlink | 0o777}
+			continue
+		}
+		content := fmt.Appendf(nil, "// dummy content for %s\n", filePath)
+		g.fsys[filePath] = &fstest.MapFile{Data: content, Mode: 0o644}
+		files = append(files, pattern)
+	}
+
+	if level+1 < g.c.Depth {
+		for j := range g.c.Fanout {
+			sub := fmt.Sprintf("dir%d_%d", i, level+1)
+			if g.c.Fanout > 1 {
+				sub += fmt.Sprintf("_%d", j)
+			}
+			g.dir(path.Join(name, sub), i, level+1)
+		}
+	}
+}
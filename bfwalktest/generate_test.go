@@ -0,0 +1,68 @@
+package bfwalktest
+
+import (
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGenerate(t *testing.T) {
+	fsys := Generate(Config{Seed: 1, Dirs: 2, Depth: 3, FilesPerDir: 4})
+
+	var dirs []string
+	files := 0
+	for name, f := range fsys {
+		if f.Mode.IsDir() {
+			dirs = append(dirs, name)
+		} else {
+			files++
+		}
+	}
+	slices.Sort(dirs)
+	expected := []string{
+		"root/dir0_0",
+		"root/dir0_0/dir0_1",
+		"root/dir0_0/dir0_1/dir0_2",
+		"root/dir1_0",
+		"root/dir1_0/dir1_1",
+		"root/dir1_0/dir1_1/dir1_2",
+	}
+	if !slices.Equal(dirs, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, dirs)
+	}
+	if files == 0 || files > 6*4 {
+		t.Errorf("expected up to %d files, got %d", 6*4, files)
+	}
+	if err := fstest.TestFS(fsys, "root/dir0_0/dir0_1/dir0_2"); err != nil {
+		t.Errorf("invalid file system: %v", err)
+	}
+}
+
+func TestGenerateSeed(t *testing.T) {
+	c := Config{Seed: 42, Dirs: 3, Depth: 2, Fanout: 2, Symlinks: 0.5}
+	a, b := Generate(c), Generate(c)
+	if !slices.Equal(slices.Sorted(maps.Keys(a)), slices.Sorted(maps.Keys(b))) {
+		t.Errorf("same config generated different trees")
+	}
+
+	symlinks := 0
+	for name, f := range a {
+		if f.Mode&fs.ModeSymlink == 0 {
+			continue
+		}
+		symlinks++
+		target := a[path.Join(path.Dir(name), string(f.Data))]
+		if target == nil || !target.Mode.IsRegular() {
+			t.Errorf("symlink %s points to missing file %s", name, f.Data)
+		}
+	}
+	if symlinks == 0 {
+		t.Errorf("expected symlinks")
+	}
+	if _, ok := a["root/dir2_0/dir2_1_1"]; !ok {
+		t.Errorf("expected fanout directories")
+	}
+}
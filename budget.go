@@ -0,0 +1,35 @@
+package bfwalk
+
+// ErrBudgetExceeded is returned by a walk that reads more entries than the
+// budget set with [WithMaxVisited].
+var ErrBudgetExceeded error = budgetExceededError{}
+
+type budgetExceededError struct{}
+
+func (budgetExceededError) Error() string { return "bfwalk: visit budget exceeded" }
+
+// WithMaxVisited makes walks stop with [ErrBudgetExceeded] once they have
+// read more than n entries in total, including the roots and entries left
+// out of the walk by pruning and filtering options, as a safety valve for
+// walking unknown or untrusted file systems. Unlike [WithMaxResults], which
+// bounds the calls of the walk callback, it bounds the work of the walk.
+//
+// The budget is checked after each directory read, so the walk stops before
+// visiting the entries of the read that exceeds it. Directories read once
+// per pass by [Walker.IDWalk] are counted each time. A budget of 0 or less
+// means no limit.
+func WithMaxVisited(n int) Option {
+	return func(w *Walker) {
+		w.maxVisited = n
+	}
+}
+
+// spend records that n more entries were read, and returns
+// [ErrBudgetExceeded] if that exceeds the budget of the walk.
+func (w *Walker) spend(n int) error {
+	w.spent += n
+	if w.maxVisited > 0 && w.spent > w.maxVisited {
+		return ErrBudgetExceeded
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithMaxVisited(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/.git/HEAD":    {},
+		"root/.git/config":  {},
+		"root/a/file":       {},
+		"root/b/file":       {},
+		"root/node_modules": {Mode: fs.ModeDir},
+	}
+
+	tests := []struct {
+		name     string
+		budget   int
+		opts     []Option
+		expected []string
+		err      error
+	}{
+		{"no limit", 0, nil, []string{"root", "root/.git", "root/a", "root/b", "root/node_modules", "root/.git/HEAD", "root/.git/config", "root/a/file", "root/b/file"}, nil},
+		{"exact", 9, nil, []string{"root", "root/.git", "root/a", "root/b", "root/node_modules", "root/.git/HEAD", "root/.git/config", "root/a/file", "root/b/file"}, nil},
+		{"exceeded", 7, nil, []string{"root", "root/.git", "root/a", "root/b", "root/node_modules", "root/.git/HEAD", "root/.git/config"}, ErrBudgetExceeded},
+		{"pruned", 4, []Option{WithDefaultPrunes()}, []string{"root"}, ErrBudgetExceeded},
+	}
+	for _, tt := range tests {
+		got, err := Collect(fsys, "root", append(tt.opts, WithMaxVisited(tt.budget))...)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.err, err)
+		}
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+
+	var ids []string
+	err := NewWalker(WithMaxVisited(12)).IDWalk(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		ids = append(ids, path)
+		return err
+	})
+	if err != ErrBudgetExceeded {
+		t.Errorf("IDWalk: expected %v, got %v", ErrBudgetExceeded, err)
+	}
+	// The root is read again for the second level
+	expected := []string{"root", "root/.git", "root/a", "root/b", "root/node_modules", "root/.git/HEAD", "root/.git/config", "root/a/file"}
+	if !slices.Equal(ids, expected) {
+		t.Errorf("IDWalk: expected %v, got %v", expected, ids)
+	}
+}
@@ -0,0 +1,53 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"sync"
+)
+
+// A CachedEntry is an [fs.DirEntry] that calls Info on the underlying entry
+// at most once and returns the same result to every caller.
+//
+// Walkers pass every entry to the walk callback as a *CachedEntry, so that
+// callbacks checking the size and modification time of an entry separately
+// do not stat it twice on file systems where Info is not cached.
+type CachedEntry struct {
+	fs.DirEntry
+
+	once        sync.Once
+	info        fs.FileInfo
+	err         error
+	contentType string // with WithDetectContentType
+}
+
+// NewCachedEntry returns a [CachedEntry] wrapping d.
+func NewCachedEntry(d fs.DirEntry) *CachedEntry {
+	if c, ok := d.(*CachedEntry); ok {
+		return c
+	}
+	return &CachedEntry{DirEntry: d}
+}
+
+// Info returns the file info of the underlying entry, which is read on the
+// first call. It is safe to call Info from multiple goroutines.
+func (e *CachedEntry) Info() (fs.FileInfo, error) {
+	e.once.Do(func() {
+		e.info, e.err = e.DirEntry.Info()
+	})
+	return e.info, e.err
+}
+
+// String returns a formatted version of the entry, as [fs.FormatDirEntry].
+func (e *CachedEntry) String() string {
+	return fs.FormatDirEntry(e)
+}
+
+// cacheEntries replaces each entry of dirs with a [CachedEntry], allocating
+// them together.
+func cacheEntries(dirs []fs.DirEntry) {
+	entries := make([]CachedEntry, len(dirs))
+	for i, d := range dirs {
+		entries[i].DirEntry = d
+		dirs[i] = &entries[i]
+	}
+}
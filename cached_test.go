@@ -0,0 +1,50 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCachedEntry(t *testing.T) {
+	fsys := countingFS{
+		MapFS: fstest.MapFS{
+			"root/file1.txt":      {Data: []byte("hello")},
+			"root/dirA/file1.txt": {Data: []byte("")},
+		},
+		stats: new(atomic.Int64),
+	}
+
+	err := WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if _, ok := d.(*CachedEntry); !ok {
+			t.Errorf("expected *CachedEntry for %s, got %T", path, d)
+		}
+		for range 3 {
+			if _, err := d.Info(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := fsys.stats.Load(); n != 3 {
+		t.Errorf("expected 3 calls to Info, got %d", n)
+	}
+}
+
+func TestNewCachedEntry(t *testing.T) {
+	info, err := fs.Stat(fstest.MapFS{"file1.txt": {}}, "file1.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := NewCachedEntry(fs.FileInfoToDirEntry(info))
+	if NewCachedEntry(d) != d {
+		t.Errorf("expected a CachedEntry not to be wrapped again")
+	}
+}
@@ -0,0 +1,41 @@
+package bfwalk
+
+import (
+	"path"
+	"strings"
+)
+
+// WithCaseInsensitive makes the name filters of the Walker ignore case, so
+// that a pattern such as "*.JPG" matches photo.jpg: the patterns of
+// [WithInclude] and [WithPreferNames], the names of [WithPruneNames] and the
+// extensions of [WithExtensions].
+//
+// Expressions passed to [WithFilter] and [WithPrune], and ignore files read
+// with [WithIgnoreFiles], still match case-sensitively. With
+// [WithInclude], directories are read in full rather than with Glob.
+func WithCaseInsensitive() Option {
+	return func(w *Walker) {
+		w.foldCase = true
+	}
+}
+
+// sameName reports whether the names a and b are the same, ignoring case
+// with WithCaseInsensitive.
+func (w *Walker) sameName(a, b string) bool {
+	a, b = w.norm(a), w.norm(b)
+	if w.foldCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// matchName reports whether name matches the shell pattern pattern,
+// ignoring case with WithCaseInsensitive.
+func (w *Walker) matchName(pattern, name string) bool {
+	pattern, name = w.norm(pattern), w.norm(name)
+	if w.foldCase {
+		pattern, name = strings.ToLower(pattern), strings.ToLower(name)
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
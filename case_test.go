@@ -0,0 +1,57 @@
+package bfwalk
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithCaseInsensitive(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/IMG_1.JPG":           {},
+		"root/img_2.jpg":           {},
+		"root/Index.HTML":          {},
+		"root/notes.txt":           {},
+		"root/Node_Modules/x.jpg":  {},
+		"root/Uploads/photo.Jpeg":  {},
+		"root/uploads2/photo.jpeg": {},
+	}
+
+	include := WithInclude("*.jpg", "uploads/*")
+	cases := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{"IncludeFold", []Option{include, WithCaseInsensitive()}, []string{
+			"root", "root/IMG_1.JPG", "root/Uploads", "root/img_2.jpg", "root/Uploads/photo.Jpeg",
+		}},
+		{"Include", []Option{include}, []string{"root", "root/img_2.jpg"}},
+		{"ExtensionsFold", []Option{WithCaseInsensitive(), WithExtensions("jpg", ".jpeg"), WithPruneNames("node_modules")}, []string{
+			"root", "root/IMG_1.JPG", "root/Uploads", "root/img_2.jpg", "root/uploads2",
+			"root/Uploads/photo.Jpeg", "root/uploads2/photo.jpeg",
+		}},
+		{"PreferNamesFold", []Option{WithPreferNames("index.*"), WithExtensions("html"), WithPruneNames("Node_Modules", "Uploads", "uploads2"), WithCaseInsensitive()}, []string{
+			"root", "root/Index.HTML",
+		}},
+		{"PreferNames", []Option{WithPreferNames("*.jpg"), WithInclude("*")}, []string{
+			"root", "root/img_2.jpg", "root/IMG_1.JPG", "root/Index.HTML", "root/Node_Modules",
+			"root/Uploads", "root/notes.txt", "root/uploads2",
+		}},
+		{"PreferNamesCaseFold", []Option{WithPreferNames("*.jpg"), WithInclude("*"), WithCaseInsensitive()}, []string{
+			"root", "root/IMG_1.JPG", "root/img_2.jpg", "root/Index.HTML", "root/Node_Modules",
+			"root/Uploads", "root/notes.txt", "root/uploads2",
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := Collect(memFS, "root", c.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, c.expected) {
+				t.Errorf("expected:\n  %v\ngot\n: %v", c.expected, got)
+			}
+		})
+	}
+}
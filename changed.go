@@ -0,0 +1,60 @@
+package bfwalk
+
+import "io/fs"
+
+// WithTrustDirModTimes makes [Walker.WalkChanged] skip directories whose
+// modification time is the one recorded in the previous manifest, without
+// reading them, so that walks of large unchanged trees read only their top.
+//
+// The modification time of a directory usually changes only when entries
+// are added to, removed from or renamed within it, not when its files are
+// modified in place or when its subdirectories change, so only use this
+// for trees where files are replaced rather than modified, and directories
+// rather than their contents, such as build outputs written by rename.
+func WithTrustDirModTimes() Option {
+	return func(w *Walker) {
+		w.trustDirs = true
+	}
+}
+
+// WalkChanged walks the file tree rooted at root like [WalkDir], calling fn
+// only for the entries that are new or changed since prev, a [Manifest] of
+// the tree taken earlier with [Snapshot]. Entries are changed if they
+// differ from prev as described by [Manifest.Diff], or if their file info
+// cannot be read; errors reading directories are passed to fn as usual.
+//
+// Unchanged directories are walked without calling fn for them. Entries
+// removed since prev are not reported; see [Manifest.Diff] for those.
+func WalkChanged(fsys fs.FS, root string, prev *Manifest, fn fs.WalkDirFunc) error {
+	return NewWalker().WalkChanged(fsys, root, prev, fn)
+}
+
+// WalkChanged walks the file tree rooted at root like the package-level
+// [WalkChanged] function, with the traversal adjusted by the options the
+// Walker was created with. Entries are matched to those of prev by their
+// path relative to root, so prev may be a snapshot of another copy of the
+// tree.
+func (w *Walker) WalkChanged(fsys fs.FS, root string, prev *Manifest, fn fs.WalkDirFunc) error {
+	index := prev.byPath()
+	return w.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fn(path, d, err)
+		}
+		old, ok := index[relPath(w.current.root, w.current.name)]
+		if !ok {
+			return fn(path, d, nil)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return fn(path, d, nil)
+		}
+		cur := ManifestEntry{Mode: info.Mode(), Size: info.Size(), ModTime: info.ModTime()}
+		if old.modified(cur) {
+			return fn(path, d, nil)
+		}
+		if d.IsDir() && w.trustDirs && old.ModTime.Equal(cur.ModTime) {
+			return fs.SkipDir
+		}
+		return nil
+	})
+}
@@ -0,0 +1,74 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestWalkChanged(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	fsys := fstest.MapFS{
+		"root":             {Mode: fs.ModeDir, ModTime: t0},
+		"root/same":        {Data: []byte("a"), ModTime: t0},
+		"root/edited":      {Data: []byte("a"), ModTime: t0},
+		"root/src":         {Mode: fs.ModeDir, ModTime: t0},
+		"root/src/main.go": {Data: []byte("a"), ModTime: t0},
+		"root/out":         {Mode: fs.ModeDir, ModTime: t0},
+		"root/out/app":     {Data: []byte("a"), ModTime: t0},
+	}
+	prev, err := Snapshot(fsys, "root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fsys["root/edited"] = &fstest.MapFile{Data: []byte("ab"), ModTime: t0}
+	fsys["root/src/main.go"] = &fstest.MapFile{Data: []byte("a"), ModTime: t1} // Edited in place
+	fsys["root/out/new"] = &fstest.MapFile{ModTime: t1}
+	fsys["root/out"] = &fstest.MapFile{Mode: fs.ModeDir, ModTime: t1}
+	fsys["root/added/file"] = &fstest.MapFile{ModTime: t1}
+	fsys["root"] = &fstest.MapFile{Mode: fs.ModeDir, ModTime: t1}
+
+	walk := func(w *Walker) []string {
+		t.Helper()
+		var got []string
+		err := w.WalkChanged(fsys, "root", prev, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			got = append(got, path)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return got
+	}
+
+	got := walk(NewWalker())
+	expected := []string{"root/added", "root/edited", "root/added/file", "root/out/new", "root/src/main.go"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// Trusting directory modification times skips root/src, whose file was
+	// edited in place
+	got = walk(NewWalker(WithTrustDirModTimes(), WithRelativePaths()))
+	expected = []string{"added", "edited", "added/file", "out/new"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("trusted: expected %v, got %v", expected, got)
+	}
+
+	// Nothing is read below an unchanged root
+	var calls int
+	err = NewWalker(WithTrustDirModTimes()).WalkChanged(fsys, "root/src", prev, func(path string, d fs.DirEntry, err error) error {
+		calls++
+		return err
+	})
+	if err != nil || calls != 0 {
+		t.Errorf("expected no calls and no error, got %d calls and %v", calls, err)
+	}
+}
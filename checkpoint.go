@@ -0,0 +1,114 @@
+package bfwalk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+)
+
+// checkpoint is the serialized form of the pending state of a walk.
+type checkpoint struct {
+	Pending []checkpointDir `json:"pending,omitempty"` // queued directories
+	Dir     *checkpointDir  `json:"dir,omitempty"`     // partially visited directory
+	After   string          `json:"after,omitempty"`   // last entry visited in Dir
+	Found   []checkpointDir `json:"found,omitempty"`   // directories found in Dir
+}
+
+// checkpointDir is a directory recorded in a checkpoint, with the root it
+// was found under.
+type checkpointDir struct {
+	Path string `json:"path"`
+	Root string `json:"root"`
+}
+
+func newCheckpointDir(e namedEntry) checkpointDir {
+	return checkpointDir{e.name, e.root}
+}
+
+func (c checkpointDir) entry(fsys fs.FS) namedEntry {
+	return namedEntry{c.Path, pendingDir{fsys, c.Path}, c.Root, depthOf(c.Root, c.Path), nil}
+}
+
+// Checkpoint returns a snapshot of the work remaining in the walk in
+// progress, which can be passed to [Walker.Resume] or [ResumeWalk] to
+// continue the walk later, possibly in another process.
+//
+// Checkpoint must be called from the callback of the walk in progress. The
+// snapshot treats the entry being visited as done, so resuming continues
+// with the entry that follows it. A snapshot taken while visiting the root,
+// or after the walk is done, has no work remaining. Walks using the [DFS]
+// strategy or [WithHybridStrategy] cannot be checkpointed.
+func (w *Walker) Checkpoint() ([]byte, error) {
+	if w.dfsDepth >= 0 {
+		return nil, errors.New("bfwalk: checkpoint of depth-first walk")
+	}
+	c := checkpoint{After: w.last}
+	if w.dir.d != nil {
+		dir := newCheckpointDir(w.dir)
+		c.Dir = &dir
+	}
+	for _, e := range w.pending() {
+		c.Pending = append(c.Pending, newCheckpointDir(e))
+	}
+	for _, e := range w.subqueue {
+		c.Found = append(c.Found, newCheckpointDir(e))
+	}
+	if e := w.visiting; e.d != nil && e.d.IsDir() {
+		c.Found = append(c.Found, newCheckpointDir(e))
+	}
+	return json.Marshal(c)
+}
+
+// Resume continues a walk from a snapshot taken with [Walker.Checkpoint],
+// calling fn for each file or directory that had not yet been visited.
+//
+// The snapshot only records paths, so fsys should be the file system that
+// was being walked when it was taken. Entries added to or removed from
+// directories that had not been read yet are picked up as usual.
+func (w *Walker) Resume(fsys fs.FS, state []byte, fn fs.WalkDirFunc) (err error) {
+	var c checkpoint
+	if err := json.Unmarshal(state, &c); err != nil {
+		return fmt.Errorf("bfwalk: invalid checkpoint: %w", err)
+	}
+	w.start()
+	defer func() { w.finish(err) }()
+	for _, dir := range c.Pending {
+		w.enqueue(dir.entry(fsys))
+	}
+
+	if c.Dir != nil {
+		var found []namedEntry
+		for _, dir := range c.Found {
+			found = append(found, dir.entry(fsys))
+		}
+		err = w.visitDir(fsys, c.Dir.entry(fsys), c.After, found, fn)
+	}
+	if err == nil {
+		err = w.walkDir(fsys, fn)
+	}
+	if err == fs.SkipDir || err == fs.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// ResumeWalk continues a walk from a snapshot taken with [Walker.Checkpoint]
+// with a new [Walker]. See [Walker.Resume] for details.
+func ResumeWalk(fsys fs.FS, state []byte, fn fs.WalkDirFunc) error {
+	return NewWalker().Resume(fsys, state, fn)
+}
+
+// pendingDir is the [fs.DirEntry] of a directory restored from a checkpoint.
+// Its file info is read from the file system on demand.
+type pendingDir struct {
+	fsys fs.FS
+	name string
+}
+
+func (d pendingDir) Name() string               { return path.Base(d.name) }
+func (d pendingDir) IsDir() bool                { return true }
+func (d pendingDir) Type() fs.FileMode          { return fs.ModeDir }
+func (d pendingDir) Info() (fs.FileInfo, error) { return stat(d.fsys, d.name) }
+func (d pendingDir) String() string             { return fs.FormatDirEntry(d) }
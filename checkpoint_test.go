@@ -0,0 +1,110 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":          {Data: []byte("")},
+		"root/dirA/file1.txt":     {Data: []byte("")},
+		"root/dirB/afile1.txt":    {Data: []byte("")},
+		"root/dirB/file1.txt":     {Data: []byte("")},
+		"root/dirB/sub/file1.txt": {Data: []byte("")},
+		"root/dirB/zfile1.txt":    {Data: []byte("")},
+	}
+
+	preferB := WithPriority(func(path string, d fs.DirEntry) int {
+		return strings.Count(path, "dirB")
+	})
+
+	// stop after every entry in turn and resume from there
+	for _, opts := range [][]Option{nil, {WithUnsorted()}, {preferB}} {
+		var full []string
+		err := NewWalker(opts...).WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+			full = append(full, path)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := 1; i < len(full); i++ {
+			var visited []string
+			var state []byte
+			w := NewWalker(opts...)
+			err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+				visited = append(visited, path)
+				if path == full[i] {
+					state, err = w.Checkpoint()
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					return fs.SkipAll
+				}
+				return err
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = NewWalker(opts...).Resume(memFS, state, func(path string, d fs.DirEntry, err error) error {
+				visited = append(visited, path)
+				return err
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(visited, full) {
+				t.Errorf("stopped at %s, expected:\n  %v\ngot\n: %v", full[i], full, visited)
+			}
+		}
+	}
+}
+
+func TestCheckpointResumeRelativePaths(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/dirA/file1.txt": {Data: []byte("")},
+		"root/dirB/file1.txt": {Data: []byte("")},
+	}
+
+	var state []byte
+	w := NewWalker(WithRelativePaths())
+	err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		if path == "dirA" {
+			state, err = w.Checkpoint()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return fs.SkipAll
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var visited []string
+	err = NewWalker(WithRelativePaths()).Resume(memFS, state, func(path string, d fs.DirEntry, err error) error {
+		visited = append(visited, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"dirB", "dirA/file1.txt", "dirB/file1.txt"}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+func TestResumeInvalid(t *testing.T) {
+	err := ResumeWalk(fstest.MapFS{}, []byte("not json"), func(path string, d fs.DirEntry, err error) error {
+		return err
+	})
+	if err == nil {
+		t.Errorf("expected error for invalid checkpoint")
+	}
+}
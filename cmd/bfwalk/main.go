@@ -0,0 +1,189 @@
+// Command bfwalk lists the file trees rooted at the given directories
+// breadth-first, one path per line.
+//
+// Usage:
+//
+//	bfwalk [flags] [dir ...]
+//
+// With no directories, bfwalk lists the current directory. The flags are:
+//
+//	-maxdepth n
+//		descend at most n levels below each directory
+//	-name pattern
+//		only list entries whose base name matches the glob pattern;
+//		may be repeated to match any of several patterns
+//	-type d|f
+//		only list directories (d) or non-directories (f)
+//	-hidden
+//		also list hidden files and directories
+//	-xdev
+//		do not descend into directories on other devices, such as
+//		mount points
+//	-unsorted
+//		list the entries of each directory in the order the file
+//		system returns them
+//	-json
+//		write each entry as a JSON object on its own line
+//	-0
+//		separate paths with NUL bytes instead of newlines
+//	-stats
+//		print walk statistics to standard error when done
+//
+// Errors reading entries are reported to standard error and the walk goes
+// on, as with find(1); bfwalk then exits with status 1.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/eriicafes/bfwalk"
+	"github.com/eriicafes/bfwalk/encode"
+	"github.com/eriicafes/bfwalk/osfs"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs bfwalk with the command line arguments args and returns its exit
+// status.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("bfwalk", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: bfwalk [flags] [dir ...]")
+		flags.PrintDefaults()
+	}
+	var names []string
+	maxDepth := flags.Int("maxdepth", -1, "descend at most `n` levels below each directory")
+	flags.Func("name", "only list entries whose base name matches the glob `pattern`", func(pattern string) error {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return err
+		}
+		names = append(names, pattern)
+		return nil
+	})
+	typ := flags.String("type", "", "only list directories (d) or non-directories (f)")
+	hidden := flags.Bool("hidden", false, "also list hidden files and directories")
+	xdev := flags.Bool("xdev", false, "do not descend into directories on other devices")
+	unsorted := flags.Bool("unsorted", false, "list entries in the order the file system returns them")
+	jsonOut := flags.Bool("json", false, "write each entry as a JSON object on its own line")
+	nul := flags.Bool("0", false, "separate paths with NUL bytes instead of newlines")
+	stats := flags.Bool("stats", false, "print walk statistics to standard error when done")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *typ != "" && *typ != "d" && *typ != "f" {
+		fmt.Fprintf(stderr, "bfwalk: invalid -type %q\n", *typ)
+		return 2
+	}
+	dirs := flags.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+	enc := encode.NewJSONLines(out, encode.Path, encode.Type, encode.Size, encode.Depth)
+	sep := byte('\n')
+	if *nul {
+		sep = 0
+	}
+
+	status := 0
+	var total bfwalk.Stats
+	for _, dir := range dirs {
+		opts := []bfwalk.Option{bfwalk.WithPathPrefix(dir)}
+		if *maxDepth >= 0 {
+			opts = append(opts, bfwalk.WithShouldDescend(func(name string, d fs.DirEntry) bool {
+				return depth(dir, name) < *maxDepth
+			}))
+		}
+		if !*hidden {
+			opts = append(opts, bfwalk.WithSkipHidden())
+		}
+		if *unsorted {
+			opts = append(opts, bfwalk.WithUnsorted())
+		}
+		var fsOpts []osfs.Option
+		if *xdev {
+			fsOpts = append(fsOpts, osfs.WithSameDevice())
+		}
+		w := bfwalk.NewWalker(opts...)
+		err := w.WalkDir(osfs.New(dir, fsOpts...), ".", func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				fmt.Fprintf(stderr, "bfwalk: %v\n", err)
+				status = 1
+				return nil
+			}
+			if !matches(d, *typ, names) {
+				return nil
+			}
+			if *jsonOut {
+				return enc.Encode(bfwalk.Entry{Path: name, DirEntry: d, Depth: depth(dir, name)})
+			}
+			if _, err := out.WriteString(name); err != nil {
+				return err
+			}
+			return out.WriteByte(sep)
+		})
+		if err != nil {
+			enc.Flush() // Keep the entries written before the error
+			fmt.Fprintf(stderr, "bfwalk: %v\n", err)
+			return 1
+		}
+		s := w.Stats()
+		total.Visited += s.Visited
+		total.Dirs += s.Dirs
+		total.Files += s.Files
+		total.Errors += s.Errors
+		total.ReadDirs += s.ReadDirs
+	}
+	if err := enc.Flush(); err != nil {
+		fmt.Fprintf(stderr, "bfwalk: %v\n", err)
+		return 1
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintf(stderr, "bfwalk: %v\n", err)
+		return 1
+	}
+	if *stats {
+		fmt.Fprintf(stderr, "%d visited, %d directories, %d files, %d errors, %d reads\n",
+			total.Visited, total.Dirs, total.Files, total.Errors, total.ReadDirs)
+	}
+	return status
+}
+
+// matches reports whether the entry d should be listed, given the -type
+// and -name flags.
+func matches(d fs.DirEntry, typ string, names []string) bool {
+	switch {
+	case typ == "d" && !d.IsDir(), typ == "f" && d.IsDir():
+		return false
+	case len(names) == 0:
+		return true
+	}
+	for _, pattern := range names {
+		if ok, _ := path.Match(pattern, d.Name()); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// depth returns the number of path elements of name below dir.
+func depth(dir, name string) int {
+	rel, err := filepath.Rel(dir, name)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"file1.txt",
+		".hidden/file1.txt",
+		"dirA/file1.go",
+		"dirA/sub/file1.txt",
+	} {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(name, []byte("hello"), 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	join := func(names ...string) []string {
+		for i, name := range names {
+			names[i] = filepath.Join(dir, filepath.FromSlash(name))
+		}
+		return names
+	}
+
+	tests := map[string]struct {
+		args     []string
+		expected []string
+	}{
+		"all": {
+			nil,
+			join(".", "dirA", "file1.txt", "dirA/file1.go", "dirA/sub", "dirA/sub/file1.txt"),
+		},
+		"maxdepth": {
+			[]string{"-maxdepth", "1"},
+			join(".", "dirA", "file1.txt"),
+		},
+		"name": {
+			[]string{"-name", "*.go", "-name", "sub"},
+			join("dirA/file1.go", "dirA/sub"),
+		},
+		"type": {
+			[]string{"-type", "d", "-hidden"},
+			join(".", ".hidden", "dirA", "dirA/sub"),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			if status := run(append(tt.args, dir), &stdout, &stderr); status != 0 {
+				t.Fatalf("exit status %d: %s", status, stderr.String())
+			}
+			got := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("expected:\n  %v\ngot\n: %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "file1.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var stdout, stderr strings.Builder
+	if status := run([]string{"-json", "-type", "f", "-stats", dir}, &stdout, &stderr); status != 0 {
+		t.Fatalf("exit status %d: %s", status, stderr.String())
+	}
+	var e struct {
+		Path  string `json:"path"`
+		Type  string `json:"type"`
+		Size  int64  `json:"size"`
+		Depth int    `json:"depth"`
+	}
+	if err := json.Unmarshal([]byte(stdout.String()), &e); err != nil {
+		t.Fatalf("invalid output %q: %v", stdout.String(), err)
+	}
+	if e.Path != filepath.Join(dir, "file1.txt") || e.Type != "file" || e.Size != 5 || e.Depth != 1 {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if !strings.HasPrefix(stderr.String(), "2 visited, 1 directories, 1 files") {
+		t.Errorf("unexpected stats %q", stderr.String())
+	}
+}
+
+func TestRunMissing(t *testing.T) {
+	var stdout, stderr strings.Builder
+	if status := run([]string{filepath.Join(t.TempDir(), "missing")}, &stdout, &stderr); status != 1 {
+		t.Errorf("expected exit status 1, got %d", status)
+	}
+	if stderr.Len() == 0 {
+		t.Errorf("expected error output")
+	}
+}
@@ -0,0 +1,110 @@
+package bfwalk
+
+import "io/fs"
+
+// WithMaxResults makes the Walker stop the walk once the walk callback has
+// been called n times, including calls that report errors, as if the last
+// call returned [fs.SkipAll]. The walk then returns nil unless the last call
+// returned an error. A limit of 0 or less means no limit.
+//
+// [Find] applies the limit to the number of matches instead.
+func WithMaxResults(n int) Option {
+	return func(w *Walker) {
+		w.maxResults = n
+	}
+}
+
+// Collect walks the file tree rooted at root with a [Walker] configured with
+// opts and returns the paths of every file and directory in the tree, in the
+// order they are visited. The first error encountered stops the walk and is
+// returned, together with the paths visited before it.
+func Collect(fsys fs.FS, root string, opts ...Option) ([]string, error) {
+	var paths []string
+	err := NewWalker(opts...).WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	return paths, err
+}
+
+// Find walks the file tree rooted at root with a [Walker] configured with
+// opts and returns the paths of the files and directories for which match
+// returns true, in the order they are visited. The first error encountered
+// stops the walk and is returned, together with the paths matched before it.
+//
+// With [WithMaxResults], the walk stops once n entries have matched, so
+// Find(fsys, root, match, WithMaxResults(1)) returns the shallowest match.
+func Find(fsys fs.FS, root string, match func(path string, d fs.DirEntry) bool, opts ...Option) ([]string, error) {
+	w := NewWalker(opts...)
+	limit := w.maxResults
+	w.maxResults = 0 // Limit matches, not visits
+
+	var paths []string
+	err := w.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if match(path, d) {
+			paths = append(paths, path)
+			if limit > 0 && len(paths) >= limit {
+				w.stopped(LimitReached)
+				return fs.SkipAll
+			}
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// Map walks the file tree rooted at root with a [Walker] configured with
+// opts and returns the values f returns for each file and directory in the
+// tree, in the order they are visited. Entries for which f returns false
+// are left out.
+//
+// The first error encountered, whether returned by f or reported by the
+// walk, stops the walk and is returned, together with the values gathered
+// before it.
+func Map[T any](fsys fs.FS, root string, f func(path string, d fs.DirEntry) (T, bool, error), opts ...Option) ([]T, error) {
+	var values []T
+	err := NewWalker(opts...).WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		v, ok, err := f(path, d)
+		if err != nil {
+			return err
+		}
+		if ok {
+			values = append(values, v)
+		}
+		return nil
+	})
+	return values, err
+}
+
+// Fold walks the file tree rooted at root with a [Walker] configured with
+// opts, calling f for each file and directory in the tree, in the order
+// they are visited, with the value f returned for the previous entry, or
+// init for the first. It returns the value f returned for the last entry.
+//
+// The first error encountered, whether returned by f or reported by the
+// walk, stops the walk and is returned, together with the value
+// accumulated before it.
+func Fold[A any](fsys fs.FS, root string, init A, f func(acc A, path string, d fs.DirEntry) (A, error), opts ...Option) (A, error) {
+	acc := init
+	err := NewWalker(opts...).WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		next, err := f(acc, path, d)
+		if err != nil {
+			return err
+		}
+		acc = next
+		return nil
+	})
+	return acc, err
+}
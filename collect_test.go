@@ -0,0 +1,205 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCollect(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":      {Data: []byte("")},
+		"root/dirA/file1.txt": {Data: []byte("")},
+		"root/dirB/file1.txt": {Data: []byte("")},
+	}
+
+	paths, err := Collect(memFS, "root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"root",
+		"root/dirA",
+		"root/dirB",
+		"root/file1.txt",
+		"root/dirA/file1.txt",
+		"root/dirB/file1.txt",
+	}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, paths)
+	}
+
+	paths, err = Collect(memFS, "root", WithMaxResults(3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(paths, expected[:3]) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected[:3], paths)
+	}
+
+	if _, err := Collect(memFS, "missing"); err == nil {
+		t.Errorf("expected error for missing root")
+	}
+}
+
+func TestFind(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/dirA/go.mod":     {Data: []byte("")},
+		"root/dirB/sub/go.mod": {Data: []byte("")},
+		"root/dirC/go.mod":     {Data: []byte("")},
+	}
+	isGoMod := func(p string, d fs.DirEntry) bool {
+		return path.Base(p) == "go.mod"
+	}
+
+	paths, err := Find(memFS, "root", isGoMod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"root/dirA/go.mod",
+		"root/dirC/go.mod",
+		"root/dirB/sub/go.mod",
+	}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, paths)
+	}
+
+	w := NewWalker()
+	paths, err = Find(memFS, "root", isGoMod, WithMaxResults(1), func(w2 *Walker) { w = w2 })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(paths, expected[:1]) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected[:1], paths)
+	}
+	if visited := w.Stats().Visited; visited != 5 {
+		t.Errorf("expected walk to stop after 5 entries, visited %d", visited)
+	}
+}
+
+func TestCollectPartial(t *testing.T) {
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/dirA/file1.txt": {Data: []byte("")},
+			"root/dirB/go.mod":    {Data: []byte("")},
+			"root/go.mod":         {Data: []byte("")},
+		},
+		errs: map[string]error{"root/dirB": fs.ErrPermission},
+	}
+
+	paths, err := Collect(fsys, "root")
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Fatalf("expected permission error, got %v", err)
+	}
+	expected := []string{"root", "root/dirA", "root/dirB", "root/go.mod", "root/dirA/file1.txt"}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, paths)
+	}
+
+	paths, err = Find(fsys, "root", func(p string, d fs.DirEntry) bool {
+		return path.Base(p) == "go.mod"
+	})
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Fatalf("expected permission error, got %v", err)
+	}
+	if !slices.Equal(paths, []string{"root/go.mod"}) {
+		t.Errorf("expected [root/go.mod], got %v", paths)
+	}
+
+	entries, err := CollectEntries(fsys, "root")
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Fatalf("expected permission error, got %v", err)
+	}
+	if len(entries) != len(expected) {
+		t.Errorf("expected %d entries, got %d", len(expected), len(entries))
+	}
+}
+
+func TestMap(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/a.txt":      {Data: []byte("a")},
+		"root/dirA/b.txt": {Data: []byte("bb")},
+		"root/dirA/c.go":  {Data: []byte("ccc")},
+	}
+	type file struct {
+		name string
+		size int64
+	}
+	txtSizes := func(p string, d fs.DirEntry) (file, bool, error) {
+		if path.Ext(p) != ".txt" {
+			return file{}, false, nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return file{}, false, err
+		}
+		return file{p, info.Size()}, true, nil
+	}
+
+	files, err := Map(memFS, "root", txtSizes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []file{{"root/a.txt", 1}, {"root/dirA/b.txt", 2}}
+	if !slices.Equal(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+
+	errBad := errors.New("bad file")
+	names, err := Map(memFS, "root", func(p string, d fs.DirEntry) (string, bool, error) {
+		if path.Ext(p) == ".go" {
+			return "", false, errBad
+		}
+		return path.Base(p), true, nil
+	})
+	if !errors.Is(err, errBad) {
+		t.Fatalf("expected %v, got %v", errBad, err)
+	}
+	if expected := []string{"root", "a.txt", "dirA", "b.txt"}; !slices.Equal(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestFold(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/a.txt":      {Data: []byte("a")},
+		"root/dirA/b.txt": {Data: []byte("bb")},
+		"root/dirA/c.go":  {Data: []byte("ccc")},
+	}
+	countByExt := func(acc map[string]int, p string, d fs.DirEntry) (map[string]int, error) {
+		if !d.IsDir() {
+			acc[path.Ext(p)]++
+		}
+		return acc, nil
+	}
+
+	counts, err := Fold(memFS, "root", map[string]int{}, countByExt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(counts) != 2 || counts[".txt"] != 2 || counts[".go"] != 1 {
+		t.Errorf("unexpected counts %v", counts)
+	}
+
+	depth, err := Fold(memFS, "root", 0, func(acc int, p string, d fs.DirEntry) (int, error) {
+		return max(acc, strings.Count(p, "/")), nil
+	}, WithMaxResults(3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if depth != 1 {
+		t.Errorf("expected depth 1 within 3 entries, got %d", depth)
+	}
+
+	total, err := Fold(fstest.MapFS{}, "missing", 10, func(acc int, p string, d fs.DirEntry) (int, error) {
+		return acc + 1, nil
+	})
+	if err == nil || total != 10 {
+		t.Errorf("expected error and initial value for missing root, got %d, %v", total, err)
+	}
+}
@@ -0,0 +1,151 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"sync"
+	"time"
+)
+
+// WithConcurrency makes the Walker read up to n queued directories at once,
+// ahead of visiting their entries, so that walks of slow file systems are
+// not bound by the latency of each read.
+//
+// The walk callback is still called from a single goroutine, one entry at a
+// time and in the same order as without concurrency. What is done as each
+// directory is read runs on the goroutines reading ahead, several at once:
+// the expressions of [WithPrune], the file filters of options such as
+// [WithMinSize], the reads of ignore files for [WithIgnoreFiles] and of
+// file contents for [WithDetectContentType] and [WithMIMEFilter], the
+// stats of entries with [WithStat], the functions of [WithRetry] and the
+// [Order]. These callbacks, like the file system, must therefore be safe
+// for concurrent use. [WithFilter] expressions are evaluated as entries
+// are visited, on the goroutine of the walk callback.
+//
+// When the callback returns [fs.SkipAll], or the walk stops for any other
+// reason, reads that have not started are cancelled, retries of reads in
+// progress are abandoned, no further callbacks are made, and the walk
+// returns once every read in progress has finished. Results of reads ahead
+// of the walk are discarded.
+//
+// Directories are only read ahead in breadth-first order: with
+// [WithPriority] or [WithStrategy] DFS, no directory is read ahead. An n of
+// one or less disables concurrency.
+func WithConcurrency(n int) Option {
+	return func(w *Walker) {
+		w.concurrency = n
+	}
+}
+
+// A prefetcher reads queued directories ahead of the walk.
+type prefetcher struct {
+	fsys  fs.FS
+	n     int               // most reads started ahead of the walk
+	reads map[string]*fetch // keyed by readKey
+	done  chan struct{}     // closed to cancel reads
+	wg    sync.WaitGroup
+}
+
+// A fetch is a directory read that may still be in progress.
+type fetch struct {
+	ready chan struct{} // closed once the read is done
+	readResult
+}
+
+// readResult is the result of reading a page of a directory.
+type readResult struct {
+	dirs    []fs.DirEntry
+	listed  int // entries read, before pruning and filtering
+	next    string
+	ignore  *ignoreRules
+	err     error
+	elapsed time.Duration
+}
+
+// startPrefetch starts reading directories ahead of the walk of fsys, if
+// the Walker is configured to.
+func (w *Walker) startPrefetch(fsys fs.FS) {
+	if w.concurrency <= 1 || w.priority != nil {
+		return
+	}
+	w.prefetch = &prefetcher{
+		fsys:  fsys,
+		n:     w.concurrency,
+		reads: make(map[string]*fetch),
+		done:  make(chan struct{}),
+	}
+}
+
+// stopPrefetch cancels the reads ahead of the walk and waits for those in
+// progress to finish.
+func (w *Walker) stopPrefetch() {
+	p := w.prefetch
+	if p == nil {
+		return
+	}
+	close(p.done)
+	p.wg.Wait()
+	w.prefetch = nil
+}
+
+// fill starts reading the directories at the head of the queue, so that up
+// to n reads are ahead of the walk.
+func (w *Walker) fill() {
+	p := w.prefetch
+	if p == nil {
+		return
+	}
+	for dir := range w.queue.all() {
+		if len(p.reads) >= p.n {
+			return
+		}
+		key := readKey(dir)
+		if _, ok := p.reads[key]; ok {
+			continue
+		}
+		f := &fetch{ready: make(chan struct{})}
+		p.reads[key] = f
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			defer close(f.ready)
+			select {
+			case <-p.done:
+				return
+			default:
+			}
+			f.readResult = w.fetch(p.fsys, dir, "")
+		}()
+	}
+}
+
+// prefetched returns the read of the first page of dir started ahead of the
+// walk, once it is done, or false if there is none.
+func (w *Walker) prefetched(dir namedEntry, token string) (readResult, bool) {
+	p := w.prefetch
+	if p == nil || token != "" {
+		return readResult{}, false
+	}
+	key := readKey(dir)
+	f, ok := p.reads[key]
+	if !ok {
+		return readResult{}, false
+	}
+	delete(p.reads, key)
+	<-f.ready
+	return f.readResult, true
+}
+
+// cancelled returns a channel that is closed once reads of the walk in
+// progress are cancelled. It returns nil, which is never closed, for walks
+// without concurrency.
+func (w *Walker) cancelled() <-chan struct{} {
+	if w.prefetch == nil {
+		return nil
+	}
+	return w.prefetch.done
+}
+
+// readKey identifies the directory dir among those queued.
+func readKey(dir namedEntry) string {
+	return dir.root + "\x00" + dir.name
+}
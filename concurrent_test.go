@@ -0,0 +1,183 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// slowFS delays each directory read and tracks the reads in progress.
+type slowFS struct {
+	fstest.MapFS
+	delay  time.Duration
+	active *atomic.Int64
+	most   *atomic.Int64
+}
+
+func newSlowFS(fsys fstest.MapFS, delay time.Duration) slowFS {
+	return slowFS{fsys, delay, new(atomic.Int64), new(atomic.Int64)}
+}
+
+func (f slowFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	n := f.active.Add(1)
+	defer f.active.Add(-1)
+	for {
+		most := f.most.Load()
+		if n <= most || f.most.CompareAndSwap(most, n) {
+			break
+		}
+	}
+	time.Sleep(f.delay)
+	return f.MapFS.ReadDir(name)
+}
+
+func visitAll(t *testing.T, fsys fs.FS, opts ...Option) []string {
+	t.Helper()
+	var paths []string
+	err := NewWalker(opts...).WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			paths = append(paths, "error "+path)
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return paths
+}
+
+func TestWithConcurrency(t *testing.T) {
+	fsys := generateFS(".", 8, 4).(fstest.MapFS)
+	expected := visitAll(t, fsys)
+
+	for _, n := range []int{0, 1, 2, 4, 16} {
+		sfs := newSlowFS(fsys, time.Millisecond)
+		visited := visitAll(t, sfs, WithConcurrency(n))
+		if !slices.Equal(visited, expected) {
+			t.Errorf("WithConcurrency(%d): expected:\n  %v\ngot\n: %v", n, expected, visited)
+		}
+		if most := sfs.most.Load(); most > int64(max(n, 1)) {
+			t.Errorf("WithConcurrency(%d): %d reads at once", n, most)
+		}
+		if n >= 4 && sfs.most.Load() < 2 {
+			t.Errorf("WithConcurrency(%d): directories were not read concurrently", n)
+		}
+	}
+}
+
+func TestWithConcurrencyErrors(t *testing.T) {
+	base := generateFS(".", 4, 3).(fstest.MapFS)
+	fsys := errFS{base, map[string]error{
+		"dir1_0":             fs.ErrPermission,
+		"dir2_0/dir2_1":      fs.ErrPermission,
+		"dir3_0/dir3_1/dir3": fs.ErrNotExist,
+	}}
+	expected := visitAll(t, fsys)
+	visited := visitAll(t, fsys, WithConcurrency(4))
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+func TestWithConcurrencySkipAll(t *testing.T) {
+	fsys := generateFS(".", 6, 3).(fstest.MapFS)
+	all := visitAll(t, fsys)
+
+	for stop := range all {
+		sfs := newSlowFS(fsys, 100*time.Microsecond)
+		var visited []string
+		var after atomic.Int64
+		stopped := false
+		err := NewWalker(WithConcurrency(4)).WalkDir(sfs, ".", func(path string, d fs.DirEntry, err error) error {
+			if stopped {
+				after.Add(1)
+			}
+			visited = append(visited, path)
+			if len(visited) == stop+1 {
+				stopped = true
+				return fs.SkipAll
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("stop at %d: unexpected error: %v", stop, err)
+		}
+		if n := after.Load(); n > 0 {
+			t.Fatalf("stop at %d: %d callbacks after SkipAll", stop, n)
+		}
+		if expected := all[:stop+1]; !slices.Equal(visited, expected) {
+			t.Fatalf("stop at %d: expected:\n  %v\ngot\n: %v", stop, expected, visited)
+		}
+		if n := sfs.active.Load(); n != 0 {
+			t.Fatalf("stop at %d: %d reads in progress after WalkDir returned", stop, n)
+		}
+	}
+}
+
+// TestWithConcurrencySkipAllStress stops many concurrent walks at random
+// points; run it with -race.
+func TestWithConcurrencySkipAllStress(t *testing.T) {
+	fsys := generateFS(".", 12, 4).(fstest.MapFS)
+	all := visitAll(t, fsys)
+	w := NewWalker(WithConcurrency(8), WithStat())
+
+	for i := range 200 {
+		stop := (i * 7919) % len(all)
+		var visited int
+		err := w.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			visited++
+			if visited > stop+1 {
+				t.Fatalf("walk %d: callback after SkipAll", i)
+			}
+			if visited == stop+1 {
+				return fs.SkipAll
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("walk %d: unexpected error: %v", i, err)
+		}
+		if s := w.Stats(); s.InFlight != 0 {
+			t.Fatalf("walk %d: %d reads in flight after WalkDir returned", i, s.InFlight)
+		}
+	}
+}
+
+func TestWithConcurrencySkipAllRetry(t *testing.T) {
+	fsys := &flakyFS{
+		MapFS: fstest.MapFS{
+			"a/file": {},
+			"b/file": {},
+		},
+		name:     "b",
+		failures: 1000,
+		err:      errors.New("unavailable"),
+	}
+	w := NewWalker(
+		WithConcurrency(4),
+		WithRetry(1000, func(int) time.Duration { return time.Hour }, nil),
+	)
+	done := make(chan error)
+	go func() {
+		done <- w.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			if path == "a/file" {
+				return fs.SkipAll
+			}
+			return nil
+		})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("WalkDir did not abandon retries after SkipAll")
+	}
+}
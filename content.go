@@ -0,0 +1,80 @@
+package bfwalk
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+)
+
+// sniffLen is the number of bytes [http.DetectContentType] considers.
+const sniffLen = 512
+
+// A WalkDirContentFunc is the type of the function called by
+// [Walker.WalkDirContent] for each file or directory visited. It behaves
+// like an [fs.WalkDirFunc], and is passed the content type of regular files
+// as detected by [http.DetectContentType].
+//
+// contentType is empty for directories and other non-regular files, for
+// files that could not be read, and when the Walker was not created with
+// [WithDetectContentType].
+type WalkDirContentFunc func(path string, d fs.DirEntry, contentType string, err error) error
+
+// WithDetectContentType makes the Walker read the first 512 bytes of every
+// regular file as each directory is read, and detect the content type of the
+// file from them with [http.DetectContentType], for [Walker.WalkDirContent].
+//
+// Like [WithStat], files of large directories are read concurrently, and
+// with [WithConcurrency] files are read ahead of the walk together with
+// their directory. Files that cannot be opened or read are given no content
+// type.
+func WithDetectContentType() Option {
+	return func(w *Walker) {
+		w.sniff = true
+	}
+}
+
+// WalkDirContent walks the file tree rooted at root like [Walker.WalkDir],
+// passing fn the content type of each regular file detected with
+// [WithDetectContentType].
+func (w *Walker) WalkDirContent(fsys fs.FS, root string, fn WalkDirContentFunc) error {
+	return w.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		return fn(path, d, contentType(d), err)
+	})
+}
+
+// contentType returns the content type detected for d, if any.
+func contentType(d fs.DirEntry) string {
+	if c, ok := d.(*CachedEntry); ok {
+		return c.contentType
+	}
+	return ""
+}
+
+// sniffEntries detects the content type of each regular file among dirs,
+// the [CachedEntry] values of the directory dir, calling throttle before
+// each file is read.
+func sniffEntries(fsys fs.FS, dir string, dirs []fs.DirEntry, throttle func()) {
+	eachEntry(dirs, func(d fs.DirEntry) {
+		if d.Type().IsRegular() {
+			throttle()
+			sniff(fsys, path.Join(dir, d.Name()), d.(*CachedEntry))
+		}
+	})
+}
+
+// sniff detects the content type of the file name from its first bytes and
+// records it in e.
+func sniff(fsys fs.FS, name string, e *CachedEntry) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	var buf [sniffLen]byte
+	n, err := io.ReadFull(f, buf[:])
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return
+	}
+	e.contentType = http.DetectContentType(buf[:n])
+}
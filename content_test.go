@@ -0,0 +1,75 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"maps"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithDetectContentType(t *testing.T) {
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/index.html":     {Data: []byte("<!DOCTYPE html><html></html>")},
+			"root/logo.png":       {Data: []byte("\x89PNG\x0D\x0A\x1A\x0A rest of image")},
+			"root/empty":          {},
+			"root/bad.txt":        {Data: []byte("text")},
+			"root/link":           {Mode: fs.ModeSymlink},
+			"root/dir/notes.txt":  {Data: []byte("plain notes\n")},
+			"root/dir/data.gz":    {Data: []byte("\x1f\x8b\x08 compressed")},
+			"root/dir/sub/x.json": {Data: []byte(`{"a": 1}`)},
+		},
+		errs: map[string]error{"root/bad.txt": fs.ErrPermission},
+	}
+	expected := map[string]string{
+		"root":                "",
+		"root/index.html":     "text/html; charset=utf-8",
+		"root/logo.png":       "image/png",
+		"root/empty":          "text/plain; charset=utf-8",
+		"root/bad.txt":        "",
+		"root/link":           "",
+		"root/dir":            "",
+		"root/dir/notes.txt":  "text/plain; charset=utf-8",
+		"root/dir/data.gz":    "application/x-gzip",
+		"root/dir/sub":        "",
+		"root/dir/sub/x.json": "text/plain; charset=utf-8",
+	}
+
+	for _, opts := range [][]Option{
+		{WithDetectContentType()},
+		{WithDetectContentType(), WithConcurrency(4), WithStat()},
+	} {
+		got := make(map[string]string)
+		err := NewWalker(opts...).WalkDirContent(fsys, "root", func(path string, d fs.DirEntry, contentType string, err error) error {
+			if err != nil {
+				return err
+			}
+			got[path] = contentType
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !maps.Equal(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	}
+
+	// The root is sniffed when it is a file
+	var root string
+	NewWalker(WithDetectContentType()).WalkDirContent(fsys, "root/logo.png", func(path string, d fs.DirEntry, contentType string, err error) error {
+		root = contentType
+		return err
+	})
+	if root != "image/png" {
+		t.Errorf("expected root content type image/png, got %q", root)
+	}
+
+	// Without the option no file is read
+	NewWalker().WalkDirContent(fsys, "root", func(path string, d fs.DirEntry, contentType string, err error) error {
+		if contentType != "" {
+			t.Errorf("%s: expected no content type without WithDetectContentType, got %q", path, contentType)
+		}
+		return err
+	})
+}
@@ -0,0 +1,36 @@
+package bfwalk
+
+import (
+	"context"
+	"io/fs"
+)
+
+// A WalkDirContextFunc is the type of the function called by
+// [WalkDirContext] for each file or directory visited. It behaves like an
+// [fs.WalkDirFunc], and is passed the context of the walk so that work done
+// for each entry can honor its cancellation and values.
+type WalkDirContextFunc func(ctx context.Context, path string, d fs.DirEntry, err error) error
+
+// WalkDirContext walks the file tree rooted at root like [WalkDir], passing
+// ctx to fn for each file or directory in the tree.
+//
+// The walk stops with the error of ctx once ctx is done. Like the deadline
+// set with [WithTimeout], ctx is checked before each directory is read, so
+// fn should check ctx itself for work that may block.
+func WalkDirContext(ctx context.Context, fsys fs.FS, root string, fn WalkDirContextFunc) error {
+	return NewWalker().WalkDirContext(ctx, fsys, root, fn)
+}
+
+// WalkDirContext walks the file tree rooted at root like the package-level
+// [WalkDirContext] function, with the traversal adjusted by the options the
+// Walker was created with.
+func (w *Walker) WalkDirContext(ctx context.Context, fsys fs.FS, root string, fn WalkDirContextFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	w.ctx = ctx
+	defer func() { w.ctx = nil }()
+	return w.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		return fn(w.ctx, path, d, err) // Replaced by observers as the walk starts
+	})
+}
@@ -0,0 +1,55 @@
+package bfwalk
+
+import (
+	"context"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+type ctxKey struct{}
+
+func TestWalkDirContext(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":      {Data: []byte("")},
+		"root/dirA/file1.txt": {Data: []byte("")},
+		"root/dirB/file1.txt": {Data: []byte("")},
+	}
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "value"))
+	defer cancel()
+	var visited []string
+	err := WalkDirContext(ctx, memFS, "root", func(ctx context.Context, path string, d fs.DirEntry, err error) error {
+		if v := ctx.Value(ctxKey{}); v != "value" {
+			t.Errorf("expected context value, got %v", v)
+		}
+		visited = append(visited, path)
+		if path == "root/dirA" {
+			cancel()
+		}
+		return err
+	})
+	if err != context.Canceled {
+		t.Errorf("expected error %v, got %v", context.Canceled, err)
+	}
+
+	// the directory being visited is finished before the walk stops
+	expected := []string{
+		"root",
+		"root/dirA",
+		"root/dirB",
+		"root/file1.txt",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+
+	err = WalkDirContext(ctx, memFS, "root", func(ctx context.Context, path string, d fs.DirEntry, err error) error {
+		t.Errorf("unexpected visit of %s", path)
+		return err
+	})
+	if err != context.Canceled {
+		t.Errorf("expected error %v, got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,68 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"path"
+)
+
+// A WriteFS is a file system that can be written to, as used by [Copy].
+// Names are slash-separated paths, as for [fs.FS]; package osfs provides an
+// implementation backed by a directory of the operating system.
+type WriteFS interface {
+	// MkdirAll creates the directory name along with any missing parents,
+	// doing nothing if it already exists.
+	MkdirAll(name string, perm fs.FileMode) error
+
+	// WriteFile writes data to the file name, creating it if needed and
+	// truncating it otherwise.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+// Copy walks the file tree rooted at root in src with a [Walker] configured
+// with opts and recreates it under the same names in dst. The tree is
+// copied breadth-first, so the directories of each level are created before
+// any of their contents.
+//
+// Regular files are copied with their permission bits, and directories with
+// theirs plus owner read, write and search permission, so that their
+// contents can be written. Entries of other types, such as symbolic links,
+// are not copied. Options that change reported paths, such as
+// [WithRelativePaths] and [WithPathPrefix], are ignored, and with
+// [WithNormalizePaths], files are read and written by the names the file
+// system lists, while filters match normalized names. The first error
+// encountered stops the copy and is returned.
+func Copy(dst WriteFS, src fs.FS, root string, opts ...Option) error {
+	w := NewWalker(opts...)
+	w.relative, w.prefix, w.rawPaths = false, "", true
+	return w.WalkDir(src, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return copyEntry(dst, src, name, d, name == root)
+	})
+}
+
+// copyEntry copies the entry d named name from src to dst, creating its
+// parent directories first if it is the root of the copy.
+func copyEntry(dst WriteFS, src fs.FS, name string, d fs.DirEntry, isRoot bool) error {
+	if !d.IsDir() && !d.Type().IsRegular() {
+		return nil
+	}
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+	if d.IsDir() {
+		return dst.MkdirAll(name, info.Mode().Perm()|0o700)
+	}
+	if isRoot && path.Dir(name) != "." {
+		if err := dst.MkdirAll(path.Dir(name), 0o755); err != nil {
+			return err
+		}
+	}
+	data, err := fs.ReadFile(src, name)
+	if err != nil {
+		return err
+	}
+	return dst.WriteFile(name, data, info.Mode().Perm())
+}
@@ -0,0 +1,111 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"path"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// memWriteFS is a WriteFS over a MapFS that records the operations done.
+type memWriteFS struct {
+	fstest.MapFS
+	ops *[]string
+}
+
+func newMemWriteFS() memWriteFS {
+	return memWriteFS{fstest.MapFS{}, new([]string)}
+}
+
+func (m memWriteFS) MkdirAll(name string, perm fs.FileMode) error {
+	*m.ops = append(*m.ops, "mkdir "+name)
+	for ; name != "."; name = path.Dir(name) {
+		if _, ok := m.MapFS[name]; !ok {
+			m.MapFS[name] = &fstest.MapFile{Mode: fs.ModeDir | perm}
+		}
+	}
+	return nil
+}
+
+func (m memWriteFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	*m.ops = append(*m.ops, "write "+name)
+	if info, err := fs.Stat(m.MapFS, path.Dir(name)); err != nil || !info.IsDir() {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrNotExist}
+	}
+	m.MapFS[name] = &fstest.MapFile{Data: data, Mode: perm}
+	return nil
+}
+
+func TestCopy(t *testing.T) {
+	src := fstest.MapFS{
+		"root/file1.txt":          {Data: []byte("one"), Mode: 0o600},
+		"root/dirA/file2.txt":     {Data: []byte("two"), Mode: 0o644},
+		"root/dirB/sub/file3.txt": {Data: []byte("three"), Mode: 0o644},
+		"root/dirB/link":          {Data: []byte("file3.txt"), Mode: fs.ModeSymlink},
+		"other/file.txt":          {Data: []byte("other")},
+	}
+	dst := newMemWriteFS()
+	if err := Copy(dst, src, "root", WithRelativePaths()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"mkdir root",
+		"mkdir root/dirA",
+		"mkdir root/dirB",
+		"write root/file1.txt",
+		"write root/dirA/file2.txt",
+		"mkdir root/dirB/sub",
+		"write root/dirB/sub/file3.txt",
+	}
+	if !slices.Equal(*dst.ops, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, *dst.ops)
+	}
+	changes, err := Diff(src, dst.MapFS, "root", DiffContents())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Kind != Removed || changes[0].Path != "root/dirB/link" {
+		t.Errorf("expected only the link to differ, got %v", changes)
+	}
+	if m := dst.MapFS["root/file1.txt"].Mode; m != 0o600 {
+		t.Errorf("expected mode 0600, got %v", m)
+	}
+}
+
+func TestCopyFile(t *testing.T) {
+	src := fstest.MapFS{"a/b/file.txt": {Data: []byte("data")}}
+	dst := newMemWriteFS()
+	if err := Copy(dst, src, "a/b/file.txt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f := dst.MapFS["a/b/file.txt"]; f == nil || string(f.Data) != "data" {
+		t.Errorf("expected file to be copied, got %v", dst.MapFS)
+	}
+
+	if err := Copy(dst, src, "missing"); err == nil {
+		t.Error("expected error for missing root")
+	}
+}
+
+func TestCopyNormalizePaths(t *testing.T) {
+	cafe := "cafe\u0301"
+	src := fstest.MapFS{
+		"root/" + cafe + "/menu.txt": {Data: []byte("menu")},
+		"root/other/file.txt":        {Data: []byte("other")},
+	}
+	dst := newMemWriteFS()
+	err := Copy(dst, src, "root", WithNormalizePaths(norm.NFC), WithInclude("café/*"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f := dst.MapFS["root/"+cafe+"/menu.txt"]; f == nil || string(f.Data) != "menu" {
+		t.Errorf("expected file to be copied by its listed name, got %v", dst.MapFS)
+	}
+	if _, ok := dst.MapFS["root/other/file.txt"]; ok {
+		t.Error("expected excluded file not to be copied")
+	}
+}
@@ -0,0 +1,52 @@
+package bfwalk
+
+import (
+	"context"
+	"time"
+)
+
+// ErrDeadlineExceeded is returned by a walk that runs past the deadline set
+// with [WithTimeout] or [WithDeadline]. It matches [context.DeadlineExceeded]
+// with [errors.Is].
+var ErrDeadlineExceeded error = deadlineExceededError{}
+
+type deadlineExceededError struct{}
+
+func (deadlineExceededError) Error() string        { return "bfwalk: deadline exceeded" }
+func (deadlineExceededError) Timeout() bool        { return true }
+func (deadlineExceededError) Is(target error) bool { return target == context.DeadlineExceeded }
+
+// WithTimeout makes walks stop with [ErrDeadlineExceeded] once they have run
+// for longer than d. The deadline is checked before each directory is read,
+// so a slow callback or directory read may overrun it.
+func WithTimeout(d time.Duration) Option {
+	return func(w *Walker) {
+		w.timeout, w.deadline = d, time.Time{}
+	}
+}
+
+// WithDeadline makes walks stop with [ErrDeadlineExceeded] once t has
+// passed. The deadline is checked before each directory is read, so a slow
+// callback or directory read may overrun it.
+func WithDeadline(t time.Time) Option {
+	return func(w *Walker) {
+		w.timeout, w.deadline = 0, t
+	}
+}
+
+// interrupted returns the error that stops the walk in progress once its
+// deadline has passed or its context is done, or nil.
+func (w *Walker) interrupted() error {
+	if w.expired() {
+		return ErrDeadlineExceeded
+	}
+	if w.ctx != nil {
+		return w.ctx.Err()
+	}
+	return nil
+}
+
+// expired reports whether the deadline of the walk in progress has passed.
+func (w *Walker) expired() bool {
+	return !w.until.IsZero() && !time.Now().Before(w.until)
+}
@@ -0,0 +1,55 @@
+package bfwalk
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestWithTimeout(t *testing.T) {
+	fsys := generateFS("data", 10, 3)
+
+	var visited int
+	err := NewWalker(WithTimeout(20*time.Millisecond)).WalkDir(fsys, "data", func(path string, d fs.DirEntry, err error) error {
+		visited++
+		if path == "data/dir1_0" {
+			time.Sleep(30 * time.Millisecond)
+		}
+		return err
+	})
+	if err != ErrDeadlineExceeded {
+		t.Fatalf("expected %v, got %v", ErrDeadlineExceeded, err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error to match context.DeadlineExceeded")
+	}
+	if visited != 11 {
+		t.Errorf("expected the walk to stop after the first level, visited %d", visited)
+	}
+
+	// the timeout applies to each walk
+	w := NewWalker(WithTimeout(time.Hour))
+	for range 2 {
+		if err := w.WalkDir(fsys, "data", func(path string, d fs.DirEntry, err error) error { return err }); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestWithDeadline(t *testing.T) {
+	fsys := generateFS("data", 10, 3)
+
+	var visited int
+	err := NewWalker(WithDeadline(time.Now().Add(-time.Second))).WalkDir(fsys, "data", func(path string, d fs.DirEntry, err error) error {
+		visited++
+		return err
+	})
+	if err != ErrDeadlineExceeded {
+		t.Fatalf("expected %v, got %v", ErrDeadlineExceeded, err)
+	}
+	if visited != 1 {
+		t.Errorf("expected only the root to be visited, visited %d", visited)
+	}
+}
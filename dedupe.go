@@ -0,0 +1,31 @@
+package bfwalk
+
+// WithDedupePaths makes the Walker pass each path to the walk callback at
+// most once, so that walks of overlapping roots, such as "a" and "a/b" with
+// [Walker.WalkDirs], and of file systems that list an entry more than once
+// do not report it twice. A directory that was already visited is not
+// walked again.
+//
+// Paths are compared as names in the file system walked, before
+// [WithRelativePaths] and [WithPathPrefix] apply, and every path visited is
+// kept in memory until the walk ends. Paths visited before a checkpoint
+// was taken are not remembered when resuming from it. To visit files
+// reached through several hard links once, use [WithDedupeHardlinks].
+func WithDedupePaths() Option {
+	return func(w *Walker) {
+		w.dedupePaths = true
+	}
+}
+
+// isPathSeen reports whether the file named name was visited before,
+// recording it otherwise.
+func (w *Walker) isPathSeen(name string) bool {
+	if w.seen[name] {
+		return true
+	}
+	if w.seen == nil {
+		w.seen = make(map[string]bool)
+	}
+	w.seen[name] = true
+	return false
+}
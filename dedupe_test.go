@@ -0,0 +1,82 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// repeatFS is a file system that lists every entry of its directories twice.
+type repeatFS struct {
+	fstest.MapFS
+}
+
+func (f repeatFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	dirs, err := f.MapFS.ReadDir(name)
+	return append(dirs, dirs...), err
+}
+
+func TestWithDedupePaths(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a/b/c/file": {},
+		"a/b/other":  {},
+		"a/top":      {},
+	}
+	walk := func(fsys fs.FS, roots []string, opts ...Option) []string {
+		t.Helper()
+		var paths []string
+		err := NewWalker(opts...).WalkDirs(fsys, roots, func(path string, d fs.DirEntry, err error) error {
+			paths = append(paths, path)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return paths
+	}
+
+	roots := []string{"a/b", "a", "a/b"}
+	got := walk(fsys, roots, WithDedupePaths())
+	expected := []string{"a/b", "a", "a/b/c", "a/b/other", "a/top", "a/b/c/file"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("overlapping roots: expected %v, got %v", expected, got)
+	}
+	if got := walk(fsys, roots); len(got) <= len(expected) {
+		t.Errorf("expected duplicates without WithDedupePaths, got %v", got)
+	}
+
+	got = walk(repeatFS{fsys}, []string{"a"}, WithDedupePaths())
+	expected = []string{"a", "a/b", "a/top", "a/b/c", "a/b/other", "a/b/c/file"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("repeated entries: expected %v, got %v", expected, got)
+	}
+
+	var paths []string
+	err := NewWalker(WithDedupePaths()).IDWalk(repeatFS{fsys}, "a", func(path string, d fs.DirEntry, err error) error {
+		paths = append(paths, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("repeated entries with IDWalk: expected %v, got %v", expected, paths)
+	}
+
+	// Each walk starts afresh
+	w := NewWalker(WithDedupePaths())
+	for range 2 {
+		var paths []string
+		err := w.WalkDir(fsys, "a/b", func(path string, d fs.DirEntry, err error) error {
+			paths = append(paths, path)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(paths) != 4 {
+			t.Errorf("expected 4 paths, got %v", paths)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package bfwalk
+
+import "iter"
+
+// minDeque is the smallest ring buffer a deque allocates, and below which it
+// does not shrink.
+const minDeque = 16
+
+// A deque is a queue kept in a ring buffer. Values popped from the front
+// free room for values pushed at the back, so that a queue that is drained
+// as it grows, like the directories of a breadth-first walk, reuses the
+// same buffer instead of stranding popped values and reallocating. The
+// buffer doubles when full and halves when a quarter full. The zero deque
+// is empty and ready to use.
+type deque[T any] struct {
+	buf  []T // ring buffer, whose length is zero or a power of two
+	head int // index of the front value in buf
+	n    int // number of values
+}
+
+// len returns the number of values in q.
+func (q *deque[T]) len() int {
+	return q.n
+}
+
+// pushBack adds vs to the back of q, in order.
+func (q *deque[T]) pushBack(vs ...T) {
+	if need := q.n + len(vs); need > len(q.buf) {
+		size := max(len(q.buf), minDeque)
+		for size < need {
+			size *= 2
+		}
+		q.resize(size)
+	}
+	for _, v := range vs {
+		q.buf[(q.head+q.n)&(len(q.buf)-1)] = v
+		q.n++
+	}
+}
+
+// popFront removes and returns the value at the front of q, reporting false
+// if q is empty.
+func (q *deque[T]) popFront() (T, bool) {
+	var zero T
+	if q.n == 0 {
+		return zero, false
+	}
+	v := q.buf[q.head]
+	q.buf[q.head] = zero // Release references held by the value
+	q.head = (q.head + 1) & (len(q.buf) - 1)
+	q.n--
+	q.shrink()
+	return v, true
+}
+
+// at returns the value i places from the front of q.
+func (q *deque[T]) at(i int) T {
+	if i < 0 || i >= q.n {
+		panic("bfwalk: deque index out of range")
+	}
+	return q.buf[(q.head+i)&(len(q.buf)-1)]
+}
+
+// all returns an iterator over the values of q, from front to back. q must
+// not be changed during the iteration.
+func (q *deque[T]) all() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := range q.n {
+			if !yield(q.at(i)) {
+				return
+			}
+		}
+	}
+}
+
+// slice returns a copy of the values of q, from front to back.
+func (q *deque[T]) slice() []T {
+	s := make([]T, q.n)
+	q.copyTo(s)
+	return s
+}
+
+// copyTo copies the values of q, from front to back, to the start of s.
+func (q *deque[T]) copyTo(s []T) {
+	if q.n == 0 {
+		return
+	}
+	n := copy(s, q.buf[q.head:min(q.head+q.n, len(q.buf))])
+	copy(s[n:], q.buf[:q.n-n])
+}
+
+// shrink halves the buffer of q if it is at most a quarter full.
+func (q *deque[T]) shrink() {
+	if len(q.buf) > minDeque && q.n <= len(q.buf)/4 {
+		q.resize(len(q.buf) / 2)
+	}
+}
+
+// resize moves the values of q to a new buffer of the given size, a power of
+// two of at least q.len().
+func (q *deque[T]) resize(size int) {
+	buf := make([]T, size)
+	q.copyTo(buf)
+	q.buf, q.head = buf, 0
+}
@@ -0,0 +1,66 @@
+package bfwalk
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	var q deque[int]
+	var expected []int // Model of q
+	check := func(step string) {
+		t.Helper()
+		if got := q.slice(); !slices.Equal(got, expected) || q.len() != len(expected) {
+			t.Fatalf("%s: expected %v, got %v (len %d)", step, expected, got, q.len())
+		}
+		if got := slices.Collect(q.all()); !slices.Equal(got, expected) {
+			t.Fatalf("%s: all: expected %v, got %v", step, expected, got)
+		}
+	}
+
+	check("empty")
+	if _, ok := q.popFront(); ok {
+		t.Fatal("popFront of empty deque succeeded")
+	}
+
+	// Drain from the front while pushing at the back, wrapping around the
+	// buffer many times.
+	next := 0
+	for round := range 100 {
+		for range round%7 + 1 {
+			q.pushBack(next)
+			expected = append(expected, next)
+			next++
+		}
+		for range round % 5 {
+			v, ok := q.popFront()
+			if !ok || v != expected[0] {
+				t.Fatalf("round %d: popFront: expected %d, got %d, %v", round, expected[0], v, ok)
+			}
+			expected = expected[1:]
+		}
+		check("round")
+	}
+	if len(q.buf) > 4*max(len(expected), minDeque) {
+		t.Errorf("buffer of %d values holds %d", len(q.buf), len(expected))
+	}
+
+	q.pushBack(expected[:3]...)
+	expected = append(expected, expected[:3]...)
+	check("pushBack")
+	if got := q.at(2); got != expected[2] {
+		t.Errorf("at(2): expected %d, got %d", expected[2], got)
+	}
+
+	for len(expected) > 0 {
+		v, ok := q.popFront()
+		if !ok || v != expected[0] {
+			t.Fatalf("popFront: expected %d, got %d, %v", expected[0], v, ok)
+		}
+		expected = expected[1:]
+	}
+	check("drained")
+	if len(q.buf) > minDeque {
+		t.Errorf("drained deque kept a buffer of %d values", len(q.buf))
+	}
+}
@@ -0,0 +1,282 @@
+package bfwalk
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+)
+
+// A ChangeKind describes how an entry differs between two file trees.
+type ChangeKind int
+
+const (
+	Added    ChangeKind = iota + 1 // entry only exists in the second tree
+	Removed                        // entry only exists in the first tree
+	Modified                       // entry exists in both trees but differs
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	}
+	return "unknown"
+}
+
+// A Change is a difference between two file trees reported by [Diff].
+type Change struct {
+	Kind ChangeKind
+	Path string
+	A    fs.DirEntry // entry in the first tree, nil if Added
+	B    fs.DirEntry // entry in the second tree, nil if Removed
+}
+
+// A DiffOption configures [Diff].
+type DiffOption func(*diffConfig)
+
+type diffConfig struct {
+	contents bool
+}
+
+// DiffContents makes [Diff] compare the contents of files of the same size
+// instead of their modification times, and the targets of symbolic links on
+// file systems with a ReadLink method, as [fs.ReadLinkFS] in Go 1.25. Other
+// files of the same type and size are the same.
+func DiffContents() DiffOption {
+	return func(c *diffConfig) {
+		c.contents = true
+	}
+}
+
+// diffEntry is a path queued by [Diff] with the directory it names in each
+// tree, or nil where the path is not a directory.
+type diffEntry struct {
+	name string
+	a, b fs.DirEntry
+}
+
+// Diff walks the file trees rooted at root in fsysA and fsysB in lockstep,
+// breadth-first, and returns the changes that turn the first tree into the
+// second, in breadth-first order.
+//
+// Entries inside added or removed directories are reported individually.
+// Files are modified if their type, size or modification time differ, or
+// with [DiffContents], if their type, size or contents differ. Directories
+// are modified only if their type differs.
+//
+// If root does not exist in one of the trees, every entry of the other is
+// reported. The first error encountered reading either tree stops the walk
+// and is returned.
+func Diff(fsysA, fsysB fs.FS, root string, opts ...DiffOption) ([]Change, error) {
+	var c diffConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	a, err := statEntry(fsysA, root)
+	if err != nil {
+		return nil, err
+	}
+	b, err := statEntry(fsysB, root)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil && b == nil {
+		return nil, &fs.PathError{Op: "diff", Path: root, Err: fs.ErrNotExist}
+	}
+
+	var changes []Change
+	var queue deque[diffEntry]
+	compare := func(name string, a, b fs.DirEntry) error {
+		kind, err := c.compare(fsysA, fsysB, name, a, b)
+		if err != nil {
+			return err
+		}
+		if kind != 0 {
+			changes = append(changes, Change{kind, name, a, b})
+		}
+		e := diffEntry{name: name}
+		if a != nil && a.IsDir() {
+			e.a = a
+		}
+		if b != nil && b.IsDir() {
+			e.b = b
+		}
+		if e.a != nil || e.b != nil {
+			queue.pushBack(e)
+		}
+		return nil
+	}
+	if err := compare(root, a, b); err != nil {
+		return nil, err
+	}
+
+	for {
+		e, ok := queue.popFront()
+		if !ok {
+			break
+		}
+
+		as, err := readDirIf(fsysA, e.name, e.a)
+		if err != nil {
+			return nil, err
+		}
+		bs, err := readDirIf(fsysB, e.name, e.b)
+		if err != nil {
+			return nil, err
+		}
+		// Merge the sorted listings
+		for len(as) > 0 || len(bs) > 0 {
+			var a, b fs.DirEntry
+			switch {
+			case len(bs) == 0 || len(as) > 0 && as[0].Name() < bs[0].Name():
+				a, as = as[0], as[1:]
+			case len(as) == 0 || bs[0].Name() < as[0].Name():
+				b, bs = bs[0], bs[1:]
+			default:
+				a, as = as[0], as[1:]
+				b, bs = bs[0], bs[1:]
+			}
+			name := a
+			if name == nil {
+				name = b
+			}
+			if err := compare(path.Join(e.name, name.Name()), a, b); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return changes, nil
+}
+
+// compare returns how the entry name differs between the trees, or 0 if it
+// does not.
+func (c diffConfig) compare(fsysA, fsysB fs.FS, name string, a, b fs.DirEntry) (ChangeKind, error) {
+	switch {
+	case a == nil:
+		return Added, nil
+	case b == nil:
+		return Removed, nil
+	case a.Type() != b.Type():
+		return Modified, nil
+	case a.IsDir():
+		return 0, nil
+	}
+	infoA, err := a.Info()
+	if err != nil {
+		return 0, err
+	}
+	infoB, err := b.Info()
+	if err != nil {
+		return 0, err
+	}
+	if infoA.Size() != infoB.Size() {
+		return Modified, nil
+	}
+	if !c.contents {
+		if !infoA.ModTime().Equal(infoB.ModTime()) {
+			return Modified, nil
+		}
+		return 0, nil
+	}
+	var same bool
+	switch {
+	case infoA.Mode().IsRegular():
+		same, err = sameContents(fsysA, fsysB, name)
+	case a.Type() == fs.ModeSymlink:
+		same, err = sameLink(fsysA, fsysB, name)
+	default:
+		return 0, nil
+	}
+	if err != nil || same {
+		return 0, err
+	}
+	return Modified, nil
+}
+
+// statEntry returns the entry for name, or nil if it does not exist.
+func statEntry(fsys fs.FS, name string) (fs.DirEntry, error) {
+	info, err := stat(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fs.FileInfoToDirEntry(info), nil
+}
+
+// readDirIf reads the directory name if d is not nil.
+func readDirIf(fsys fs.FS, name string, d fs.DirEntry) ([]fs.DirEntry, error) {
+	if d == nil {
+		return nil, nil
+	}
+	return listDir(fsys, name, true)
+}
+
+// readLinkFS is a file system that reads the targets of symbolic links, as
+// fs.ReadLinkFS, added in a later Go version than the one of this module.
+type readLinkFS interface {
+	fs.FS
+	ReadLink(name string) (string, error)
+}
+
+// sameLink reports whether the symbolic link name has the same target in
+// both file systems, or true if either cannot read it.
+func sameLink(fsysA, fsysB fs.FS, name string) (bool, error) {
+	la, okA := fsysA.(readLinkFS)
+	lb, okB := fsysB.(readLinkFS)
+	if !okA || !okB {
+		return true, nil
+	}
+	targetA, err := la.ReadLink(name)
+	if err != nil {
+		return false, err
+	}
+	targetB, err := lb.ReadLink(name)
+	if err != nil {
+		return false, err
+	}
+	return targetA == targetB, nil
+}
+
+// sameContents reports whether the file name has the same contents in both
+// file systems.
+func sameContents(fsysA, fsysB fs.FS, name string) (bool, error) {
+	fa, err := fsysA.Open(name)
+	if err != nil {
+		return false, err
+	}
+	defer fa.Close()
+	fb, err := fsysB.Open(name)
+	if err != nil {
+		return false, err
+	}
+	defer fb.Close()
+
+	bufA, bufB := make([]byte, 32*1024), make([]byte, 32*1024)
+	for {
+		na, errA := io.ReadFull(fa, bufA)
+		nb, errB := io.ReadFull(fb, bufB)
+		if !bytes.Equal(bufA[:na], bufB[:nb]) {
+			return false, nil
+		}
+		doneA := errA == io.EOF || errA == io.ErrUnexpectedEOF
+		doneB := errB == io.EOF || errB == io.ErrUnexpectedEOF
+		if errA != nil && !doneA {
+			return false, errA
+		}
+		if errB != nil && !doneB {
+			return false, errB
+		}
+		if doneA || doneB {
+			return doneA == doneB, nil
+		}
+	}
+}
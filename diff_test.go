@@ -0,0 +1,129 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fsysA := fstest.MapFS{
+		"root/same.txt":          {Data: []byte("same"), ModTime: mtime},
+		"root/size.txt":          {Data: []byte("a"), ModTime: mtime},
+		"root/touched.txt":       {Data: []byte("same"), ModTime: mtime},
+		"root/content.txt":       {Data: []byte("aaaa"), ModTime: mtime},
+		"root/removed/file1.txt": {Data: []byte(""), ModTime: mtime},
+		"root/type":              {Data: []byte(""), ModTime: mtime},
+	}
+	fsysB := fstest.MapFS{
+		"root/same.txt":        {Data: []byte("same"), ModTime: mtime},
+		"root/size.txt":        {Data: []byte("ab"), ModTime: mtime},
+		"root/touched.txt":     {Data: []byte("same"), ModTime: mtime.Add(time.Hour)},
+		"root/content.txt":     {Data: []byte("bbbb"), ModTime: mtime},
+		"root/added/file1.txt": {Data: []byte(""), ModTime: mtime},
+		"root/type/file1.txt":  {Data: []byte(""), ModTime: mtime},
+	}
+
+	cases := []struct {
+		name     string
+		opts     []DiffOption
+		expected []string
+	}{
+		{"Default", nil, []string{
+			"added root/added",
+			"removed root/removed",
+			"modified root/size.txt",
+			"modified root/touched.txt",
+			"modified root/type",
+			"added root/added/file1.txt",
+			"removed root/removed/file1.txt",
+			"added root/type/file1.txt",
+		}},
+		{"Contents", []DiffOption{DiffContents()}, []string{
+			"added root/added",
+			"modified root/content.txt",
+			"removed root/removed",
+			"modified root/size.txt",
+			"modified root/type",
+			"added root/added/file1.txt",
+			"removed root/removed/file1.txt",
+			"added root/type/file1.txt",
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			changes, err := Diff(fsysA, fsysB, "root", c.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, ch := range changes {
+				got = append(got, ch.Kind.String()+" "+ch.Path)
+				if (ch.A == nil) != (ch.Kind == Added) || (ch.B == nil) != (ch.Kind == Removed) {
+					t.Errorf("unexpected entries for %s: %v, %v", ch.Path, ch.A, ch.B)
+				}
+			}
+			if !slices.Equal(got, c.expected) {
+				t.Errorf("expected:\n  %v\ngot\n: %v", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestDiffMissingRoot(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/file1.txt": {Data: []byte("")},
+	}
+
+	changes, err := Diff(fstest.MapFS{}, fsys, "root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 2 || changes[0].Kind != Added || changes[1].Path != "root/file1.txt" {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+
+	_, err = Diff(fstest.MapFS{}, fstest.MapFS{}, "root")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected %v, got %v", fs.ErrNotExist, err)
+	}
+}
+
+// linkFS is a file system whose symbolic links target the paths held as
+// their data.
+type linkFS struct {
+	fstest.MapFS
+}
+
+func (f linkFS) ReadLink(name string) (string, error) {
+	file, ok := f.MapFS[name]
+	if !ok || file.Mode&fs.ModeSymlink == 0 {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return string(file.Data), nil
+}
+
+func TestDiffContentsLinks(t *testing.T) {
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fsysA := linkFS{fstest.MapFS{
+		"root/same":    {Data: []byte("a.txt"), Mode: fs.ModeSymlink, ModTime: mtime},
+		"root/changed": {Data: []byte("a.txt"), Mode: fs.ModeSymlink, ModTime: mtime},
+	}}
+	fsysB := linkFS{fstest.MapFS{
+		"root/same":    {Data: []byte("a.txt"), Mode: fs.ModeSymlink, ModTime: mtime},
+		"root/changed": {Data: []byte("b.txt"), Mode: fs.ModeSymlink, ModTime: mtime},
+	}}
+
+	changes, err := Diff(fsysA, fsysB, "root", DiffContents())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Kind != Modified || changes[0].Path != "root/changed" {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+}
@@ -0,0 +1,51 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"path"
+	"slices"
+)
+
+// DirSizes walks the file tree rooted at root and returns the total size in
+// bytes of the files below each directory in the tree, keyed by path.
+//
+// Sizes are totalled once the walk is done, by adding the size of each
+// directory to its parent from the deepest level up. Directories themselves
+// count for nothing. If root is a file, the map holds its size alone. The
+// first error encountered stops the walk and is returned.
+func DirSizes(fsys fs.FS, root string) (map[string]int64, error) {
+	sizes := make(map[string]int64)
+	var dirs []string
+	err := WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			sizes[name] = 0
+			dirs = append(dirs, name)
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if name == root {
+			sizes[name] = info.Size()
+		} else {
+			sizes[path.Dir(name)] += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Directories are visited breadth-first, so every directory comes
+	// after its parent and children are totalled before their parents.
+	for _, name := range slices.Backward(dirs) {
+		if name != root {
+			sizes[path.Dir(name)] += sizes[name]
+		}
+	}
+	return sizes, nil
+}
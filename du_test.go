@@ -0,0 +1,42 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"maps"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDirSizes(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":          {Data: []byte("1")},
+		"root/dirA/file1.txt":     {Data: []byte("22")},
+		"root/dirB/file1.txt":     {Data: []byte("333")},
+		"root/dirB/sub/file1.txt": {Data: []byte("4444")},
+		"root/dirB/sub/empty":     {Mode: fs.ModeDir | 0o755},
+	}
+
+	sizes, err := DirSizes(memFS, "root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]int64{
+		"root":                10,
+		"root/dirA":           2,
+		"root/dirB":           7,
+		"root/dirB/sub":       4,
+		"root/dirB/sub/empty": 0,
+	}
+	if !maps.Equal(sizes, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, sizes)
+	}
+
+	sizes, err = DirSizes(memFS, "root/file1.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !maps.Equal(sizes, map[string]int64{"root/file1.txt": 1}) {
+		t.Errorf("unexpected sizes for file root: %v", sizes)
+	}
+}
@@ -0,0 +1,101 @@
+package bfwalk
+
+import (
+	"hash"
+	"io/fs"
+	"slices"
+)
+
+// FindDuplicates walks the file tree rooted at root and returns groups of
+// paths of regular files with identical contents.
+//
+// Files are grouped by size as they are visited, and only files that share
+// their size with another file are read and hashed, so unique sizes cost a
+// single stat. Paths within a group and the groups themselves are in
+// breadth-first order, by their first path. The first error encountered
+// stops the walk and is returned.
+//
+// Contents are compared by their SHA-256 digest; see [FindDuplicatesWith]
+// for other hash functions.
+func FindDuplicates(fsys fs.FS, root string) ([][]string, error) {
+	return FindDuplicatesWith(fsys, root, SHA256)
+}
+
+// FindDuplicatesWith is like [FindDuplicates], but compares contents by
+// their digest computed with hash functions returned by h. Faster hash
+// functions that are not collision resistant, such as [CRC32], may group
+// files whose contents differ.
+func FindDuplicatesWith(fsys fs.FS, root string, h func() hash.Hash) ([][]string, error) {
+	type key struct {
+		size int64
+		sum  string
+	}
+	type group struct {
+		first int // visit index of the first path
+		paths []string
+	}
+
+	var visited int
+	first := make(map[int64]string) // the unhashed first file of each size
+	index := make(map[string]int)   // visit index of files in first
+	groups := make(map[key]*group)
+	add := func(name string, size int64, i int) error {
+		sum, err := hashFile(fsys, name, h())
+		if err != nil {
+			return err
+		}
+		k := key{size, string(sum)}
+		g := groups[k]
+		if g == nil {
+			g = &group{first: i}
+			groups[k] = g
+		}
+		g.paths = append(g.paths, name)
+		return nil
+	}
+
+	err := WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		visited++
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size := info.Size()
+		prev, seen := first[size]
+		if !seen {
+			first[size], index[name] = name, visited
+			return nil
+		}
+		if prev != "" {
+			// Second file of this size, hash the first as well
+			if err := add(prev, size, index[prev]); err != nil {
+				return err
+			}
+			first[size] = ""
+			delete(index, prev)
+		}
+		return add(name, size, visited)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var dupes []*group
+	for _, g := range groups {
+		if len(g.paths) > 1 {
+			dupes = append(dupes, g)
+		}
+	}
+	slices.SortFunc(dupes, func(a, b *group) int { return a.first - b.first })
+	result := make([][]string, len(dupes))
+	for i, g := range dupes {
+		result[i] = g.paths
+	}
+	return result, nil
+}
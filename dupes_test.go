@@ -0,0 +1,32 @@
+package bfwalk
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/a.txt":          {Data: []byte("same")},
+		"root/b.txt":          {Data: []byte("diff")},
+		"root/c.txt":          {Data: []byte("unique size")},
+		"root/dirA/a.txt":     {Data: []byte("diff")},
+		"root/dirA/b.txt":     {Data: []byte("same")},
+		"root/dirB/sub/a.txt": {Data: []byte("same")},
+		"root/dirB/x.txt":     {Data: []byte("size")},
+	}
+
+	dupes, err := FindDuplicates(memFS, "root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := [][]string{
+		{"root/a.txt", "root/dirA/b.txt", "root/dirB/sub/a.txt"},
+		{"root/b.txt", "root/dirA/a.txt"},
+	}
+	if !slices.EqualFunc(dupes, expected, slices.Equal) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, dupes)
+	}
+}
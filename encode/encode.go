@@ -0,0 +1,264 @@
+// Package encode writes the entries of a walk as structured records, for
+// exporting file inventories as plain text, JSON Lines or CSV.
+package encode
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+// A Field is a property of an entry written in each record.
+type Field int
+
+const (
+	Path    Field = iota // path of the entry, as reported by the walk
+	Type                 // "dir", "file", "symlink" or "other"
+	Size                 // size in bytes
+	Mode                 // file mode, as formatted by fs.FileMode.String
+	ModTime              // modification time
+	Depth                // number of path elements below the walk root
+	Parent               // path of the parent directory
+)
+
+// DefaultFields are the fields written by the JSON Lines and CSV encoders
+// when no fields are given.
+var DefaultFields = []Field{Path, Type, Size, Mode, ModTime, Depth}
+
+// String returns the name of the field, used as the JSON key and CSV header.
+func (f Field) String() string {
+	switch f {
+	case Path:
+		return "path"
+	case Type:
+		return "type"
+	case Size:
+		return "size"
+	case Mode:
+		return "mode"
+	case ModTime:
+		return "mtime"
+	case Depth:
+		return "depth"
+	case Parent:
+		return "parent"
+	}
+	return "unknown"
+}
+
+// An Encoder writes a record for each entry passed to Encode. Records may be
+// buffered until Flush is called.
+type Encoder interface {
+	Encode(e bfwalk.Entry) error
+	Flush() error
+}
+
+// Write writes a record with enc for each entry of entries, such as those
+// yielded by [bfwalk.Entries], then flushes enc. An entry with Err set
+// stops writing, and its error is returned after flushing the records
+// written so far.
+func Write(enc Encoder, entries iter.Seq[bfwalk.Entry]) error {
+	var err error
+	for e := range entries {
+		if e.Err != nil {
+			err = e.Err
+			break
+		}
+		if err = enc.Encode(e); err != nil {
+			break
+		}
+	}
+	if ferr := enc.Flush(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// NewText returns an Encoder that writes each entry to w on its own line,
+// with the given fields separated by tabs. With no fields, only the path is
+// written. Times are formatted as [time.RFC3339Nano].
+func NewText(w io.Writer, fields ...Field) Encoder {
+	if len(fields) == 0 {
+		fields = []Field{Path}
+	}
+	return &textEncoder{bufio.NewWriter(w), fields}
+}
+
+type textEncoder struct {
+	w      *bufio.Writer
+	fields []Field
+}
+
+func (enc *textEncoder) Encode(e bfwalk.Entry) error {
+	record, err := format(e, enc.fields)
+	if err != nil {
+		return err
+	}
+	_, err = enc.w.WriteString(strings.Join(record, "\t") + "\n")
+	return err
+}
+
+func (enc *textEncoder) Flush() error {
+	return enc.w.Flush()
+}
+
+// NewJSONLines returns an Encoder that writes each entry to w as a JSON
+// object on its own line, with a key for each of the given fields in order,
+// or for [DefaultFields] if none are given. Sizes and depths are written as
+// numbers and times as [time.RFC3339Nano] strings.
+func NewJSONLines(w io.Writer, fields ...Field) Encoder {
+	if len(fields) == 0 {
+		fields = DefaultFields
+	}
+	return &jsonEncoder{bufio.NewWriter(w), fields}
+}
+
+// WriteJSONLines walks the file tree rooted at root and writes each visited
+// entry to w as a JSON object on its own line, in breadth-first order, with
+// the [DefaultFields]. It is short for [Write] with [NewJSONLines] and
+// [bfwalk.Entries]. Entries are written as they are visited, so output for
+// a walk that fails part way is truncated rather than discarded.
+func WriteJSONLines(w io.Writer, fsys fs.FS, root string) error {
+	return Write(NewJSONLines(w), bfwalk.Entries(fsys, root))
+}
+
+type jsonEncoder struct {
+	w      *bufio.Writer
+	fields []Field
+}
+
+func (enc *jsonEncoder) Encode(e bfwalk.Entry) error {
+	buf := []byte{'{'}
+	for i, f := range enc.fields {
+		v, err := value(e, f)
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = strconv.AppendQuote(buf, f.String())
+		buf = append(buf, ':')
+		buf = append(buf, b...)
+	}
+	buf = append(buf, '}', '\n')
+	_, err := enc.w.Write(buf)
+	return err
+}
+
+func (enc *jsonEncoder) Flush() error {
+	return enc.w.Flush()
+}
+
+// NewCSV returns an Encoder that writes each entry to w as a CSV record
+// with the given fields, or [DefaultFields] if none are given, preceded by
+// a header record naming the fields. Times are formatted as
+// [time.RFC3339Nano].
+func NewCSV(w io.Writer, fields ...Field) Encoder {
+	if len(fields) == 0 {
+		fields = DefaultFields
+	}
+	return &csvEncoder{w: csv.NewWriter(w), fields: fields}
+}
+
+type csvEncoder struct {
+	w      *csv.Writer
+	fields []Field
+	header bool // whether the header has been written
+}
+
+func (enc *csvEncoder) Encode(e bfwalk.Entry) error {
+	if !enc.header {
+		enc.header = true
+		header := make([]string, len(enc.fields))
+		for i, f := range enc.fields {
+			header[i] = f.String()
+		}
+		if err := enc.w.Write(header); err != nil {
+			return err
+		}
+	}
+	record, err := format(e, enc.fields)
+	if err != nil {
+		return err
+	}
+	return enc.w.Write(record)
+}
+
+func (enc *csvEncoder) Flush() error {
+	enc.w.Flush()
+	return enc.w.Error()
+}
+
+// format returns the given fields of e formatted as strings.
+func format(e bfwalk.Entry, fields []Field) ([]string, error) {
+	record := make([]string, len(fields))
+	for i, f := range fields {
+		v, err := value(e, f)
+		if err != nil {
+			return nil, err
+		}
+		switch v := v.(type) {
+		case string:
+			record[i] = v
+		case time.Time:
+			record[i] = v.Format(time.RFC3339Nano)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return record, nil
+}
+
+// value returns the field f of e.
+func value(e bfwalk.Entry, f Field) (any, error) {
+	switch f {
+	case Path:
+		return e.Path, nil
+	case Type:
+		return entryType(e.DirEntry.Type()), nil
+	case Depth:
+		return e.Depth, nil
+	case Parent:
+		return e.Parent, nil
+	}
+	info, err := e.DirEntry.Info()
+	if err != nil {
+		return nil, err
+	}
+	switch f {
+	case Size:
+		return info.Size(), nil
+	case Mode:
+		return info.Mode().String(), nil
+	case ModTime:
+		return info.ModTime(), nil
+	}
+	return nil, fmt.Errorf("encode: unknown field %d", f)
+}
+
+// entryType describes the type bits of mode.
+func entryType(mode fs.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return "dir"
+	case mode&fs.ModeSymlink != 0:
+		return "symlink"
+	case mode.IsRegular():
+		return "file"
+	}
+	return "other"
+}
@@ -0,0 +1,111 @@
+package encode
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+func TestEncoders(t *testing.T) {
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	memFS := fstest.MapFS{
+		"root":                {Mode: fs.ModeDir | 0o755, ModTime: mtime},
+		"root/file1.txt":      {Data: []byte("hello"), Mode: 0o644, ModTime: mtime},
+		"root/dirA/file,.txt": {Data: []byte(""), Mode: 0o644, ModTime: mtime},
+	}
+
+	tests := map[string]struct {
+		enc      func(sb *strings.Builder) Encoder
+		expected string
+	}{
+		"text": {
+			func(sb *strings.Builder) Encoder { return NewText(sb) },
+			"root\nroot/file1.txt\nroot/dirA/file,.txt\n",
+		},
+		"text fields": {
+			func(sb *strings.Builder) Encoder { return NewText(sb, Depth, Type, Path) },
+			"0\tdir\troot\n1\tfile\troot/file1.txt\n2\tfile\troot/dirA/file,.txt\n",
+		},
+		"jsonl": {
+			func(sb *strings.Builder) Encoder { return NewJSONLines(sb, Path, Size, ModTime, Parent) },
+			`{"path":"root","size":0,"mtime":"2024-01-02T03:04:05Z","parent":""}` + "\n" +
+				`{"path":"root/file1.txt","size":5,"mtime":"2024-01-02T03:04:05Z","parent":"root"}` + "\n" +
+				`{"path":"root/dirA/file,.txt","size":0,"mtime":"2024-01-02T03:04:05Z","parent":"root/dirA"}` + "\n",
+		},
+		"csv": {
+			func(sb *strings.Builder) Encoder { return NewCSV(sb, Path, Mode) },
+			"path,mode\nroot,drwxr-xr-x\nroot/file1.txt,-rw-r--r--\n\"root/dirA/file,.txt\",-rw-r--r--\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sb strings.Builder
+			entries := func(yield func(bfwalk.Entry) bool) {
+				for e := range bfwalk.Entries(memFS, "root") {
+					if e.DirEntry.IsDir() && e.Path != "root" {
+						continue // Keep only root and files
+					}
+					if !yield(e) {
+						return
+					}
+				}
+			}
+			if err := Write(tt.enc(&sb), entries); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("expected:\n  %q\ngot\n: %q", tt.expected, sb.String())
+			}
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt": {Data: []byte("")},
+	}
+	errWalk := errors.New("walk failed")
+	entries := func(yield func(bfwalk.Entry) bool) {
+		for e := range bfwalk.Entries(memFS, "root") {
+			if !yield(e) {
+				return
+			}
+		}
+		yield(bfwalk.Entry{Err: errWalk})
+	}
+
+	var sb strings.Builder
+	if err := Write(NewText(&sb), entries); err != errWalk {
+		t.Errorf("expected error %v, got %v", errWalk, err)
+	}
+	if expected := "root\nroot/file1.txt\n"; sb.String() != expected {
+		t.Errorf("expected:\n  %q\ngot\n: %q", expected, sb.String())
+	}
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	memFS := fstest.MapFS{
+		"root":                {Mode: fs.ModeDir | 0o755, ModTime: mtime},
+		"root/dirA":           {Mode: fs.ModeDir | 0o755, ModTime: mtime},
+		"root/file1.txt":      {Data: []byte("hello"), Mode: 0o644, ModTime: mtime},
+		"root/dirA/file1.txt": {Data: []byte(""), Mode: 0o644, ModTime: mtime},
+	}
+
+	var sb strings.Builder
+	if err := WriteJSONLines(&sb, memFS, "root"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"path":"root","type":"dir","size":0,"mode":"drwxr-xr-x","mtime":"2024-01-02T03:04:05Z","depth":0}` + "\n" +
+		`{"path":"root/dirA","type":"dir","size":0,"mode":"drwxr-xr-x","mtime":"2024-01-02T03:04:05Z","depth":1}` + "\n" +
+		`{"path":"root/file1.txt","type":"file","size":5,"mode":"-rw-r--r--","mtime":"2024-01-02T03:04:05Z","depth":1}` + "\n" +
+		`{"path":"root/dirA/file1.txt","type":"file","size":0,"mode":"-rw-r--r--","mtime":"2024-01-02T03:04:05Z","depth":2}` + "\n"
+	if sb.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, sb.String())
+	}
+}
@@ -0,0 +1,100 @@
+package bfwalk
+
+import (
+	"context"
+	"io/fs"
+	"iter"
+	"path"
+)
+
+// An Entry is a single file or directory reported by a walk.
+type Entry struct {
+	Path     string      // path as it would be passed to a WalkDirFunc
+	DirEntry fs.DirEntry // nil if Path itself could not be stat'ed
+	Depth    int         // number of path elements below the walk root
+	Parent   string      // path of the parent directory, "" for a root
+	Err      error       // error reading or stat'ing the entry, if any
+}
+
+// entry returns the Entry for the call of the walk callback in progress.
+func (w *Walker) entry(p string, d fs.DirEntry, err error) Entry {
+	e := w.current
+	parent := ""
+	if e.name != e.root {
+		parent = w.report(e.root, path.Dir(e.name))
+	}
+	return Entry{p, d, e.depth, parent, err}
+}
+
+// Entries walks the file tree rooted at root breadth-first and yields an
+// [Entry] for each file or directory in the tree, including root, in the
+// order they are visited by [WalkDir].
+//
+// A directory that cannot be read is yielded a second time with Err set,
+// and errors that stop the walk, such as [ErrDeadlineExceeded], are yielded
+// last with only Err set. Stopping the iteration stops the walk.
+func Entries(fsys fs.FS, root string) iter.Seq[Entry] {
+	return NewWalker().Entries(fsys, root)
+}
+
+// Entries walks the file tree rooted at root like the package-level
+// [Entries] function, with the traversal adjusted by the options the Walker
+// was created with.
+func (w *Walker) Entries(fsys fs.FS, root string) iter.Seq[Entry] {
+	return func(yield func(Entry) bool) {
+		err := w.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+			if !yield(w.entry(path, d, err)) {
+				return fs.SkipAll
+			}
+			return nil
+		})
+		if err != nil {
+			yield(Entry{Err: err})
+		}
+	}
+}
+
+// Stream walks the file tree rooted at root in a new goroutine and sends an
+// [Entry] for each file or directory in the tree on the returned channel,
+// like [Entries]. The channel is closed once the walk is done, or once ctx
+// is canceled.
+func Stream(ctx context.Context, fsys fs.FS, root string) <-chan Entry {
+	return NewWalker().Stream(ctx, fsys, root)
+}
+
+// Stream walks the file tree rooted at root like the package-level [Stream]
+// function, with the traversal adjusted by the options the Walker was
+// created with. The Walker must not be used for another walk until the
+// channel is closed.
+func (w *Walker) Stream(ctx context.Context, fsys fs.FS, root string) <-chan Entry {
+	ch := make(chan Entry)
+	go func() {
+		defer close(ch)
+		for e := range w.Entries(fsys, root) {
+			select {
+			case ch <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// CollectEntries walks the file tree rooted at root with a [Walker]
+// configured with opts and returns an [Entry] for each file and directory
+// in the tree, in the order they are visited. The first error encountered
+// stops the walk and is returned, together with the entries visited before
+// it.
+func CollectEntries(fsys fs.FS, root string, opts ...Option) ([]Entry, error) {
+	w := NewWalker(opts...)
+	var entries []Entry
+	err := w.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		entries = append(entries, w.entry(path, d, nil))
+		return nil
+	})
+	return entries, err
+}
@@ -0,0 +1,127 @@
+package bfwalk
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEntries(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":          {Data: []byte("")},
+		"root/dirA/file1.txt":     {Data: []byte("")},
+		"root/dirB/sub/file1.txt": {Data: []byte("")},
+	}
+
+	var visited []string
+	for e := range Entries(memFS, "root") {
+		if e.Err != nil {
+			t.Fatalf("unexpected error for %s: %v", e.Path, e.Err)
+		}
+		visited = append(visited, fmt.Sprintf("%s %d %q", e.Path, e.Depth, e.Parent))
+	}
+
+	expected := []string{
+		`root 0 ""`,
+		`root/dirA 1 "root"`,
+		`root/dirB 1 "root"`,
+		`root/file1.txt 1 "root"`,
+		`root/dirA/file1.txt 2 "root/dirA"`,
+		`root/dirB/sub 2 "root/dirB"`,
+		`root/dirB/sub/file1.txt 3 "root/dirB/sub"`,
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+
+	// relative paths are reported relative to root
+	visited = nil
+	for e := range NewWalker(WithRelativePaths()).Entries(memFS, "root") {
+		if e.Depth == 2 {
+			visited = append(visited, fmt.Sprintf("%s %q", e.Path, e.Parent))
+		}
+	}
+	expected = []string{
+		`dirA/file1.txt "dirA"`,
+		`dirB/sub "dirB"`,
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+func TestEntriesStop(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/dirA/file1.txt": {Data: []byte("")},
+		"root/dirB/file1.txt": {Data: []byte("")},
+	}
+
+	var visited []string
+	for e := range Entries(memFS, "root") {
+		visited = append(visited, e.Path)
+		if e.Path == "root/dirA" {
+			break
+		}
+	}
+
+	expected := []string{"root", "root/dirA"}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+func TestStream(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/dirA/file1.txt": {Data: []byte("")},
+		"root/dirB/file1.txt": {Data: []byte("")},
+	}
+
+	var visited []string
+	for e := range Stream(context.Background(), memFS, "root") {
+		visited = append(visited, e.Path)
+	}
+
+	expected := []string{
+		"root",
+		"root/dirA",
+		"root/dirB",
+		"root/dirA/file1.txt",
+		"root/dirB/file1.txt",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+
+	// canceling closes the channel
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := Stream(ctx, memFS, "root")
+	<-ch
+	cancel()
+	for range ch {
+	}
+}
+
+func TestCollectEntries(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/dirA/file1.txt": {Data: []byte("")},
+	}
+
+	entries, err := CollectEntries(memFS, "root", WithMaxResults(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var visited []string
+	for _, e := range entries {
+		visited = append(visited, fmt.Sprintf("%s %d %q", e.Path, e.Depth, e.Parent))
+	}
+
+	expected := []string{
+		`root 0 ""`,
+		`root/dirA 1 "root"`,
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
@@ -0,0 +1,93 @@
+package bfwalk
+
+import "io/fs"
+
+// An EventKind is the kind of an [Event].
+type EventKind int
+
+const (
+	// EnterDir is sent when a directory is visited, before the walk
+	// callback is called for it. It is sent whether or not the directory
+	// is then read, so directories that are skipped or not descended into
+	// are reported too.
+	EnterDir EventKind = iota
+
+	// VisitFile is sent when a non-directory entry is visited.
+	VisitFile
+
+	// ReadDirError is sent when an entry cannot be read, before the error
+	// is passed to the walk callback.
+	ReadDirError
+
+	// LevelDone is sent once every entry of a breadth level has been
+	// visited, as with [WithLevelHooks].
+	LevelDone
+
+	// WalkDone is sent once the walk is done, with the error it returns.
+	WalkDone
+)
+
+// String returns the name of the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case EnterDir:
+		return "EnterDir"
+	case VisitFile:
+		return "VisitFile"
+	case ReadDirError:
+		return "ReadDirError"
+	case LevelDone:
+		return "LevelDone"
+	case WalkDone:
+		return "WalkDone"
+	}
+	return "unknown"
+}
+
+// An Event describes progress of a walk sent to the subscribers added with
+// [WithSubscribers]. Fields that do not apply to the kind of the event are
+// left zero.
+type Event struct {
+	Kind     EventKind
+	Path     string      // path of the entry, as passed to the walk callback
+	DirEntry fs.DirEntry // entry, nil for ReadDirError on a root that cannot be stat'ed
+	Depth    int         // depth of the entry, or of the level for LevelDone
+	Entries  int         // entries visited in the level, for LevelDone
+	Err      error       // error of ReadDirError, or returned by the walk for WalkDone
+}
+
+// WithSubscribers makes the Walker send an [Event] to each of subs as the
+// walk progresses, so that several consumers, such as a progress display
+// and a log, can follow a single walk alongside the walk callback.
+//
+// Events are sent from the goroutine calling the walk callback, in the
+// order they happen, and each subscriber is called in turn; subscribers
+// that block hold up the walk. If the option is given more than once, the
+// subscribers are added to those given before.
+func WithSubscribers(subs ...func(Event)) Option {
+	return func(w *Walker) {
+		w.subscribers = append(w.subscribers, subs...)
+	}
+}
+
+// emit sends e to the subscribers of the Walker.
+func (w *Walker) emit(e Event) {
+	for _, fn := range w.subscribers {
+		fn(e)
+	}
+}
+
+// emitVisit sends the event for the visit of the entry e, passed to the
+// walk callback with err.
+func (w *Walker) emitVisit(e namedEntry, err error) {
+	ev := Event{Path: w.report(e.root, e.name), DirEntry: e.d, Depth: e.depth, Err: err}
+	switch {
+	case err != nil:
+		ev.Kind = ReadDirError
+	case e.d.IsDir():
+		ev.Kind = EnterDir
+	default:
+		ev.Kind = VisitFile
+	}
+	w.emit(ev)
+}
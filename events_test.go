@@ -0,0 +1,102 @@
+package bfwalk
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithSubscribers(t *testing.T) {
+	errRead := errors.New("read failed")
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/file":     {},
+			"root/a/file":   {},
+			"root/bad/file": {},
+		},
+		errs: map[string]error{"root/bad": errRead},
+	}
+
+	var events, errs []string
+	w := NewWalker(WithSubscribers(func(e Event) {
+		s := fmt.Sprint(e.Kind, " ", e.Path, " ", e.Depth)
+		if e.Kind == LevelDone {
+			s = fmt.Sprint(e.Kind, " ", e.Depth, " ", e.Entries)
+		}
+		events = append(events, s)
+	}), WithSubscribers(func(e Event) {
+		if e.Err != nil {
+			errs = append(errs, fmt.Sprint(e.Kind, " ", e.Err))
+		}
+	}))
+	err := w.WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"EnterDir root 0",
+		"LevelDone 0 1",
+		"EnterDir root/a 1",
+		"EnterDir root/bad 1",
+		"VisitFile root/file 1",
+		"LevelDone 1 3",
+		"VisitFile root/a/file 2",
+		"ReadDirError root/bad 1",
+		"LevelDone 2 2",
+		"WalkDone  0",
+	}
+	if !slices.Equal(events, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, events)
+	}
+	if expected := []string{"ReadDirError read failed"}; !slices.Equal(errs, expected) {
+		t.Errorf("expected %v, got %v", expected, errs)
+	}
+}
+
+func TestWithSubscribersUndescended(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/a/file": {},
+		"root/b/file": {},
+		"root/c/file": {},
+	}
+	run := func(t *testing.T, walk func(*Walker, fs.FS, string, fs.WalkDirFunc) error) []string {
+		var events []string
+		w := NewWalker(WithSubscribers(func(e Event) {
+			if e.Kind == EnterDir || e.Kind == VisitFile {
+				events = append(events, fmt.Sprint(e.Kind, " ", e.Path))
+			}
+		}), WithShouldDescend(func(path string, d fs.DirEntry) bool {
+			return path != "root/c"
+		}))
+		err := walk(w, fsys, "root", func(path string, d fs.DirEntry, err error) error {
+			if path == "root/b" {
+				return fs.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return events
+	}
+
+	expected := []string{
+		"EnterDir root",
+		"EnterDir root/a",
+		"EnterDir root/b",
+		"EnterDir root/c",
+		"VisitFile root/a/file",
+	}
+	if events := run(t, (*Walker).WalkDir); !slices.Equal(events, expected) {
+		t.Errorf("expected %v, got %v", expected, events)
+	}
+	if events := run(t, (*Walker).IDWalk); !slices.Equal(events, expected) {
+		t.Errorf("IDWalk: expected %v, got %v", expected, events)
+	}
+}
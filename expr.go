@@ -0,0 +1,186 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"path"
+	"time"
+)
+
+// An Expr is a predicate on the entries of a walk, built from the
+// combinators below in the style of the expressions of find(1), and used
+// with [WithFilter] and [WithPrune]. For example, the regular Go files over
+// 1MB modified in the last day are
+//
+//	And(Type('f'), Name("*.go"), Size('>', 1<<20), Newer(time.Now().Add(-24*time.Hour)))
+//
+// Predicates on file info read it with the entry's Info method, which the
+// Walker calls at most once per entry. An entry whose info cannot be read
+// matches no such predicate.
+type Expr func(d fs.DirEntry) bool
+
+// And returns an Expr matching entries that match every one of exprs,
+// evaluated in order until one does not match. And with no exprs matches
+// every entry.
+func And(exprs ...Expr) Expr {
+	return func(d fs.DirEntry) bool {
+		for _, e := range exprs {
+			if !e(d) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns an Expr matching entries that match any of exprs, evaluated
+// in order until one matches. Or with no exprs matches no entry.
+func Or(exprs ...Expr) Expr {
+	return func(d fs.DirEntry) bool {
+		for _, e := range exprs {
+			if e(d) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns an Expr matching entries that do not match e.
+func Not(e Expr) Expr {
+	return func(d fs.DirEntry) bool {
+		return !e(d)
+	}
+}
+
+// Name returns an Expr matching entries whose name matches the shell
+// pattern pattern, with the syntax of [path.Match]. A malformed pattern
+// matches no entry.
+func Name(pattern string) Expr {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return Or()
+	}
+	return func(d fs.DirEntry) bool {
+		ok, _ := path.Match(pattern, d.Name())
+		return ok
+	}
+}
+
+// Type returns an Expr matching entries of the given type, as in find(1):
+// 'f' for regular files, 'd' for directories, 'l' for symbolic links, 'p'
+// for named pipes, 's' for sockets, 'c' for character devices and 'b' for
+// other devices. Any other type matches no entry.
+func Type(t byte) Expr {
+	return func(d fs.DirEntry) bool {
+		m := d.Type()
+		switch t {
+		case 'f':
+			return m.IsRegular()
+		case 'd':
+			return m.IsDir()
+		case 'l':
+			return m&fs.ModeSymlink != 0
+		case 'p':
+			return m&fs.ModeNamedPipe != 0
+		case 's':
+			return m&fs.ModeSocket != 0
+		case 'c':
+			return m&fs.ModeCharDevice != 0
+		case 'b':
+			return m&fs.ModeDevice != 0 && m&fs.ModeCharDevice == 0
+		}
+		return false
+	}
+}
+
+// Size returns an Expr matching entries whose size compares to n as given
+// by op: '<' for less than n bytes, '=' for exactly n bytes and '>' for
+// more than n bytes. Any other op matches no entry.
+func Size(op byte, n int64) Expr {
+	return withInfo(func(info fs.FileInfo) bool {
+		switch op {
+		case '<':
+			return info.Size() < n
+		case '=':
+			return info.Size() == n
+		case '>':
+			return info.Size() > n
+		}
+		return false
+	})
+}
+
+// Perm returns an Expr matching entries whose permission bits, including
+// [fs.ModeSetuid], [fs.ModeSetgid] and [fs.ModeSticky], compare to mode as
+// given by op, as in find(1): '=' for exactly mode, '-' for all the bits of
+// mode set, and '/' for any of the bits of mode set. Any other op matches
+// no entry. For example, the world-writable regular files are
+//
+//	And(Type('f'), Perm('/', 0o002))
+//
+// Symbolic links usually have every permission bit set.
+func Perm(op byte, mode fs.FileMode) Expr {
+	const bits = fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky
+	mode &= bits
+	return withInfo(func(info fs.FileInfo) bool {
+		perm := info.Mode() & bits
+		switch op {
+		case '=':
+			return perm == mode
+		case '-':
+			return perm&mode == mode
+		case '/':
+			return perm&mode != 0
+		}
+		return false
+	})
+}
+
+// Newer returns an Expr matching entries modified after t.
+func Newer(t time.Time) Expr {
+	return withInfo(func(info fs.FileInfo) bool {
+		return info.ModTime().After(t)
+	})
+}
+
+// withInfo returns an Expr matching entries whose file info matches fn.
+func withInfo(fn func(info fs.FileInfo) bool) Expr {
+	return func(d fs.DirEntry) bool {
+		info, err := d.Info()
+		return err == nil && fn(info)
+	}
+}
+
+// WithFilter makes the Walker pass to the walk callback only the entries
+// matching e. Directories that do not match are still descended into, as
+// with find(1) expressions without -prune, and the roots of the walk are
+// always visited. If the option is given more than once, entries are
+// visited only if they match every e.
+func WithFilter(e Expr) Option {
+	return func(w *Walker) {
+		w.filters = append(w.filters, e)
+	}
+}
+
+// WithPrune makes the Walker leave the entries matching e out of the walk:
+// they are not passed to the walk callback and directories are not
+// descended into, as with [WithPruneNames]. The roots of the walk are
+// always visited.
+func WithPrune(e Expr) Option {
+	return func(w *Walker) {
+		w.prune = append(w.prune, e)
+	}
+}
+
+// matches reports whether d matches every filter given with [WithFilter].
+func (w *Walker) matches(d fs.DirEntry) bool {
+	if len(w.filters) == 0 {
+		return true
+	}
+	d = w.normEntry(d)
+	for _, e := range w.filters {
+		if !e(d) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestExpr(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"main.go":          {Data: make([]byte, 100), ModTime: now, Mode: 0o644},
+		"README.md":        {Data: make([]byte, 10), ModTime: now.Add(-48 * time.Hour), Mode: 0o666},
+		"link":             {Mode: fs.ModeSymlink},
+		"pipe":             {Mode: fs.ModeNamedPipe},
+		"sock":             {Mode: fs.ModeSocket},
+		"tty":              {Mode: fs.ModeDevice | fs.ModeCharDevice},
+		"disk":             {Mode: fs.ModeDevice},
+		"pkg/util.go":      {Data: make([]byte, 5000), ModTime: now.Add(-time.Hour), Mode: 0o755 | fs.ModeSetuid},
+		"pkg/util_test.go": {Data: make([]byte, 50), ModTime: now},
+	}
+	dirs, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pkg, err := fs.ReadDir(fsys, "pkg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries := append(dirs, pkg...)
+
+	tests := []struct {
+		name     string
+		expr     Expr
+		expected []string
+	}{
+		{"name", Name("*.go"), []string{"main.go", "util.go", "util_test.go"}},
+		{"bad name", Name("[*.go"), nil},
+		{"file", Type('f'), []string{"README.md", "main.go", "util.go", "util_test.go"}},
+		{"dir", Type('d'), []string{"pkg"}},
+		{"link", Type('l'), []string{"link"}},
+		{"pipe", Type('p'), []string{"pipe"}},
+		{"socket", Type('s'), []string{"sock"}},
+		{"char device", Type('c'), []string{"tty"}},
+		{"block device", Type('b'), []string{"disk"}},
+		{"bad type", Type('x'), nil},
+		{"smaller", And(Type('f'), Size('<', 100)), []string{"README.md", "util_test.go"}},
+		{"equal", Size('=', 100), []string{"main.go"}},
+		{"larger", Size('>', 100), []string{"util.go"}},
+		{"bad op", Size('!', 100), nil},
+		{"newer", Newer(now.Add(-2 * time.Hour)), []string{"main.go", "util.go", "util_test.go"}},
+		{"exact perm", Perm('=', 0o644), []string{"main.go"}},
+		{"all perm", And(Type('f'), Perm('-', 0o644)), []string{"README.md", "main.go", "util.go"}},
+		{"any perm", Perm('/', 0o002), []string{"README.md"}},
+		{"setuid", Perm('/', fs.ModeSetuid), []string{"util.go"}},
+		{"bad perm op", Perm('+', 0o644), nil},
+		{"or", Or(Name("*.md"), Type('l')), []string{"README.md", "link"}},
+		{"not", And(Name("*.go"), Not(Name("*_test.go"))), []string{"main.go", "util.go"}},
+		{"empty and", And(), []string{"README.md", "disk", "link", "main.go", "pipe", "pkg", "sock", "tty", "util.go", "util_test.go"}},
+		{"empty or", Or(), nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, d := range entries {
+			if tt.expr(d) {
+				got = append(got, d.Name())
+			}
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestWithFilter(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/a.go":           {},
+		"root/b.txt":          {},
+		"root/src/c.go":       {},
+		"root/src/d.md":       {},
+		"root/vendor/e.go":    {},
+		"root/src/deep/f.go":  {},
+		"root/src/deep/g.txt": {},
+	}
+	var done []string
+	got, err := Collect(fsys, "root",
+		WithFilter(Name("*.go")),
+		WithPrune(And(Type('d'), Name("vendor"))),
+		WithOnDirComplete(func(path string, entries int) {
+			done = append(done, path)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"root", "root/a.go", "root/src/c.go", "root/src/deep/f.go"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	expectedDone := []string{"root", "root/src", "root/src/deep"}
+	if !slices.Equal(done, expectedDone) {
+		t.Errorf("expected completed dirs %v, got %v", expectedDone, done)
+	}
+
+	var ids []string
+	err = NewWalker(WithFilter(Name("*.go")), WithPrune(Name("vendor"))).IDWalk(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		ids = append(ids, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(ids, expected) {
+		t.Errorf("IDWalk: expected %v, got %v", expected, ids)
+	}
+
+	// Filters given more than once must all match
+	got, err = Collect(fsys, "root", WithFilter(Name("*.go")), WithFilter(Not(Name("a*"))))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []string{"root", "root/src/c.go", "root/vendor/e.go", "root/src/deep/f.go"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
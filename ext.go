@@ -0,0 +1,38 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// WithExtensions makes the Walker pass to the walk callback only the files
+// whose extension, as returned by [path.Ext], is one of exts, given with or
+// without the leading dot, such as ".jpg" or "png". Directories are still
+// visited and descended into, and the roots of the walk are always visited.
+// If the option is given more than once, the extensions are added to those
+// given before.
+func WithExtensions(exts ...string) Option {
+	exts = slices.Clone(exts)
+	for i, ext := range exts {
+		if !strings.HasPrefix(ext, ".") {
+			exts[i] = "." + ext
+		}
+	}
+	return func(w *Walker) {
+		w.exts = append(w.exts, exts...)
+	}
+}
+
+// otherExt reports whether d is a file left out of the walk by the
+// extensions of WithExtensions.
+func (w *Walker) otherExt(d fs.DirEntry) bool {
+	if d.IsDir() {
+		return false
+	}
+	ext := path.Ext(d.Name())
+	return !slices.ContainsFunc(w.exts, func(e string) bool {
+		return w.sameName(e, ext)
+	})
+}
@@ -0,0 +1,46 @@
+package bfwalk
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithExtensions(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/IMG_1.JPG":           {},
+		"root/img_2.jpg":           {},
+		"root/notes.txt":           {},
+		"root/Node_Modules/x.jpg":  {},
+		"root/uploads/photo.jpeg":  {},
+		"root/uploads/photo.png":   {},
+		"root/uploads/sub/raw.gif": {},
+	}
+
+	cases := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{"WithDot", []Option{WithExtensions(".jpg")}, []string{
+			"root", "root/Node_Modules", "root/img_2.jpg", "root/uploads", "root/Node_Modules/x.jpg", "root/uploads/sub",
+		}},
+		{"WithoutDot", []Option{WithExtensions("jpg", ".jpeg"), WithPruneNames("Node_Modules")}, []string{
+			"root", "root/img_2.jpg", "root/uploads", "root/uploads/photo.jpeg", "root/uploads/sub",
+		}},
+		{"Repeated", []Option{WithExtensions("png"), WithExtensions("gif")}, []string{
+			"root", "root/Node_Modules", "root/uploads", "root/uploads/photo.png", "root/uploads/sub", "root/uploads/sub/raw.gif",
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := Collect(memFS, "root", c.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, c.expected) {
+				t.Errorf("expected:\n  %v\ngot\n: %v", c.expected, got)
+			}
+		})
+	}
+}
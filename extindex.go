@@ -0,0 +1,38 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// IndexByExt walks the file tree rooted at root with a [Walker] configured
+// with opts and returns the paths of its files grouped by extension, as
+// returned by [path.Ext], such as ".css" or ".go". Files without an
+// extension are grouped under "". Paths of each extension are in the order
+// they are visited.
+//
+// With [WithExtensions], only files of the given extensions are indexed,
+// root included, and with [WithCaseInsensitive], extensions differing only
+// in case are grouped under their lower case form. The first error
+// encountered stops the walk and is returned, together with the index of
+// the files visited before it.
+func IndexByExt(fsys fs.FS, root string, opts ...Option) (map[string][]string, error) {
+	w := NewWalker(opts...)
+	index := make(map[string][]string)
+	err := w.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || len(w.exts) > 0 && w.otherExt(d) {
+			return nil
+		}
+		ext := path.Ext(d.Name())
+		if w.foldCase {
+			ext = strings.ToLower(ext)
+		}
+		index[ext] = append(index[ext], name)
+		return nil
+	})
+	return index, err
+}
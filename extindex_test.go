@@ -0,0 +1,75 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"maps"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestIndexByExt(t *testing.T) {
+	memFS := fstest.MapFS{
+		"site/index.html":          {},
+		"site/style.css":           {},
+		"site/LICENSE":             {},
+		"site/img/logo.PNG":        {},
+		"site/img/bg.png":          {},
+		"site/blog/post.html":      {},
+		"site/blog/archive.tar.gz": {},
+	}
+
+	cases := []struct {
+		name     string
+		opts     []Option
+		expected map[string][]string
+	}{
+		{"all", nil, map[string][]string{
+			".html": {"site/index.html", "site/blog/post.html"},
+			".css":  {"site/style.css"},
+			"":      {"site/LICENSE"},
+			".PNG":  {"site/img/logo.PNG"},
+			".png":  {"site/img/bg.png"},
+			".gz":   {"site/blog/archive.tar.gz"},
+		}},
+		{"extensions", []Option{WithExtensions("html", ".png")}, map[string][]string{
+			".html": {"site/index.html", "site/blog/post.html"},
+			".png":  {"site/img/bg.png"},
+		}},
+		{"case insensitive", []Option{WithExtensions(".png"), WithCaseInsensitive(), WithRelativePaths()}, map[string][]string{
+			".png": {"img/bg.png", "img/logo.PNG"},
+		}},
+	}
+	for _, c := range cases {
+		index, err := IndexByExt(memFS, "site", c.opts...)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if !maps.EqualFunc(index, c.expected, slices.Equal) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, index)
+		}
+	}
+
+	index, err := IndexByExt(memFS, "site/style.css", WithExtensions(".html"))
+	if err != nil || len(index) != 0 {
+		t.Errorf("expected empty index of a root of another extension, got %v, %v", index, err)
+	}
+}
+
+func TestIndexByExtError(t *testing.T) {
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/a.txt":     {},
+			"root/dir/b.txt": {},
+		},
+		errs: map[string]error{"root/dir": fs.ErrPermission},
+	}
+	index, err := IndexByExt(fsys, "root")
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("expected permission error, got %v", err)
+	}
+	if !slices.Equal(index[".txt"], []string{"root/a.txt"}) {
+		t.Errorf("expected files visited before the error, got %v", index)
+	}
+}
@@ -0,0 +1,87 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// A readdirFile is a directory handle listing its entries as file info,
+// like [os.File.Readdir] and the files of [net/http.FileSystem].
+type readdirFile interface {
+	Readdir(n int) ([]fs.FileInfo, error)
+}
+
+// listDir reads the directory name of fsys, sorting the entries by filename
+// if sorted. Sorted listings use [fs.ReadDirFS] if fsys implements it, and
+// unsorted ones the ReadDir or Readdir method of the directory opened with
+// Open, so that they are not sorted by fsys, falling back to fs.ReadDirFS
+// only when the directory cannot be opened or its handle cannot list its
+// entries. Unlike
+// [fs.ReadDir], it accepts directory handles that only implement Readdir.
+func listDir(fsys fs.FS, name string, sorted bool) ([]fs.DirEntry, error) {
+	rfs, isReadDirFS := fsys.(fs.ReadDirFS)
+	if isReadDirFS && sorted {
+		return rfs.ReadDir(name)
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		if isReadDirFS {
+			return rfs.ReadDir(name)
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var dirs []fs.DirEntry
+	switch f := f.(type) {
+	case fs.ReadDirFile:
+		dirs, err = f.ReadDir(-1)
+	case readdirFile:
+		var infos []fs.FileInfo
+		infos, err = f.Readdir(-1)
+		dirs = make([]fs.DirEntry, len(infos))
+		for i, info := range infos {
+			dirs[i] = fs.FileInfoToDirEntry(info)
+		}
+	default:
+		if isReadDirFS {
+			return rfs.ReadDir(name)
+		}
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not implemented")}
+	}
+	if sorted {
+		slices.SortFunc(dirs, func(a, b fs.DirEntry) int {
+			return strings.Compare(a.Name(), b.Name())
+		})
+	}
+	return dirs, err
+}
+
+// stat returns the file info of name in fsys with [fs.Stat], which uses
+// [fs.StatFS] if fsys implements it and otherwise the Stat method of the
+// file opened with Open. If that fails for a reason other than name not
+// existing, the file info is looked up among the entries of the parent
+// directory, for file systems that list files they cannot open.
+func stat(fsys fs.FS, name string) (fs.FileInfo, error) {
+	info, err := fs.Stat(fsys, name)
+	if err == nil || name == "." || errors.Is(err, fs.ErrNotExist) {
+		return info, err
+	}
+	dirs, lerr := listDir(fsys, path.Dir(name), false)
+	if lerr != nil {
+		return nil, err
+	}
+	base := path.Base(name)
+	for _, d := range dirs {
+		if d.Name() == base {
+			if info, ierr := d.Info(); ierr == nil {
+				return info, nil
+			}
+			break
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,152 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMinimalFS(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/b":        {},
+		"root/a/file":   {},
+		"root/a/c/file": {},
+	}
+	expected, err := Collect(memFS, "root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		fsys fs.FS
+		opts []Option
+	}{
+		{"Readdir", readdirFS{memFS}, nil},
+		{"ReaddirUnsorted", readdirFS{memFS}, []Option{WithUnsorted()}},
+		{"ListOnly", listOnlyFS{memFS}, nil},
+		{"ListOnlyUnsorted", listOnlyFS{memFS}, []Option{WithUnsorted()}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := Collect(c.fsys, "root", c.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := expected
+			if c.opts != nil {
+				slices.Sort(got)
+				expected = slices.Sorted(slices.Values(expected))
+			}
+			if !slices.Equal(got, expected) {
+				t.Errorf("expected %v, got %v", expected, got)
+			}
+		})
+	}
+}
+
+// readdirFS is a file system whose directories list their entries only as
+// file info, with a Readdir method.
+type readdirFS struct {
+	fsys fstest.MapFS
+}
+
+func (f readdirFS) Open(name string) (fs.File, error) {
+	file, err := f.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return infoDir{file}, nil
+}
+
+type infoDir struct {
+	fs.File
+}
+
+func (f infoDir) Readdir(n int) ([]fs.FileInfo, error) {
+	dir, ok := f.File.(fs.ReadDirFile)
+	if !ok {
+		return nil, errors.New("not a directory")
+	}
+	dirs, err := dir.ReadDir(n)
+	infos := make([]fs.FileInfo, 0, len(dirs))
+	for _, d := range dirs {
+		info, err := d.Info()
+		if err != nil {
+			return infos, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, err
+}
+
+// listOnlyFS is a file system that lists directories with ReadDir but
+// cannot open them.
+type listOnlyFS struct {
+	fsys fstest.MapFS
+}
+
+func (f listOnlyFS) Open(name string) (fs.File, error) {
+	if d, err := fs.Stat(f.fsys, name); err == nil && d.IsDir() {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errors.New("is a directory")}
+	}
+	return f.fsys.Open(name)
+}
+
+func (f listOnlyFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return f.fsys.ReadDir(name)
+}
+
+// countReadDirFS is a file system counting the calls of its ReadDir method.
+type countReadDirFS struct {
+	fs.FS
+	calls *int
+}
+
+func (f countReadDirFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	*f.calls++
+	return fs.ReadDir(f.FS, name)
+}
+
+func TestUnsortedSkipsReadDirFS(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b/file", "a/file", "c"} {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(name, nil, 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, ok := os.DirFS(dir).(fs.ReadDirFS); !ok {
+		t.Skip("os.DirFS does not implement fs.ReadDirFS")
+	}
+
+	calls := 0
+	fsys := countReadDirFS{os.DirFS(dir), &calls}
+	expected, err := Collect(fsys, ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls == 0 {
+		t.Errorf("expected sorted walk to list directories with fs.ReadDirFS")
+	}
+
+	calls = 0
+	got, err := Collect(fsys, ".", WithUnsorted())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected unsorted walk not to list directories with fs.ReadDirFS, got %d calls", calls)
+	}
+	slices.Sort(got)
+	if expected = slices.Sorted(slices.Values(expected)); !slices.Equal(got, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, got)
+	}
+}
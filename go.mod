@@ -1,3 +1,9 @@
 module github.com/eriicafes/bfwalk
 
 go 1.24.0
+
+require (
+	github.com/fsnotify/fsnotify v1.9.0
+	golang.org/x/sys v0.13.0
+	golang.org/x/text v0.21.0
+)
@@ -0,0 +1,74 @@
+package bfwalk
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"iter"
+	"regexp"
+	"strings"
+)
+
+// A GrepMatch is a line of a file matched by [Grep].
+type GrepMatch struct {
+	Path string
+	Line int    // line number, counting from 1
+	Text string // line without its line break, "\n" or "\r\n"
+}
+
+// Grep walks the file tree rooted at root and returns an iterator over the
+// lines of its regular files that match pattern, searching the files on a
+// pool of workers goroutines.
+//
+// Matches are yielded file by file in breadth-first order, the order in
+// which [WalkDir] visits the files, so that matches in shallower files come
+// first, and in line order within each file. Errors reading a directory or
+// file are yielded with the path they occurred at, after any matches found
+// in the file before the error, and the walk continues. Stopping the
+// iteration stops the walk and waits for the workers to exit.
+func Grep(fsys fs.FS, root string, pattern *regexp.Regexp, workers int) iter.Seq2[GrepMatch, error] {
+	return func(yield func(GrepMatch, error) bool) {
+		files := walkFiles(fsys, root, workers, func(name string) ([]GrepMatch, error) {
+			return grepFile(fsys, name, pattern)
+		})
+		for r := range files {
+			for _, m := range r.value {
+				if !yield(m, nil) {
+					return
+				}
+			}
+			if r.err != nil && !yield(GrepMatch{Path: r.path}, r.err) {
+				return
+			}
+		}
+	}
+}
+
+// grepFile returns the lines of the file name that match pattern.
+func grepFile(fsys fs.FS, name string, pattern *regexp.Regexp) ([]GrepMatch, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var matches []GrepMatch
+	r := bufio.NewReader(f)
+	for n := 1; ; n++ {
+		line, err := r.ReadString('\n')
+		if line != "" {
+			if l, ok := strings.CutSuffix(line, "\n"); ok {
+				line = strings.TrimSuffix(l, "\r")
+			}
+			if pattern.MatchString(line) {
+				matches = append(matches, GrepMatch{name, n, line})
+			}
+		}
+		if err == io.EOF {
+			return matches, nil
+		}
+		if err != nil {
+			return matches, err
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package bfwalk
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGrep(t *testing.T) {
+	errBad := errors.New("bad file")
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/a.go":          {Data: []byte("package a\n// TODO: one\nfunc A() {}\n")},
+			"root/b.txt":         {Data: []byte("no match here")},
+			"root/bad.go":        {Data: []byte("TODO")},
+			"root/dirA/c.go":     {Data: []byte("// TODO: two\r\n\n// TODO: three")},
+			"root/dirA/sub/d.go": {Data: []byte("// TODO: four\n")},
+		},
+		errs: map[string]error{"root/bad.go": errBad},
+	}
+	todo := regexp.MustCompile(`TODO`)
+
+	for _, workers := range []int{1, 4} {
+		var got []string
+		for m, err := range Grep(fsys, "root", todo, workers) {
+			if err != nil {
+				if !errors.Is(err, errBad) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				got = append(got, "error "+m.Path)
+				continue
+			}
+			got = append(got, fmt.Sprintf("%s:%d:%s", m.Path, m.Line, m.Text))
+		}
+		expected := []string{
+			"root/a.go:2:// TODO: one",
+			"error root/bad.go",
+			"root/dirA/c.go:1:// TODO: two",
+			"root/dirA/c.go:3:// TODO: three",
+			"root/dirA/sub/d.go:1:// TODO: four",
+		}
+		if !slices.Equal(got, expected) {
+			t.Errorf("workers %d: expected:\n  %q\ngot\n: %q", workers, expected, got)
+		}
+	}
+
+	// Patterns anchored at the end of the line match lines ending in "\r\n"
+	var lines []int
+	for m, err := range Grep(fsys.MapFS, "root/dirA", regexp.MustCompile(`two$`), 1) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		lines = append(lines, m.Line)
+	}
+	if !slices.Equal(lines, []int{1}) {
+		t.Errorf("expected a match on line 1, got %v", lines)
+	}
+}
+
+func TestGrepStop(t *testing.T) {
+	fsys := generateFS("root", 10, 3)
+	pattern := regexp.MustCompile(`.`)
+	n := 0
+	for _, err := range Grep(fsys, "root", pattern, 4) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("expected 3 matches before stopping, got %d", n)
+	}
+
+	var paths []string
+	for m := range Grep(fstest.MapFS{"x": {Data: []byte("x")}}, ".", pattern, 0) {
+		paths = append(paths, m.Path)
+	}
+	if !slices.Equal(paths, []string{"x"}) {
+		t.Errorf("expected [x] with no workers, got %v", paths)
+	}
+}
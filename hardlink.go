@@ -0,0 +1,52 @@
+package bfwalk
+
+// WithDedupeHardlinks makes the Walker pass each file with several hard
+// links to the walk callback only once, under the first path it is found
+// at, so that totals over the walk count each physical file once. If onLink
+// is not nil, it is called instead of the callback for each other path of
+// the file, with the path the file was reported under.
+//
+// Files are identified by the device and inode number in their file info,
+// which is read for every entry that is not a directory. File systems of
+// the operating system, such as [os.DirFS], report them on Unix systems;
+// other entries, including all entries on Windows, are always visited. The
+// roots of the walk are always visited.
+func WithDedupeHardlinks(onLink func(path, first string)) Option {
+	return func(w *Walker) {
+		w.dedupe = true
+		if onLink != nil {
+			w.onLink = append(w.onLink, onLink)
+		}
+	}
+}
+
+// A fileID identifies a physical file.
+type fileID struct {
+	dev, ino uint64
+}
+
+// isLinkSeen reports whether the entry e is another hard link to a file
+// visited before, calling the link hooks if so. Otherwise it records the
+// path of e for the file, if e has other hard links.
+func (w *Walker) isLinkSeen(e namedEntry) bool {
+	if e.d.IsDir() {
+		return false
+	}
+	id, ok := hardlinkID(e.d)
+	if !ok {
+		return false
+	}
+	reported := w.report(e.root, e.name)
+	first, ok := w.links[id]
+	if !ok {
+		if w.links == nil {
+			w.links = make(map[fileID]string)
+		}
+		w.links[id] = reported
+		return false
+	}
+	for _, fn := range w.onLink {
+		fn(reported, first)
+	}
+	return true
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package bfwalk
+
+import "io/fs"
+
+// hardlinkID returns the identity of the file of d, which file info does
+// not hold on this system.
+func hardlinkID(d fs.DirEntry) (fileID, bool) {
+	return fileID{}, false
+}
@@ -0,0 +1,61 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestWithDedupeHardlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hard links are not identified on Windows")
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"a", "b/sub"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b/file.txt"), []byte("data"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "single.txt"), nil, 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"a/link.txt", "b/sub/link.txt"} {
+		if err := os.Link(filepath.Join(dir, "b/file.txt"), filepath.Join(dir, name)); err != nil {
+			t.Skipf("cannot create hard links: %v", err)
+		}
+	}
+
+	var links [][2]string
+	w := NewWalker(WithDedupeHardlinks(func(path, first string) {
+		links = append(links, [2]string{path, first})
+	}))
+	walks := []func(*Walker, fs.FS, string, fs.WalkDirFunc) error{(*Walker).WalkDir, (*Walker).WalkDir, (*Walker).IDWalk}
+	for _, walk := range walks {
+		links = nil
+		var paths []string
+		err := walk(w, os.DirFS(dir), ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			paths = append(paths, path)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []string{".", "a", "b", "single.txt", "a/link.txt", "b/sub"}
+		if !slices.Equal(paths, expected) {
+			t.Errorf("expected:\n  %v\ngot\n: %v", expected, paths)
+		}
+		expectedLinks := [][2]string{{"b/file.txt", "a/link.txt"}, {"b/sub/link.txt", "a/link.txt"}}
+		if !slices.Equal(links, expectedLinks) {
+			t.Errorf("expected links:\n  %v\ngot\n: %v", expectedLinks, links)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+//go:build unix
+
+package bfwalk
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// hardlinkID returns the identity of the file of d, reporting false if its
+// file info does not hold it or the file has a single link.
+func hardlinkID(d fs.DirEntry) (fileID, bool) {
+	info, err := d.Info()
+	if err != nil {
+		return fileID{}, false
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || st.Nlink < 2 {
+		return fileID{}, false
+	}
+	return fileID{uint64(st.Dev), uint64(st.Ino)}, true
+}
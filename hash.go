@@ -0,0 +1,82 @@
+package bfwalk
+
+import (
+	"hash"
+	"io"
+	"io/fs"
+	"iter"
+)
+
+// A FileHash is the digest of a file computed by [HashWalk].
+type FileHash struct {
+	Path   string
+	Digest []byte
+}
+
+// HashWalk walks the file tree rooted at root and returns an iterator over
+// the digests of its regular files, computed with hash functions returned by
+// hasher on a pool of workers goroutines.
+//
+// Digests are yielded in breadth-first order, the order in which [WalkDir]
+// visits the files, regardless of which worker finishes first. Errors
+// reading a directory or file are yielded with the path they occurred at,
+// and the walk continues. Stopping the iteration stops the walk and waits
+// for the workers to exit.
+func HashWalk(fsys fs.FS, root string, hasher func() hash.Hash, workers int) iter.Seq2[FileHash, error] {
+	return func(yield func(FileHash, error) bool) {
+		hashes := walkFiles(fsys, root, workers, func(name string) ([]byte, error) {
+			return hashFile(fsys, name, hasher())
+		})
+		for r := range hashes {
+			if !yield(FileHash{r.path, r.value}, r.err) {
+				return
+			}
+		}
+	}
+}
+
+// A fileResult is the result of processing a file for [walkFiles], or an
+// error reading the directory or file at path.
+type fileResult[T any] struct {
+	path  string
+	value T
+	err   error
+}
+
+// walkFiles walks the file tree rooted at root and returns an iterator over
+// the results of calling fn for each of its regular files on a pool of
+// workers goroutines. Results are yielded in breadth-first order, along
+// with errors reading directories, and the walk continues after errors.
+// Stopping the iteration stops the walk and waits for the workers to exit.
+func walkFiles[T any](fsys fs.FS, root string, workers int, fn func(name string) (T, error)) iter.Seq[fileResult[T]] {
+	files := func(yield func(fileResult[T]) bool) {
+		WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.Type().IsRegular() {
+				return nil
+			}
+			if !yield(fileResult[T]{path: path, err: err}) {
+				return fs.SkipAll
+			}
+			return nil
+		})
+	}
+	return process(files, workers, func(r fileResult[T]) fileResult[T] {
+		if r.err == nil {
+			r.value, r.err = fn(r.path)
+		}
+		return r
+	})
+}
+
+// hashFile returns the digest of the contents of the file name.
+func hashFile(fsys fs.FS, name string, h hash.Hash) ([]byte, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
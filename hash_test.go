@@ -0,0 +1,70 @@
+package bfwalk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHashWalk(t *testing.T) {
+	fsys := generateFS("data", 10, 3)
+
+	var expected []string
+	WalkDir(fsys, "data", func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			expected = append(expected, path)
+		}
+		return err
+	})
+
+	for _, workers := range []int{0, 1, 8} {
+		var visited []string
+		for h, err := range HashWalk(fsys, "data", sha256.New, workers) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sum := sha256.Sum256(fsys.(fstest.MapFS)[h.Path].Data)
+			if !bytes.Equal(h.Digest, sum[:]) {
+				t.Errorf("unexpected digest for %s", h.Path)
+			}
+			visited = append(visited, h.Path)
+		}
+		if !slices.Equal(visited, expected) {
+			t.Errorf("%d workers: expected:\n  %v\ngot\n: %v", workers, expected, visited)
+		}
+	}
+}
+
+func TestHashWalkStop(t *testing.T) {
+	fsys := generateFS("data", 10, 3)
+
+	n := 0
+	for _, err := range HashWalk(fsys, "data", sha256.New, 4) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		n++
+		if n == 5 {
+			break
+		}
+	}
+	if n != 5 {
+		t.Errorf("expected 5 results, got %d", n)
+	}
+}
+
+func TestHashWalkError(t *testing.T) {
+	var paths []string
+	for h, err := range HashWalk(fstest.MapFS{}, "missing", sha256.New, 2) {
+		if err == nil {
+			t.Errorf("expected error for %s", h.Path)
+		}
+		paths = append(paths, h.Path)
+	}
+	if !slices.Equal(paths, []string{"missing"}) {
+		t.Errorf("unexpected results: %v", paths)
+	}
+}
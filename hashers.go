@@ -0,0 +1,50 @@
+package bfwalk
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"hash"
+	"hash/crc32"
+)
+
+// The built-in hash functions below can be passed wherever a function
+// returning a [hash.Hash] is accepted, as by [HashWalk], [WriteManifest] and
+// [FindDuplicatesWith]. Any other such function, such as one from
+// golang.org/x/crypto, can be passed as well.
+//
+// SHA256 is the default where a hash function is not given. SHA1 is no
+// longer collision resistant, and CRC32 and CRC32C are not cryptographic at
+// all, so they suit finding accidental changes rather than deliberate ones.
+// CRC32C is the fastest, as most CPUs compute it in hardware.
+
+// SHA256 returns a new [hash.Hash] computing the SHA-256 checksum.
+func SHA256() hash.Hash { return sha256.New() }
+
+// SHA1 returns a new [hash.Hash] computing the SHA-1 checksum.
+func SHA1() hash.Hash { return sha1.New() }
+
+// CRC32 returns a new [hash.Hash] computing the CRC-32 checksum with the
+// IEEE polynomial.
+func CRC32() hash.Hash { return crc32.NewIEEE() }
+
+// CRC32C returns a new [hash.Hash] computing the CRC-32 checksum with the
+// Castagnoli polynomial.
+func CRC32C() hash.Hash { return crc32.New(castagnoli) }
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// LookupHasher returns the built-in hash function named name, one of
+// "sha256", "sha1", "crc32" and "crc32c", reporting false if there is none.
+func LookupHasher(name string) (func() hash.Hash, bool) {
+	switch name {
+	case "sha256":
+		return SHA256, true
+	case "sha1":
+		return SHA1, true
+	case "crc32":
+		return CRC32, true
+	case "crc32c":
+		return CRC32C, true
+	}
+	return nil, false
+}
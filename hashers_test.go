@@ -0,0 +1,50 @@
+package bfwalk
+
+import (
+	"encoding/hex"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLookupHasher(t *testing.T) {
+	cases := []struct {
+		name, digest string
+	}{
+		{"sha256", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{"sha1", "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d"},
+		{"crc32", "3610a686"},
+		{"crc32c", "9a71bb4c"},
+	}
+	for _, c := range cases {
+		h, ok := LookupHasher(c.name)
+		if !ok {
+			t.Fatalf("expected hasher %q", c.name)
+		}
+		hh := h()
+		hh.Write([]byte("hello"))
+		if got := hex.EncodeToString(hh.Sum(nil)); got != c.digest {
+			t.Errorf("%s: expected %s, got %s", c.name, c.digest, got)
+		}
+	}
+	if _, ok := LookupHasher("md4"); ok {
+		t.Errorf("expected no hasher for md4")
+	}
+}
+
+func TestFindDuplicatesWith(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/a.txt":      {Data: []byte("same")},
+		"root/b.txt":      {Data: []byte("diff")},
+		"root/dirA/c.txt": {Data: []byte("same")},
+	}
+	for _, h := range []string{"sha1", "crc32c"} {
+		hasher, _ := LookupHasher(h)
+		groups, err := FindDuplicatesWith(memFS, "root", hasher)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(groups) != 1 || len(groups[0]) != 2 || groups[0][0] != "root/a.txt" || groups[0][1] != "root/dirA/c.txt" {
+			t.Errorf("%s: unexpected groups %v", h, groups)
+		}
+	}
+}
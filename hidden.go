@@ -0,0 +1,24 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// WithSkipHidden makes the Walker leave hidden files and directories out of
+// the walk: they are not passed to the walk callback and hidden directories
+// are not descended into. The roots of the walk are always visited.
+//
+// An entry is hidden if its name starts with a dot. On Windows, entries
+// with the hidden file attribute are also hidden, if the file system
+// reports it in the entry's file info as [os.DirFS] does.
+func WithSkipHidden() Option {
+	return func(w *Walker) {
+		w.prune = append(w.prune, isHidden)
+	}
+}
+
+// isHidden reports whether the directory entry d is hidden.
+func isHidden(d fs.DirEntry) bool {
+	return strings.HasPrefix(d.Name(), ".") || hasHiddenAttr(d)
+}
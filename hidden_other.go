@@ -0,0 +1,11 @@
+//go:build !windows
+
+package bfwalk
+
+import "io/fs"
+
+// hasHiddenAttr reports whether d has the Windows hidden file attribute,
+// which is never the case on other systems.
+func hasHiddenAttr(d fs.DirEntry) bool {
+	return false
+}
@@ -0,0 +1,41 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithSkipHidden(t *testing.T) {
+	memFS := fstest.MapFS{
+		".root/file1.txt":             {Data: []byte("")},
+		".root/.env":                  {Data: []byte("")},
+		".root/.git/config":           {Data: []byte("")},
+		".root/dirA/file1.txt":        {Data: []byte("")},
+		".root/dirA/.cache/file1.txt": {Data: []byte("")},
+	}
+
+	var visited []string
+	w := NewWalker(WithSkipHidden())
+	err := w.WalkDir(memFS, ".root", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		".root",
+		".root/dirA",
+		".root/file1.txt",
+		".root/dirA/file1.txt",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
@@ -0,0 +1,16 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// hasHiddenAttr reports whether d has the Windows hidden file attribute.
+func hasHiddenAttr(d fs.DirEntry) bool {
+	info, err := d.Info()
+	if err != nil {
+		return false
+	}
+	attrs, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	return ok && attrs.FileAttributes&syscall.FILE_ATTRIBUTE_HIDDEN != 0
+}
@@ -0,0 +1,109 @@
+package bfwalk
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithWalkHooks makes the Walker call onStart when a walk starts, and onEnd
+// with the error the walk returns once it is done. Either function may be
+// nil. The hooks are called for every walk of the Walker, including walks
+// resumed with [Walker.Resume].
+func WithWalkHooks(onStart func(), onEnd func(err error)) Option {
+	return func(w *Walker) {
+		if onStart != nil {
+			w.onStart = append(w.onStart, onStart)
+		}
+		if onEnd != nil {
+			w.onEnd = append(w.onEnd, onEnd)
+		}
+	}
+}
+
+// WithReadDirHook makes the Walker call fn after each directory read,
+// including any retries, with the path of the directory as reported to the
+// walk callback, the time the read took, the number of entries read and
+// the error it returned. Directories listed in pages are reported once for
+// each page.
+func WithReadDirHook(fn func(path string, elapsed time.Duration, entries int, err error)) Option {
+	return func(w *Walker) {
+		w.onReadDir = append(w.onReadDir, fn)
+	}
+}
+
+// A WalkObserver observes a single walk, as returned by the function passed
+// to [WithWalkObserver]. Either function may be nil.
+type WalkObserver struct {
+	// ReadDir is called after each directory read of the walk, like the
+	// hook of WithReadDirHook.
+	ReadDir func(path string, elapsed time.Duration, entries int, err error)
+
+	// End is called once the walk is done, with its statistics and the
+	// error it returns.
+	End func(s Stats, err error)
+
+	// Context, if not nil, replaces the context of the walk, such as with a
+	// context holding a span of the walk. It is passed to the callback of
+	// [Walker.WalkDirContext] and to the observers started after this one,
+	// and must be done when the context of the walk is. Walks without a
+	// context, such as those of [Walker.WalkDir], do not pass it on.
+	Context context.Context
+}
+
+// WithWalkObserver makes the Walker call start when each walk starts, with
+// the context of the walk given to [Walker.WalkDirContext] or [Walker.Run],
+// or [context.Background], and report the walk to the [WalkObserver] start
+// returns.
+//
+// Unlike the hooks of [WithWalkHooks] and [WithReadDirHook], which are
+// shared by every walk of the Walker, an observer holds the state of a
+// single walk, so that walks with Walker.Run can be observed while they run
+// at once.
+func WithWalkObserver(start func(ctx context.Context) WalkObserver) Option {
+	return func(w *Walker) {
+		w.observe = append(w.observe, start)
+	}
+}
+
+// start prepares the Walker for a new walk.
+func (w *Walker) start() {
+	w.reset()
+	w.debugLog = w.logger != nil && w.logger.Enabled(context.Background(), slog.LevelDebug)
+	for _, fn := range w.onStart {
+		fn()
+	}
+	if len(w.observe) > 0 {
+		ctx := w.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		for _, start := range w.observe {
+			o := start(ctx)
+			if o.Context != nil {
+				ctx = o.Context
+			}
+			w.observers = append(w.observers, o)
+		}
+		if w.ctx != nil {
+			w.ctx = ctx
+		}
+	}
+}
+
+// finish ends the walk in progress, which returns err.
+func (w *Walker) finish(err error) {
+	w.stopped(w.stopReason(err))
+	w.endLevel()
+	for _, fn := range w.onEnd {
+		fn(err)
+	}
+	for _, o := range w.observers {
+		if o.End != nil {
+			o.End(w.Stats(), err)
+		}
+	}
+	if len(w.subscribers) > 0 {
+		w.emit(Event{Kind: WalkDone, Err: err})
+	}
+}
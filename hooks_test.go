@@ -0,0 +1,168 @@
+package bfwalk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestWithWalkHooks(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt": {Data: []byte("")},
+	}
+	errStop := errors.New("stop")
+
+	var events []string
+	w := NewWalker(WithWalkHooks(
+		func() { events = append(events, "start") },
+		func(err error) { events = append(events, fmt.Sprint("end ", err)) },
+	))
+	err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		events = append(events, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		return errStop
+	})
+	if err != errStop {
+		t.Fatalf("expected error %v, got %v", errStop, err)
+	}
+
+	expected := []string{
+		"start",
+		"root",
+		"root/file1.txt",
+		"end <nil>",
+		"start",
+		"end stop",
+	}
+	if !slices.Equal(events, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, events)
+	}
+}
+
+func TestWithReadDirHook(t *testing.T) {
+	errRead := errors.New("read failed")
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/file1.txt":      {Data: []byte("")},
+			"root/dirA/file1.txt": {Data: []byte("")},
+			"root/dirB/file1.txt": {Data: []byte("")},
+		},
+		errs: map[string]error{"root/dirB": errRead},
+	}
+
+	var reads []string
+	w := NewWalker(WithRelativePaths(), WithReadDirHook(func(path string, elapsed time.Duration, entries int, err error) {
+		if elapsed < 0 {
+			t.Errorf("negative duration for %s", path)
+		}
+		reads = append(reads, fmt.Sprint(path, " ", entries, " ", err))
+	}))
+	err := w.WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		". 3 <nil>",
+		"dirA 1 <nil>",
+		"dirB 0 read failed",
+	}
+	if !slices.Equal(reads, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, reads)
+	}
+}
+
+func TestWithWalkObserver(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":      {Data: []byte("")},
+		"root/dirA/file1.txt": {Data: []byte("")},
+	}
+	type ctxKey struct{}
+
+	var mu sync.Mutex
+	var ends []string
+	w := NewWalker(WithWalkObserver(func(ctx context.Context) WalkObserver {
+		name, _ := ctx.Value(ctxKey{}).(string)
+		var reads []string // State of this walk only
+		return WalkObserver{
+			ReadDir: func(path string, elapsed time.Duration, entries int, err error) {
+				reads = append(reads, fmt.Sprintf("%s:%d", path, entries))
+			},
+			End: func(s Stats, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				ends = append(ends, fmt.Sprintf("%s %v visited=%d %v", name, reads, s.Visited, err))
+			},
+		}
+	}))
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"a", "b", "c"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := context.WithValue(context.Background(), ctxKey{}, name)
+			w.Run(ctx, memFS, "root", func(path string, d fs.DirEntry, err error) error {
+				return err
+			})
+		}()
+	}
+	wg.Wait()
+	w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		return err
+	})
+
+	slices.Sort(ends)
+	expected := []string{
+		" [root:2 root/dirA:1] visited=4 <nil>",
+		"a [root:2 root/dirA:1] visited=4 <nil>",
+		"b [root:2 root/dirA:1] visited=4 <nil>",
+		"c [root:2 root/dirA:1] visited=4 <nil>",
+	}
+	if !slices.Equal(ends, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, ends)
+	}
+}
+
+func TestWithWalkObserverContext(t *testing.T) {
+	memFS := fstest.MapFS{"root/file1.txt": {Data: []byte("")}}
+	type ctxKey struct{}
+
+	var started []string
+	observer := func(name string) Option {
+		return WithWalkObserver(func(ctx context.Context) WalkObserver {
+			prev, _ := ctx.Value(ctxKey{}).(string)
+			started = append(started, prev+">"+name)
+			return WalkObserver{Context: context.WithValue(ctx, ctxKey{}, name)}
+		})
+	}
+	w := NewWalker(observer("a"), observer("b"))
+	var seen []string
+	err := w.WalkDirContext(context.Background(), memFS, "root", func(ctx context.Context, path string, d fs.DirEntry, err error) error {
+		name, _ := ctx.Value(ctxKey{}).(string)
+		seen = append(seen, name)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{">a", "a>b"}; !slices.Equal(started, expected) {
+		t.Errorf("expected %v, got %v", expected, started)
+	}
+	if expected := []string{"b", "b"}; !slices.Equal(seen, expected) {
+		t.Errorf("expected %v, got %v", expected, seen)
+	}
+}
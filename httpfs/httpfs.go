@@ -0,0 +1,104 @@
+// Package httpfs adapts an [http.FileSystem] to an [fs.FS], so that asset
+// bundles only exposed as an http.FileSystem can be walked with
+// [bfwalk.WalkDir].
+package httpfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// New returns a file system serving the files of hfs. Paths are looked up in
+// hfs with a leading slash, so "dir/file" opens "/dir/file".
+//
+// The returned file system implements [fs.ReadDirFS] and [fs.StatFS], and the
+// directories it opens implement [fs.ReadDirFile], on top of the Readdir and
+// Stat methods of [http.File].
+func New(hfs http.FileSystem) fs.FS {
+	return &httpFS{hfs}
+}
+
+type httpFS struct {
+	hfs http.FileSystem
+}
+
+func (f *httpFS) open(op, name string) (http.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	hname := "/" + name
+	if name == "." {
+		hname = "/"
+	}
+	file, err := f.hfs.Open(hname)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: unwrap(err)}
+	}
+	return file, nil
+}
+
+func (f *httpFS) Open(name string) (fs.File, error) {
+	file, err := f.open("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return &httpFile{File: file, name: name}, nil
+}
+
+func (f *httpFS) Stat(name string) (fs.FileInfo, error) {
+	file, err := f.open("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: unwrap(err)}
+	}
+	return info, nil
+}
+
+func (f *httpFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	file, err := f.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	entries, err := file.(*httpFile).ReadDir(-1)
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, err
+}
+
+// httpFile is an [http.File] opened from an [httpFS].
+type httpFile struct {
+	http.File
+	name string
+}
+
+func (f *httpFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	infos, err := f.File.Readdir(n)
+	entries := make([]fs.DirEntry, len(infos))
+	for i, info := range infos {
+		entries[i] = fs.FileInfoToDirEntry(info)
+	}
+	if err != nil && err != io.EOF {
+		err = &fs.PathError{Op: "readdir", Path: f.name, Err: unwrap(err)}
+	}
+	return entries, err
+}
+
+// unwrap returns the underlying error of a path error reported by hfs, so
+// that it can be reported with the fs.FS path instead.
+func unwrap(err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package httpfs
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"root/file1.txt", "root/dirA/file1.txt", "root/dirB/sub/file1.txt"} {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(name, []byte("hello"), 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	fsys := New(http.Dir(dir))
+
+	var visited []string
+	err := bfwalk.WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"root",
+		"root/dirA",
+		"root/dirB",
+		"root/file1.txt",
+		"root/dirA/file1.txt",
+		"root/dirB/sub",
+		"root/dirB/sub/file1.txt",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+
+	if err := fstest.TestFS(fsys, "root/file1.txt", "root/dirA/file1.txt", "root/dirB/sub/file1.txt"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	fsys := New(http.Dir(t.TempDir()))
+	if _, err := fs.Stat(fsys, "missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected %v, got %v", fs.ErrNotExist, err)
+	}
+	if _, err := fsys.Open("../escape"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("expected %v, got %v", fs.ErrInvalid, err)
+	}
+}
+
+func TestNewDotfiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{".env", "root/.env"} {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(name, []byte("KEY=value"), 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	fsys := New(http.Dir(dir))
+
+	for _, name := range []string{".env", "root/.env"} {
+		if data, err := fs.ReadFile(fsys, name); err != nil || string(data) != "KEY=value" {
+			t.Errorf("ReadFile(%s): unexpected result %q, %v", name, data, err)
+		}
+	}
+	paths, err := bfwalk.Collect(fsys, ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{".", ".env", "root", "root/.env"}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, paths)
+	}
+}
@@ -0,0 +1,194 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"path"
+)
+
+// IDWalk walks the file tree rooted at root by iterative deepening, calling
+// fn for each file or directory in the tree, including root, in the same
+// order as [WalkDir].
+//
+// Instead of keeping every directory of the next level in memory, IDWalk
+// walks the tree depth-first once for each level, down to that level, and
+// calls fn only for the entries first reached in that pass. Directories are
+// read once for each level below them, trading repeated reads for memory
+// proportional to the depth of the tree. Directories skipped with
+// [fs.SkipDir] are remembered so that they stay skipped in later passes.
+//
+// A directory that cannot be read is reported once, in the pass that first
+// reads it; later passes walk any entries it did list without reporting the
+// error again.
+func IDWalk(fsys fs.FS, root string, fn fs.WalkDirFunc) error {
+	return NewWalker().IDWalk(fsys, root, fn)
+}
+
+// IDWalk walks the file tree rooted at root by iterative deepening like the
+// package-level [IDWalk] function, with the traversal adjusted by the
+// options the Walker was created with. Directories left out by
+// [WithSampling] are chosen as in [Walker.WalkDir], and the hooks of
+// [WithOnDirComplete] are called in the pass that visits the entries of each
+// directory. [WithPriority] and [WithStrategy] have no effect on IDWalk, and
+// [Walker.Checkpoint] cannot be used to resume it.
+func (w *Walker) IDWalk(fsys fs.FS, root string, fn fs.WalkDirFunc) (err error) {
+	w.start()
+	w.deepening = true
+	defer func() { w.finish(err) }()
+	err = w.idWalk(fsys, root, fn)
+	if err == fs.SkipDir || err == fs.SkipAll {
+		return nil
+	}
+	return err
+}
+
+func (w *Walker) idWalk(fsys fs.FS, root string, fn fs.WalkDirFunc) error {
+	w.throttle()
+	info, err := stat(fsys, root)
+	if err != nil {
+		return w.visit(fn, namedEntry{root, nil, root, 0, nil}, err)
+	}
+	if err := w.spend(1); err != nil {
+		return err
+	}
+	d := NewCachedEntry(fs.FileInfoToDirEntry(info))
+	dir := namedEntry{root, d, root, 0, nil}
+	if err := w.visit(fn, dir, nil); err != nil {
+		return err
+	}
+	if !d.IsDir() || !w.shouldDescend(dir) {
+		return nil
+	}
+
+	p := idPass{skip: make(map[string]bool)}
+	for p.limit = 1; ; p.limit++ {
+		p.more = false
+		if err := w.idVisit(fsys, dir, &p, fn); err != nil {
+			return err
+		}
+		if !p.more {
+			return nil
+		}
+	}
+}
+
+// An idPass holds the state of a single pass of [Walker.IDWalk].
+type idPass struct {
+	limit int             // depth of the entries reported in this pass
+	more  bool            // whether entries below limit may exist
+	skip  map[string]bool // directories not to descend into
+}
+
+// idVisit walks the directory dir down to the depth limit of p, calling fn
+// for the entries at that depth.
+func (w *Walker) idVisit(fsys fs.FS, dir namedEntry, p *idPass, fn fs.WalkDirFunc) error {
+	if err := w.interrupted(); err != nil {
+		return w.interruption(err)
+	}
+	report := dir.depth+1 == p.limit
+	if report && w.sampling {
+		weight := w.weight
+		w.weight = w.dirWeight(dir)
+		defer func() { w.weight = weight }()
+	}
+
+	token, visited, selected := "", 0, 0
+	var descended map[string]bool // with WithDedupePaths, directories entered from dir
+	var found []namedEntry        // with WithSampling, subdirectories to descend into
+	for {
+		r := w.readDir(fsys, dir, token)
+		if err := w.spend(r.listed); err != nil {
+			return err
+		}
+		dirs, next, err := r.dirs, r.next, r.err
+		if w.maxPerDir > 0 {
+			dirs, next = w.selectPage(dirs, next, &selected)
+		}
+		if err != nil && report && w.denied(dir, err) {
+			err, next = nil, ""
+		}
+		if err != nil {
+			if report {
+				// Second call, to report ReadDir error.
+				err = w.visit(fn, dir, err)
+				if err != nil {
+					if err == fs.SkipDir {
+						p.skip[dir.name] = true
+						err = nil
+					}
+					return err
+				}
+			}
+			next = "" // Pages after a failed read cannot be listed
+		}
+
+		for _, d1 := range dirs {
+			name1 := path.Join(dir.name, d1.Name())
+			entry := namedEntry{name1, d1, dir.root, dir.depth + 1, r.ignore}
+			if !report {
+				if !d1.IsDir() || p.skip[name1] || w.dedupePaths && descended[name1] {
+					continue
+				}
+				if w.dedupePaths {
+					if descended == nil {
+						descended = make(map[string]bool)
+					}
+					descended[name1] = true
+				}
+				if err := w.idVisit(fsys, entry, p, fn); err != nil {
+					return err
+				}
+				continue
+			}
+			if w.dedupePaths && w.isPathSeen(name1) || w.dedupe && w.isLinkSeen(entry) {
+				continue
+			}
+			var err error
+			if w.matches(d1) {
+				visited++
+				err = w.visit(fn, entry, nil)
+			}
+			if err != nil {
+				if err == fs.SkipDir {
+					if d1.IsDir() {
+						p.skip[name1] = true
+						continue // Skip current directory
+					}
+					p.skip[dir.name] = true
+					w.dirDone(dir, visited)
+					return nil // Skip parent directory
+				}
+				return err
+			}
+			if d1.IsDir() {
+				switch {
+				case !w.shouldDescend(entry):
+					p.skip[name1] = true
+				case w.sampling:
+					found = append(found, entry)
+				default:
+					p.more = true
+				}
+			}
+		}
+		if next == "" {
+			if report {
+				w.sampleSkip(dir, found, p)
+				w.dirDone(dir, visited)
+			}
+			return nil
+		}
+		token = next
+	}
+}
+
+// sampleSkip marks the subdirectories of dir found during its visit that
+// are not sampled as skipped in p.
+func (w *Walker) sampleSkip(dir namedEntry, found []namedEntry, p *idPass) {
+	for _, sub := range found {
+		p.skip[sub.name] = true
+	}
+	for _, sub := range w.sampleDirs(dir, found) {
+		delete(p.skip, sub.name)
+		p.more = true
+	}
+}
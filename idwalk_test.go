@@ -0,0 +1,101 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestIDWalk(t *testing.T) {
+	errRead := errors.New("read failed")
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/file1.txt":          {Data: []byte("")},
+			"root/dirA/file1.txt":     {Data: []byte("")},
+			"root/dirB/afile1.txt":    {Data: []byte("")},
+			"root/dirB/file1.txt":     {Data: []byte("")},
+			"root/dirB/sub/file1.txt": {Data: []byte("")},
+			"root/dirB/zfile1.txt":    {Data: []byte("")},
+			"root/dirC/sub/file1.txt": {Data: []byte("")},
+		},
+		errs: map[string]error{"root/dirC/sub": errRead},
+	}
+
+	// IDWalk must visit entries exactly like WalkDir
+	tests := map[string]func(path string, d fs.DirEntry, err error) error{
+		"all": func(path string, d fs.DirEntry, err error) error {
+			return nil
+		},
+		"skip dir": func(path string, d fs.DirEntry, err error) error {
+			if path == "root/dirB" {
+				return fs.SkipDir
+			}
+			return nil
+		},
+		"skip parent": func(path string, d fs.DirEntry, err error) error {
+			if path == "root/dirB/afile1.txt" {
+				return fs.SkipDir
+			}
+			return nil
+		},
+		"skip read error": func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return fs.SkipDir
+			}
+			return nil
+		},
+		"skip all": func(path string, d fs.DirEntry, err error) error {
+			if path == "root/dirB/sub" {
+				return fs.SkipAll
+			}
+			return nil
+		},
+		"error": func(path string, d fs.DirEntry, err error) error {
+			return err
+		},
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			var expected, visited []string
+			expectedErr := WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+				expected = append(expected, path)
+				return fn(path, d, err)
+			})
+			err := IDWalk(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+				visited = append(visited, path)
+				return fn(path, d, err)
+			})
+			if err != expectedErr {
+				t.Errorf("expected error %v, got %v", expectedErr, err)
+			}
+			if !slices.Equal(visited, expected) {
+				t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+			}
+		})
+	}
+}
+
+func TestIDWalkGenerated(t *testing.T) {
+	fsys := generateFS("root", 5, 4)
+
+	var expected, visited []string
+	err := WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		expected = append(expected, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = IDWalk(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		visited = append(visited, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
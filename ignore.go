@@ -0,0 +1,210 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// WithIgnoreFiles makes the Walker read the ignore files of the given
+// names, such as ".gitignore", ".ignore" or ".bfwalkignore", found in any
+// directory of the walk, and leave the entries they match out of the walk:
+// they are not passed to the walk callback and directories are not
+// descended into. The roots of the walk are always visited.
+//
+// Ignore files use the syntax of .gitignore files. Each line is a pattern
+// in the syntax of [path.Match], extended with "**" to match any number of
+// directories, and "[!...]" as a negated character class. A pattern ending
+// with a slash matches only directories, a pattern with a slash at the
+// start or in the middle is relative to the directory of the ignore file,
+// and any other pattern matches entries of that name at any depth below
+// it. A pattern starting with "!" re-includes entries excluded by an
+// earlier pattern, unless a directory above them is excluded. Blank lines
+// and lines starting with "#" are ignored, and "\" escapes a leading "#" or
+// "!" and trailing spaces.
+//
+// The rules of an ignore file apply to the subtree of its directory, and
+// take precedence over those of the directories above it. In a directory
+// with several ignore files, the rules of each file take precedence over
+// those of the files named before it. Ignore files above the roots of the
+// walk are not read, and ignore files that cannot be read are skipped;
+// ignore files themselves are visited unless they match a rule. If the
+// option is given more than once, the names are added to those given
+// before.
+func WithIgnoreFiles(names ...string) Option {
+	names = slices.Clone(names)
+	return func(w *Walker) {
+		w.ignoreNames = append(w.ignoreNames, names...)
+	}
+}
+
+// ignoreRules are the rules of an ignore file, stacked on the rules of the
+// ignore files of the directories above it.
+type ignoreRules struct {
+	parent   *ignoreRules
+	base     string // name of the directory of the ignore file
+	patterns []ignorePattern
+}
+
+// noIgnores ends every stack of ignore rules.
+var noIgnores = &ignoreRules{}
+
+// An ignorePattern is a single line of an ignore file.
+type ignorePattern struct {
+	elems    []string // slash-separated elements, where "**" matches any number
+	negate   bool     // whether matching entries are re-included
+	dirOnly  bool     // whether only directories match
+	anchored bool     // whether elems match the path below base, or only the name
+}
+
+// ignores returns the ignore rules for the entries of dir, given dirs, the
+// entries of dir if they were read in full, or nil.
+func (w *Walker) ignores(fsys fs.FS, dir namedEntry, dirs []fs.DirEntry) *ignoreRules {
+	parent := dir.ignore
+	if parent == nil {
+		parent = noIgnores
+		if dir.name != dir.root {
+			// Resumed from a checkpoint: rebuild the rules from the root
+			parent = w.ancestorIgnores(fsys, dir.root, path.Dir(dir.name))
+		}
+	}
+	return w.loadIgnores(fsys, dir.name, parent, dirs)
+}
+
+// ancestorIgnores returns the ignore rules for the entries of the directory
+// name, reading the ignore files of each directory from root down to name.
+func (w *Walker) ancestorIgnores(fsys fs.FS, root, name string) *ignoreRules {
+	rules := w.loadIgnores(fsys, root, noIgnores, nil)
+	if name == root {
+		return rules
+	}
+	dir := root
+	for _, elem := range strings.Split(relPath(root, name), "/") {
+		dir = path.Join(dir, elem)
+		rules = w.loadIgnores(fsys, dir, rules, nil)
+	}
+	return rules
+}
+
+// loadIgnores returns the ignore rules of the ignore files in the directory
+// name stacked on parent. When dirs, the entries of the directory, are
+// given, only the ignore files listed among them are read.
+func (w *Walker) loadIgnores(fsys fs.FS, name string, parent *ignoreRules, dirs []fs.DirEntry) *ignoreRules {
+	rules := parent
+	for _, file := range w.ignoreNames {
+		if dirs != nil && !slices.ContainsFunc(dirs, func(d fs.DirEntry) bool {
+			return d.Name() == file && !d.IsDir()
+		}) {
+			continue
+		}
+		w.throttle()
+		data, err := fs.ReadFile(fsys, path.Join(name, file))
+		if err != nil {
+			continue
+		}
+		if patterns := parseIgnore(w.norm(string(data))); len(patterns) > 0 {
+			rules = &ignoreRules{rules, name, patterns}
+		}
+	}
+	return rules
+}
+
+// parseIgnore returns the patterns of the ignore file data.
+func parseIgnore(data string) []ignorePattern {
+	var patterns []ignorePattern
+	for line := range strings.Lines(data) {
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		trimmed := strings.TrimRight(line, " ")
+		if strings.HasSuffix(trimmed, `\`) && len(trimmed) < len(line) {
+			trimmed += " " // Escaped trailing space
+		}
+		line = trimmed
+
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate, line = true, line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly, line = true, strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			p.anchored, line = true, strings.TrimLeft(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		p.elems = strings.Split(line, "/")
+		valid := true
+		for i, elem := range p.elems {
+			elem = strings.ReplaceAll(elem, "[!", "[^")
+			if _, err := path.Match(elem, ""); err != nil {
+				valid = false
+			}
+			p.elems[i] = elem
+		}
+		if valid {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// ignored reports whether the entry d named name is excluded by rules,
+// matching its path below each ignore file normalized with norm.
+func (r *ignoreRules) ignored(name string, d fs.DirEntry, norm func(string) string) bool {
+	for ; r != nil; r = r.parent {
+		if len(r.patterns) == 0 {
+			continue
+		}
+		rel := norm(relPath(r.base, name))
+		for i := len(r.patterns) - 1; i >= 0; i-- {
+			if p := r.patterns[i]; p.match(rel, d.IsDir()) {
+				return !p.negate
+			}
+		}
+	}
+	return false
+}
+
+// match reports whether the entry at the path rel below the directory of
+// the ignore file matches p.
+func (p ignorePattern) match(rel string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	if !p.anchored {
+		ok, _ := path.Match(p.elems[0], path.Base(rel))
+		return ok
+	}
+	return matchElems(p.elems, strings.Split(rel, "/"))
+}
+
+// matchElems reports whether the path elements parts match the pattern
+// elements pat, where "**" matches any number of elements.
+func matchElems(pat, parts []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			if len(pat) == 1 {
+				return len(parts) > 0 // Everything inside, but not the directory itself
+			}
+			for i := range len(parts) + 1 {
+				if matchElems(pat[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pat[0], parts[0]); !ok {
+			return false
+		}
+		pat, parts = pat[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
@@ -0,0 +1,163 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithIgnoreFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/.ignore":                   {Data: []byte("# comment\n\n*.log\nbuild/\n/top.txt\n!keep.log\ndocs/**/*.tmp\n")},
+		"root/top.txt":                   {},
+		"root/app.log":                   {},
+		"root/keep.log":                  {},
+		"root/main.go":                   {},
+		"root/build/out":                 {},
+		"root/docs/a.tmp":                {},
+		"root/docs/x/y/b.tmp":            {},
+		"root/docs/x/c.md":               {},
+		"root/sub/top.txt":               {},
+		"root/sub/build":                 {}, // A file, not a directory
+		"root/sub/.bfwalkignore":         {Data: []byte("!*.log\nsecret\\ \n")},
+		"root/sub/debug.log":             {},
+		"root/sub/secret ":               {},
+		"root/sub/deeper/.ignore":        {Data: []byte("[!m]*.go\r\n\\#hash\n")},
+		"root/sub/deeper/main.go":        {},
+		"root/sub/deeper/other.go":       {},
+		"root/sub/deeper/#hash":          {},
+		"root/sub/deeper/more/trace.log": {},
+	}
+	expected := []string{
+		"root",
+		"root/.ignore",
+		"root/docs",
+		"root/keep.log",
+		"root/main.go",
+		"root/sub",
+		"root/docs/x",
+		"root/sub/.bfwalkignore",
+		"root/sub/build",
+		"root/sub/debug.log",
+		"root/sub/deeper",
+		"root/sub/top.txt",
+		"root/docs/x/c.md",
+		"root/docs/x/y",
+		"root/sub/deeper/.ignore",
+		"root/sub/deeper/main.go",
+		"root/sub/deeper/more",
+		"root/sub/deeper/more/trace.log",
+	}
+	opts := []Option{WithIgnoreFiles(".ignore"), WithIgnoreFiles(".bfwalkignore")}
+
+	got, err := Collect(fsys, "root", opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected\n  %v\ngot\n  %v", expected, got)
+	}
+
+	var ids []string
+	err = NewWalker(opts...).IDWalk(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		ids = append(ids, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(ids, expected) {
+		t.Errorf("IDWalk: expected\n  %v\ngot\n  %v", expected, ids)
+	}
+
+	for name, extra := range map[string][]Option{
+		"concurrent": {WithConcurrency(4)},
+		"dfs":        {WithStrategy(DFS)},
+		"paged":      {WithPageSize(2)},
+		"hidden":     {WithSkipHidden()},
+	} {
+		var walked fs.FS = fsys
+		if name == "paged" {
+			walked = &pagerFS{fsys, new([]string)}
+		}
+		got, err := Collect(walked, "root", append(opts, extra...)...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		visible := expected
+		if name == "hidden" {
+			visible = slices.DeleteFunc(slices.Clone(expected), func(p string) bool {
+				return slices.Contains([]string{"root/.ignore", "root/sub/.bfwalkignore", "root/sub/deeper/.ignore"}, p)
+			})
+		}
+		slices.Sort(got)
+		visible = slices.Sorted(slices.Values(visible))
+		if !slices.Equal(got, visible) {
+			t.Errorf("%s: expected:\n  %v\ngot\n: %v", name, visible, got)
+		}
+	}
+}
+
+func TestWithIgnoreFilesResume(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/.ignore":        {Data: []byte("*.log\n")},
+		"root/a/x.log":        {},
+		"root/a/b/.ignore":    {Data: []byte("!keep.log\n")},
+		"root/a/b/c/keep.log": {},
+		"root/a/b/c/drop.log": {},
+		"root/a/b/c/file.txt": {},
+	}
+	w := NewWalker(WithIgnoreFiles(".ignore"))
+	var state []byte
+	err := w.WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		if path == "root/a/b/c" {
+			state, err = w.Checkpoint()
+			if err != nil {
+				return err
+			}
+			return fs.SkipAll
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	err = NewWalker(WithIgnoreFiles(".ignore")).Resume(fsys, state, func(path string, d fs.DirEntry, err error) error {
+		got = append(got, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"root/a/b/c/file.txt", "root/a/b/c/keep.log"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestMatchElems(t *testing.T) {
+	tests := []struct {
+		pat, path string
+		match     bool
+	}{
+		{"a/b", "a/b", true},
+		{"a/b", "a/b/c", false},
+		{"**/b", "b", true},
+		{"**/b", "x/y/b", true},
+		{"a/**", "a", false},
+		{"a/**", "a/x/y", true},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**/b", "a/x/y/c", false},
+		{"a/*.go", "a/x.go", true},
+		{"a/*.go", "a/x/y.go", false},
+	}
+	for _, tt := range tests {
+		if got := matchElems(strings.Split(tt.pat, "/"), strings.Split(tt.path, "/")); got != tt.match {
+			t.Errorf("%s against %s: expected %v, got %v", tt.path, tt.pat, tt.match, got)
+		}
+	}
+}
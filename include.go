@@ -0,0 +1,160 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// WithInclude makes the Walker visit only the entries whose path relative
+// to the root of the walk matches one of patterns, and the directories
+// leading to them. Patterns are slash-separated, in the syntax of
+// [path.Match] for each element, and "**" matches any number of
+// directories: "*.go" matches the Go files directly in the root, and
+// "**/*.go" those at any depth. Directories are only descended into if a
+// pattern may match below them, and the roots of the walk are always
+// visited. Malformed patterns match nothing. If the option is given more
+// than once, the patterns are added to those given before.
+//
+// If the file system implements [fs.GlobFS] and no pattern contains "**",
+// the entries of each directory that may match are listed with Glob rather
+// than by reading the whole directory, which is much cheaper on file
+// systems that serve glob queries themselves. The entries found with Glob
+// are then stat'ed, so symbolic links among them are followed.
+func WithInclude(patterns ...string) Option {
+	var include [][]string
+	for _, p := range patterns {
+		if elems, ok := parseInclude(p); ok {
+			include = append(include, elems)
+		}
+	}
+	return func(w *Walker) {
+		w.include = append(w.include, include...)
+		w.includeSet = true
+	}
+}
+
+// parseInclude returns the elements of the include pattern p, and whether
+// it is well formed.
+func parseInclude(p string) ([]string, bool) {
+	elems := strings.Split(strings.Trim(p, "/"), "/")
+	for _, elem := range elems {
+		if _, err := path.Match(elem, ""); err != nil {
+			return nil, false
+		}
+	}
+	return elems, true
+}
+
+// included reports whether the entry d, at the path rel below the root of
+// the walk, is kept by the include patterns.
+func (w *Walker) included(rel string, d fs.DirEntry) bool {
+	rel = w.norm(rel)
+	if w.foldCase {
+		rel = strings.ToLower(rel) // Patterns are lowercased by NewWalker
+	}
+	parts := strings.Split(rel, "/")
+	for _, pat := range w.include {
+		if matchElems(pat, parts) || d.IsDir() && matchPrefix(pat, parts) {
+			return true
+		}
+	}
+	return false
+}
+
+// includeDescends reports whether the include patterns may match entries
+// below the directory at the path rel below the root of the walk.
+func (w *Walker) includeDescends(rel string) bool {
+	if rel == "." {
+		return len(w.include) > 0
+	}
+	rel = w.norm(rel)
+	if w.foldCase {
+		rel = strings.ToLower(rel)
+	}
+	parts := strings.Split(rel, "/")
+	return slices.ContainsFunc(w.include, func(pat []string) bool {
+		return matchPrefix(pat, parts)
+	})
+}
+
+// matchPrefix reports whether the path elements parts match the beginning
+// of the pattern elements pat, so that paths below parts may match pat.
+func matchPrefix(pat, parts []string) bool {
+	for ; len(parts) > 0; pat, parts = pat[1:], parts[1:] {
+		if len(pat) == 0 {
+			return false
+		}
+		if pat[0] == "**" {
+			return true
+		}
+		if ok, _ := path.Match(pat[0], parts[0]); !ok {
+			return false
+		}
+	}
+	return len(pat) > 0
+}
+
+// globber returns fsys as an [fs.GlobFS] if the entries kept by the
+// include patterns can be listed with Glob.
+func (w *Walker) globber(fsys fs.FS) (fs.GlobFS, bool) {
+	g, ok := fsys.(fs.GlobFS)
+	if !ok || !w.includeSet || w.foldCase || w.normalize != nil {
+		return nil, false
+	}
+	for _, pat := range w.include {
+		if slices.Contains(pat, "**") {
+			return nil, false
+		}
+	}
+	return g, true
+}
+
+// globDir lists the entries of the directory dir that may be kept by the
+// include patterns with g, sorted by filename.
+func (w *Walker) globDir(g fs.GlobFS, dir namedEntry) ([]fs.DirEntry, error) {
+	var parts []string
+	if rel := relPath(dir.root, dir.name); rel != "." {
+		parts = strings.Split(rel, "/")
+	}
+	var names []string
+	for _, pat := range w.include {
+		if len(pat) <= len(parts) || !matchElems(pat[:len(parts)], parts) {
+			continue
+		}
+		matches, err := g.Glob(path.Join(escapeMeta(dir.name), pat[len(parts)]))
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, matches...)
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	dirs := make([]fs.DirEntry, 0, len(names))
+	for _, name := range names {
+		info, err := stat(g, name)
+		if err != nil {
+			return dirs, err
+		}
+		dirs = append(dirs, fs.FileInfoToDirEntry(info))
+	}
+	return dirs, nil
+}
+
+// escapeMeta returns name with the special characters of [path.Match]
+// escaped, so that it matches only itself.
+func escapeMeta(name string) string {
+	if !strings.ContainsAny(name, `*?[\`) {
+		return name
+	}
+	var b strings.Builder
+	for _, r := range name {
+		if strings.ContainsRune(`*?[\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithInclude(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/main.go":          {},
+		"root/README.md":        {},
+		"root/cmd/app/main.go":  {},
+		"root/cmd/app/app.txt":  {},
+		"root/cmd/tool/main.go": {},
+		"root/docs/a/b/c.md":    {},
+		"root/docs/a/d.go":      {},
+		"root/[x]/main.go":      {},
+	}
+
+	cases := []struct {
+		name     string
+		patterns []string
+		expected []string
+	}{
+		{"Root", []string{"*.go"}, []string{"root", "root/main.go"}},
+		{"Nested", []string{"cmd/*/main.go", "[[]x]/*"}, []string{
+			"root", "root/[x]", "root/cmd", "root/[x]/main.go", "root/cmd/app", "root/cmd/tool",
+			"root/cmd/app/main.go", "root/cmd/tool/main.go",
+		}},
+		{"Dirs", []string{"cmd/*"}, []string{"root", "root/cmd", "root/cmd/app", "root/cmd/tool"}},
+		{"AnyDepth", []string{"docs/**/*.md", "*.md"}, []string{
+			"root", "root/README.md", "root/docs", "root/docs/a", "root/docs/a/b", "root/docs/a/b/c.md",
+		}},
+		{"Malformed", []string{"["}, []string{"root"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for _, fsys := range []fs.FS{memFS, readdirFS{memFS}} {
+				got, err := Collect(fsys, "root", WithInclude(c.patterns...))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !slices.Equal(got, c.expected) {
+					t.Errorf("%T: expected:\n  %v\ngot\n: %v", fsys, c.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWithIncludeGlob(t *testing.T) {
+	fsys := &globFS{MapFS: fstest.MapFS{
+		"root/a/main.go": {},
+		"root/a/x.txt":   {},
+		"root/b/x.txt":   {},
+	}}
+	got, err := Collect(fsys, "root", WithInclude("*/main.go"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"root", "root/a", "root/b", "root/a/main.go"}; !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if expected := []string{"root/*", "root/a/main.go", "root/b/main.go"}; !slices.Equal(fsys.globs, expected) {
+		t.Errorf("expected globs %v, got %v", expected, fsys.globs)
+	}
+	if fsys.reads != 0 {
+		t.Errorf("expected no directory reads, got %d", fsys.reads)
+	}
+
+	// Patterns with "**" cannot be globbed
+	fsys.globs = nil
+	if _, err := Collect(fsys, "root", WithInclude("**/main.go")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fsys.globs != nil || fsys.reads != 3 {
+		t.Errorf("expected 3 directory reads and no globs, got %d and %v", fsys.reads, fsys.globs)
+	}
+}
+
+// globFS is a file system recording the patterns it is globbed with and
+// the number of directories read.
+type globFS struct {
+	fstest.MapFS
+	globs []string
+	reads int
+}
+
+func (f *globFS) Glob(pattern string) ([]string, error) {
+	f.globs = append(f.globs, pattern)
+	return f.MapFS.Glob(pattern)
+}
+
+func (f *globFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	f.reads++
+	return f.MapFS.ReadDir(name)
+}
@@ -0,0 +1,51 @@
+package bfwalk
+
+import "fmt"
+
+// An InterruptedError reports how far a walk got before its deadline
+// passed or its context was done. It is returned instead of the error of
+// the deadline or context by Walkers created with [WithInterruptReport],
+// and matches that error with [errors.Is].
+type InterruptedError struct {
+	Err      error  // ErrDeadlineExceeded, or the error of the context
+	Queued   int    // directories still waiting to be read
+	MaxDepth int    // deepest level of the tree visited
+	Pending  []byte // checkpoint of the work remaining, if requested
+}
+
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("%v (%d directories queued, depth %d reached)", e.Err, e.Queued, e.MaxDepth)
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// WithInterruptReport makes walks stopped by the deadline set with
+// [WithTimeout] or [WithDeadline], or by the context of
+// [Walker.WalkDirContext], return an [*InterruptedError] telling how many
+// directories were still queued and the deepest level visited.
+//
+// If checkpoint is true, the error also holds a checkpoint of the work
+// remaining, as returned by [Walker.Checkpoint], so that the walk can be
+// continued later with [Walker.Resume]. Walks that cannot be checkpointed,
+// such as depth-first walks and [Walker.IDWalk], report no checkpoint.
+// IDWalk keeps no queue of directories, so it reports none queued.
+func WithInterruptReport(checkpoint bool) Option {
+	return func(w *Walker) {
+		w.reportStops, w.stopState = true, checkpoint
+	}
+}
+
+// interruption returns the error to stop the walk in progress with once
+// interrupted by err, the error of its deadline or context.
+func (w *Walker) interruption(err error) error {
+	if !w.reportStops {
+		return err
+	}
+	e := &InterruptedError{Err: err, Queued: w.queued(), MaxDepth: w.maxDepth}
+	if w.stopState && w.dfsDepth < 0 && !w.deepening {
+		e.Pending, _ = w.Checkpoint()
+	}
+	return e
+}
@@ -0,0 +1,89 @@
+package bfwalk
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestWithInterruptReport(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/a/file": {},
+		"root/b/file": {},
+		"root/c":      {},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := NewWalker(WithInterruptReport(true))
+	err := w.WalkDirContext(ctx, memFS, "root", func(ctx context.Context, path string, d fs.DirEntry, err error) error {
+		if path == "root/a" {
+			cancel()
+		}
+		return err
+	})
+	var ie *InterruptedError
+	if !errors.As(err, &ie) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected an interrupted error for %v, got %v", context.Canceled, err)
+	}
+	if ie.Queued != 2 || ie.MaxDepth != 1 {
+		t.Errorf("expected 2 directories queued at depth 1, got %d at depth %d", ie.Queued, ie.MaxDepth)
+	}
+	expected := "context canceled (2 directories queued, depth 1 reached)"
+	if err.Error() != expected {
+		t.Errorf("expected error %q, got %q", expected, err.Error())
+	}
+
+	var resumed []string
+	err = NewWalker().Resume(memFS, ie.Pending, func(path string, d fs.DirEntry, err error) error {
+		resumed = append(resumed, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"root/a/file", "root/b/file"}; !slices.Equal(resumed, expected) {
+		t.Errorf("expected to resume with %v, got %v", expected, resumed)
+	}
+
+	// Without a checkpoint, and for depth-first walks, Pending is empty
+	for _, opts := range [][]Option{
+		{WithInterruptReport(false)},
+		{WithInterruptReport(true), WithStrategy(DFS)},
+	} {
+		w := NewWalker(append(opts, WithDeadline(time.Now().Add(-time.Second)))...)
+		err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+			return err
+		})
+		if !errors.As(err, &ie) || !errors.Is(err, ErrDeadlineExceeded) {
+			t.Fatalf("expected an interrupted error for %v, got %v", ErrDeadlineExceeded, err)
+		}
+		if ie.Pending != nil {
+			t.Errorf("expected no checkpoint, got %s", ie.Pending)
+		}
+	}
+
+	// Iterative deepening walks report no queue and no checkpoint
+	w = NewWalker(WithInterruptReport(true), WithDeadline(time.Now().Add(-time.Second)))
+	err = w.IDWalk(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		return err
+	})
+	if !errors.As(err, &ie) || !errors.Is(err, ErrDeadlineExceeded) {
+		t.Fatalf("expected an interrupted error for %v, got %v", ErrDeadlineExceeded, err)
+	}
+	if ie.Queued != 0 || ie.Pending != nil {
+		t.Errorf("expected no queue and no checkpoint, got %d queued and %s", ie.Queued, ie.Pending)
+	}
+
+	// Without the option, the error of the deadline is returned as is
+	err = NewWalker(WithDeadline(time.Now().Add(-time.Second))).WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		return err
+	})
+	if err != ErrDeadlineExceeded {
+		t.Errorf("expected %v, got %v", ErrDeadlineExceeded, err)
+	}
+}
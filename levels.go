@@ -0,0 +1,131 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"iter"
+	"slices"
+)
+
+// Levels walks the file tree rooted at root breadth-first and yields the
+// entries of each level of the tree as a single batch, starting with root
+// itself at depth 0.
+//
+// A directory that cannot be read is reported a second time with Err set,
+// in the batch of the level its contents would have been in. Stopping the
+// iteration stops the walk.
+func Levels(fsys fs.FS, root string) iter.Seq2[int, []Entry] {
+	return NewWalker().Levels(fsys, root)
+}
+
+// Levels walks the file tree rooted at root like the package-level [Levels]
+// function, with the traversal adjusted by the options the Walker was
+// created with.
+func (w *Walker) Levels(fsys fs.FS, root string) iter.Seq2[int, []Entry] {
+	return func(yield func(int, []Entry) bool) {
+		var batch []Entry
+		level, stopped := 0, false
+		err := w.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+			e := w.entry(path, d, err)
+			if at := w.level; at > level {
+				if len(batch) > 0 && !yield(level, batch) {
+					stopped = true
+					return fs.SkipAll
+				}
+				batch, level = nil, w.level
+			}
+			batch = append(batch, e)
+			return nil
+		})
+		if stopped {
+			return
+		}
+		if err != nil {
+			batch = append(batch, Entry{Depth: level, Err: err})
+		}
+		if len(batch) > 0 {
+			yield(level, batch)
+		}
+	}
+}
+
+// ReadLevel walks the file tree rooted at root breadth-first and returns
+// the entries exactly depth levels below root, in the order they are
+// visited, without reading the directories at that depth or below. A depth
+// of 0 returns root itself, and a negative depth returns no entries. The
+// first error encountered stops the walk and is returned, together with the
+// entries found before it.
+func ReadLevel(fsys fs.FS, root string, depth int) ([]Entry, error) {
+	return NewWalker().ReadLevel(fsys, root, depth)
+}
+
+// ReadLevel returns the entries depth levels below root like the
+// package-level [ReadLevel] function, with the traversal adjusted by the
+// options the Walker was created with. Like [Walker.Run], it walks in a run
+// of its own, whose statistics are not reported by [Walker.Stats].
+func (w *Walker) ReadLevel(fsys fs.FS, root string, depth int) ([]Entry, error) {
+	if depth < 0 {
+		return nil, nil
+	}
+	r := w.newRun()
+	r.descend = append(slices.Clip(w.descend), func(string, fs.DirEntry) bool {
+		return r.current.depth < depth
+	})
+
+	var entries []Entry
+	err := r.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if r.current.depth == depth {
+			entries = append(entries, r.entry(path, d, nil))
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// WithLevelHooks makes the Walker call onStart before the first entry of
+// each breadth level is passed to the walk callback, and onEnd once the
+// level is complete with the number of entries visited in it, including
+// errors. Either function may be nil.
+//
+// A directory that cannot be read is reported in the level its contents
+// would have been in, as with [Levels]. Every call to onStart is matched by
+// a call to onEnd, even if the walk is stopped early.
+func WithLevelHooks(onStart func(depth int), onEnd func(depth, visited int)) Option {
+	return func(w *Walker) {
+		if onStart != nil {
+			w.onLevel = append(w.onLevel, onStart)
+		}
+		if onEnd != nil {
+			w.onLevelEnd = append(w.onLevelEnd, onEnd)
+		}
+	}
+}
+
+// enterLevel records a visit to an entry in level, ending the current level
+// and starting the next one if level is deeper.
+func (w *Walker) enterLevel(level int) {
+	if level != w.level {
+		w.endLevel()
+		w.level, w.levelN = level, 0
+		for _, fn := range w.onLevel {
+			fn(level)
+		}
+	}
+	w.levelN++
+}
+
+// endLevel ends the level being visited, if any.
+func (w *Walker) endLevel() {
+	if w.level < 0 {
+		return
+	}
+	for _, fn := range w.onLevelEnd {
+		fn(w.level, w.levelN)
+	}
+	if len(w.subscribers) > 0 {
+		w.emit(Event{Kind: LevelDone, Depth: w.level, Entries: w.levelN})
+	}
+	w.level = -1
+}
@@ -0,0 +1,250 @@
+package bfwalk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLevels(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":          {Data: []byte("")},
+		"root/dirA/file1.txt":     {Data: []byte("")},
+		"root/dirB/sub/file1.txt": {Data: []byte("")},
+	}
+
+	var visited []string
+	for depth, entries := range Levels(memFS, "root") {
+		var paths []string
+		for _, e := range entries {
+			if e.Err != nil {
+				t.Fatalf("unexpected error for %s: %v", e.Path, e.Err)
+			}
+			if e.Depth != depth {
+				t.Errorf("expected depth %d for %s, got %d", depth, e.Path, e.Depth)
+			}
+			paths = append(paths, e.Path)
+		}
+		visited = append(visited, fmt.Sprint(depth, paths))
+	}
+
+	expected := []string{
+		"0 [root]",
+		"1 [root/dirA root/dirB root/file1.txt]",
+		"2 [root/dirA/file1.txt root/dirB/sub]",
+		"3 [root/dirB/sub/file1.txt]",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+func TestLevelsStop(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/dirA/config.json":   {Data: []byte("")},
+		"root/dirB/sub/file1.txt": {Data: []byte("")},
+	}
+
+	var levels []int
+	for depth, entries := range Levels(memFS, "root") {
+		levels = append(levels, depth)
+		if slices.ContainsFunc(entries, func(e Entry) bool { return e.DirEntry.Name() == "config.json" }) {
+			break
+		}
+	}
+
+	expected := []int{0, 1, 2}
+	if !slices.Equal(levels, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, levels)
+	}
+}
+
+func TestLevelsReadDirError(t *testing.T) {
+	errRead := errors.New("read failed")
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/dirA/file1.txt": {Data: []byte("")},
+			"root/dirB/file1.txt": {Data: []byte("")},
+		},
+		errs: map[string]error{"root/dirA": errRead},
+	}
+
+	var visited []string
+	for depth, entries := range Levels(fsys, "root") {
+		for _, e := range entries {
+			if e.Err != nil {
+				visited = append(visited, fmt.Sprint(depth, " error:", e.Path))
+				continue
+			}
+			visited = append(visited, fmt.Sprint(depth, " ", e.Path))
+		}
+	}
+
+	expected := []string{
+		"0 root",
+		"1 root/dirA",
+		"1 root/dirB",
+		"2 error:root/dirA",
+		"2 root/dirB/file1.txt",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+func TestReadLevel(t *testing.T) {
+	errRead := errors.New("read failed")
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/file1.txt":          {Data: []byte("")},
+			"root/dirA/file1.txt":     {Data: []byte("")},
+			"root/dirB/sub/file1.txt": {Data: []byte("")},
+		},
+		// Directories at the requested depth are not read
+		errs: map[string]error{"root/dirB/sub": errRead},
+	}
+
+	for depth, expected := range [][]string{
+		{"root"},
+		{"root/dirA", "root/dirB", "root/file1.txt"},
+		{"root/dirA/file1.txt", "root/dirB/sub"},
+	} {
+		entries, err := ReadLevel(fsys, "root", depth)
+		if err != nil {
+			t.Fatalf("depth %d: unexpected error: %v", depth, err)
+		}
+		var paths []string
+		for _, e := range entries {
+			if e.Depth != depth {
+				t.Errorf("expected depth %d for %s, got %d", depth, e.Path, e.Depth)
+			}
+			paths = append(paths, e.Path)
+		}
+		if !slices.Equal(paths, expected) {
+			t.Errorf("depth %d: expected %v, got %v", depth, expected, paths)
+		}
+	}
+
+	entries, err := ReadLevel(fsys, "root", 3)
+	if !errors.Is(err, errRead) {
+		t.Errorf("expected %v, got %v", errRead, err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries, got %v", entries)
+	}
+	if entries, err := ReadLevel(fsys, "root", -1); entries != nil || err != nil {
+		t.Errorf("expected no entries and no error, got %v, %v", entries, err)
+	}
+}
+
+func TestReadLevelFilter(t *testing.T) {
+	errRead := errors.New("read failed")
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/file1.txt":     {Data: []byte("")},
+			"root/a/sub/deep.go": {Data: []byte("")},
+			"root/a/z.txt":       {Data: []byte("")},
+		},
+		// Directories at the requested depth are not read, even when
+		// filtered out
+		errs: map[string]error{"root/a/sub": errRead},
+	}
+
+	entries, err := NewWalker(WithFilter(Type('f'))).ReadLevel(fsys, "root", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, expected := entryPaths(entries), []string{"root/a/z.txt"}; !slices.Equal(got, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, got)
+	}
+}
+
+func TestReadLevelShared(t *testing.T) {
+	fsys := generateFS("data", 4, 3)
+	// A descend func with spare capacity, which ReadLevel must not write to
+	w := NewWalker(WithShouldDescend(func(string, fs.DirEntry) bool { return true }))
+	w.descend = slices.Grow(w.descend, 1)
+
+	expected, err := w.ReadLevel(fsys, "data", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			entries, err := w.ReadLevel(fsys, "data", 2)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !slices.Equal(entryPaths(entries), entryPaths(expected)) {
+				t.Errorf("expected:\n  %v\ngot\n: %v", entryPaths(expected), entryPaths(entries))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			visited := 0
+			w.Run(context.Background(), fsys, "data", func(string, fs.DirEntry, error) error {
+				visited++
+				return nil
+			})
+			if visited != 1+4*3*11 {
+				t.Errorf("expected %d entries, got %d", 1+4*3*11, visited)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(w.descend) != 1 {
+		t.Errorf("ReadLevel changed the descend funcs of the Walker: %d", len(w.descend))
+	}
+}
+
+func TestWithLevelHooks(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":          {Data: []byte("")},
+		"root/dirA/file1.txt":     {Data: []byte("")},
+		"root/dirB/sub/file1.txt": {Data: []byte("")},
+	}
+
+	var events []string
+	w := NewWalker(WithLevelHooks(
+		func(depth int) { events = append(events, fmt.Sprint("start ", depth)) },
+		func(depth, visited int) { events = append(events, fmt.Sprint("end ", depth, " ", visited)) },
+	))
+	err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		events = append(events, path)
+		if path == "root/dirA/file1.txt" {
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"start 0",
+		"root",
+		"end 0 1",
+		"start 1",
+		"root/dirA",
+		"root/dirB",
+		"root/file1.txt",
+		"end 1 3",
+		"start 2",
+		"root/dirA/file1.txt",
+		"end 2 1",
+	}
+	if !slices.Equal(events, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, events)
+	}
+}
@@ -0,0 +1,26 @@
+package bfwalk
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithLogger makes the Walker log directory reads, retries, skips and
+// errors to logger at debug level, with the path and depth of the entry
+// and, for reads, the duration and number of entries read as attributes.
+// Nothing is logged unless logger has debug logging enabled when a walk
+// starts.
+func WithLogger(logger *slog.Logger) Option {
+	return func(w *Walker) {
+		w.logger = logger
+	}
+}
+
+// debug logs msg about the entry e at debug level with attrs.
+func (w *Walker) debug(msg string, e namedEntry, attrs ...slog.Attr) {
+	attrs = append([]slog.Attr{
+		slog.String("path", w.report(e.root, e.name)),
+		slog.Int("depth", e.depth),
+	}, attrs...)
+	w.logger.LogAttrs(context.Background(), slog.LevelDebug, msg, attrs...)
+}
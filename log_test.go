@@ -0,0 +1,67 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithLogger(t *testing.T) {
+	errRead := errors.New("read failed")
+	fsys := &flakyFS{
+		MapFS: fstest.MapFS{
+			"root/dirA/file1.txt": {Data: []byte("")},
+			"root/dirB/file1.txt": {Data: []byte("")},
+		},
+		name:     "root/dirA",
+		failures: 1,
+		err:      errRead,
+	}
+
+	var sb strings.Builder
+	logger := slog.New(slog.NewTextHandler(&sb, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "duration" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	w := NewWalker(WithLogger(logger), WithRetry(2, nil, nil))
+	err := w.WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		if path == "root/dirB" {
+			return fs.SkipDir
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		`level=DEBUG msg="read directory" path=root depth=0 entries=2`,
+		`level=DEBUG msg="skip directory" path=root/dirB depth=1`,
+		`level=DEBUG msg="retry directory read" path=root/dirA depth=1 attempt=1 error="read failed"`,
+		`level=DEBUG msg="read directory" path=root/dirA depth=1 entries=1`,
+		``,
+	}, "\n")
+	if sb.String() != expected {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, sb.String())
+	}
+
+	// nothing is logged above debug level
+	sb.Reset()
+	logger = slog.New(slog.NewTextHandler(&sb, nil))
+	if err := NewWalker(WithLogger(logger)).WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		return err
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sb.Len() != 0 {
+		t.Errorf("unexpected output %q", sb.String())
+	}
+}
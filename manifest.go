@@ -0,0 +1,138 @@
+package bfwalk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"runtime"
+	"strings"
+)
+
+// WriteManifest walks the file tree rooted at root and writes a line to w
+// for each regular file in the tree, in breadth-first order, holding the
+// digest of its contents computed with hash functions returned by h and its
+// path. The lines are in the format of sha256sum and similar tools, so a
+// manifest written with [crypto/sha256.New] can be checked with
+// sha256sum --check, and read back with [ReadManifest]. The first error
+// encountered stops the walk and is returned.
+func WriteManifest(w io.Writer, fsys fs.FS, root string, h func() hash.Hash) error {
+	bw := bufio.NewWriter(w)
+	for fh, err := range HashWalk(fsys, root, h, runtime.GOMAXPROCS(0)) {
+		if err != nil {
+			return err
+		}
+		if _, err := bw.WriteString(manifestLine(fh)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// manifestLine returns the manifest line of fh. As with sha256sum, paths
+// holding backslashes or line breaks are escaped, and their line starts with
+// a backslash.
+func manifestLine(fh FileHash) string {
+	name, prefix := fh.Path, ""
+	if strings.ContainsAny(name, "\\\n\r") {
+		name = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`).Replace(name)
+		prefix = `\`
+	}
+	return prefix + hex.EncodeToString(fh.Digest) + "  " + name + "\n"
+}
+
+// ReadManifest reads a manifest in the format written by [WriteManifest]
+// from r and returns its entries, in order. Lines marking files as binary,
+// with "*" before the path instead of a space, are accepted.
+func ReadManifest(r io.Reader) ([]FileHash, error) {
+	var hashes []FileHash
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		fh, err := parseManifestLine(s.Text())
+		if err != nil {
+			return hashes, fmt.Errorf("bfwalk: manifest line %d: %w", n, err)
+		}
+		hashes = append(hashes, fh)
+	}
+	return hashes, s.Err()
+}
+
+// parseManifestLine parses a line of a manifest.
+func parseManifestLine(line string) (FileHash, error) {
+	escaped := strings.HasPrefix(line, `\`)
+	if escaped {
+		line = line[1:]
+	}
+	sum, name, ok := strings.Cut(line, " ")
+	if !ok || len(name) < 2 || name[0] != ' ' && name[0] != '*' {
+		return FileHash{}, errors.New("malformed line")
+	}
+	name = name[1:]
+	digest, err := hex.DecodeString(sum)
+	if err != nil || len(digest) == 0 {
+		return FileHash{}, errors.New("malformed digest")
+	}
+	if escaped {
+		if name, err = unescapeManifestPath(name); err != nil {
+			return FileHash{}, err
+		}
+	}
+	return FileHash{name, digest}, nil
+}
+
+// unescapeManifestPath reverses the escaping of a path in a manifest line.
+func unescapeManifestPath(name string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] != '\\' {
+			b.WriteByte(name[i])
+			continue
+		}
+		i++
+		if i == len(name) {
+			return "", errors.New("malformed escape")
+		}
+		switch name[i] {
+		case '\\':
+			b.WriteByte('\\')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			return "", errors.New("malformed escape")
+		}
+	}
+	return b.String(), nil
+}
+
+// VerifyManifest reads a manifest from r as [ReadManifest] does and checks
+// it against the files in fsys, hashing them with hash functions returned by
+// h. It returns the paths of the files whose digest differs or that do not
+// exist, in the order they are listed. Other errors reading files stop the
+// check and are returned.
+func VerifyManifest(fsys fs.FS, r io.Reader, h func() hash.Hash) ([]string, error) {
+	hashes, err := ReadManifest(r)
+	if err != nil {
+		return nil, err
+	}
+	var failed []string
+	for _, fh := range hashes {
+		digest, err := hashFile(fsys, fh.Path, h())
+		if errors.Is(err, fs.ErrNotExist) {
+			failed = append(failed, fh.Path)
+			continue
+		}
+		if err != nil {
+			return failed, err
+		}
+		if !bytes.Equal(digest, fh.Digest) {
+			failed = append(failed, fh.Path)
+		}
+	}
+	return failed, nil
+}
@@ -0,0 +1,67 @@
+package bfwalk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWriteManifest(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/hello.txt":     {Data: []byte("hello")},
+		"root/dirA/empty":    {Data: []byte("")},
+		"root/dirA/a\\b.txt": {Data: []byte("hello")},
+	}
+	var buf bytes.Buffer
+	if err := WriteManifest(&buf, memFS, "root", sha256.New); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824  root/hello.txt\n" +
+		"\\2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824  root/dirA/a\\\\b.txt\n" +
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  root/dirA/empty\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	hashes, err := ReadManifest(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var paths []string
+	for _, h := range hashes {
+		paths = append(paths, h.Path)
+	}
+	if expected := []string{"root/hello.txt", "root/dirA/a\\b.txt", "root/dirA/empty"}; !slices.Equal(paths, expected) {
+		t.Errorf("expected paths %q, got %q", expected, paths)
+	}
+
+	memFS["root/dirA/empty"] = &fstest.MapFile{Data: []byte("changed")}
+	delete(memFS, "root/hello.txt")
+	failed, err := VerifyManifest(memFS, strings.NewReader(buf.String()), sha256.New)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"root/hello.txt", "root/dirA/empty"}; !slices.Equal(failed, expected) {
+		t.Errorf("expected failed %v, got %v", expected, failed)
+	}
+}
+
+func TestReadManifest(t *testing.T) {
+	hashes, err := ReadManifest(strings.NewReader("00ff *bin/file\n\\0a  new\\nline\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hashes) != 2 || hashes[0].Path != "bin/file" || !bytes.Equal(hashes[0].Digest, []byte{0, 0xff}) ||
+		hashes[1].Path != "new\nline" {
+		t.Errorf("unexpected entries %+v", hashes)
+	}
+
+	for _, bad := range []string{"00ff file", "zz  file", "00ff  ", "\\00ff  bad\\x", "nospace"} {
+		if _, err := ReadManifest(strings.NewReader("00  ok\n" + bad + "\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("expected error on line 2 for %q, got %v", bad, err)
+		}
+	}
+}
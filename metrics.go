@@ -0,0 +1,112 @@
+package bfwalk
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+// A MetricsSink receives metrics about the walks of a Walker created with
+// [WithMetrics], for export to a monitoring system. Its methods are called
+// synchronously during the walk, so they should be cheap, and must be safe
+// for concurrent use if the sink is shared by several Walkers.
+type MetricsSink interface {
+	// EntryVisited is called for each call of the walk callback, with the
+	// error passed to the callback.
+	EntryVisited(err error)
+
+	// ReadDir is called after each directory read, including any retries,
+	// with the time it took, the number of entries read and its error.
+	ReadDir(d time.Duration, entries int, err error)
+
+	// QueueHighWater is called when the number of directories waiting to
+	// be read reaches a new maximum for the walk.
+	QueueHighWater(n int)
+}
+
+// WithMetrics makes the Walker report metrics about its walks to sink.
+func WithMetrics(sink MetricsSink) Option {
+	return func(w *Walker) {
+		w.metrics = sink
+	}
+}
+
+// ExpvarMetrics is a [MetricsSink] that publishes metrics as an [expvar]
+// map, which holds the counters visited, errors, readdirs and
+// readdir_errors, the gauge queue_high_water with the largest high water
+// mark of any walk, and readdir_latency, a map of the number of directory
+// reads by latency bucket.
+type ExpvarMetrics struct {
+	vars                 expvar.Map
+	visited, errors      expvar.Int
+	readDirs, readErrors expvar.Int
+	latency              expvar.Map
+
+	mu        sync.Mutex // guards queueHigh
+	queueHigh expvar.Int
+}
+
+// latencyBuckets are the upper bounds of the readdir_latency buckets of
+// [ExpvarMetrics]. Slower reads are counted under "inf".
+var latencyBuckets = []struct {
+	name  string
+	bound time.Duration
+}{
+	{"100us", 100 * time.Microsecond},
+	{"1ms", time.Millisecond},
+	{"10ms", 10 * time.Millisecond},
+	{"100ms", 100 * time.Millisecond},
+	{"1s", time.Second},
+}
+
+// NewExpvarMetrics returns an [ExpvarMetrics] published under name. Like
+// [expvar.Publish], it panics if name is already in use.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := new(ExpvarMetrics)
+	m.vars.Set("visited", &m.visited)
+	m.vars.Set("errors", &m.errors)
+	m.vars.Set("readdirs", &m.readDirs)
+	m.vars.Set("readdir_errors", &m.readErrors)
+	m.vars.Set("queue_high_water", &m.queueHigh)
+	m.vars.Set("readdir_latency", &m.latency)
+	expvar.Publish(name, m)
+	return m
+}
+
+// String returns the metrics as a JSON object, as required by [expvar.Var].
+func (m *ExpvarMetrics) String() string {
+	return m.vars.String()
+}
+
+// EntryVisited implements [MetricsSink].
+func (m *ExpvarMetrics) EntryVisited(err error) {
+	m.visited.Add(1)
+	if err != nil {
+		m.errors.Add(1)
+	}
+}
+
+// ReadDir implements [MetricsSink].
+func (m *ExpvarMetrics) ReadDir(d time.Duration, entries int, err error) {
+	m.readDirs.Add(1)
+	if err != nil {
+		m.readErrors.Add(1)
+	}
+	bucket := "inf"
+	for _, b := range latencyBuckets {
+		if d <= b.bound {
+			bucket = b.name
+			break
+		}
+	}
+	m.latency.Add(bucket, 1)
+}
+
+// QueueHighWater implements [MetricsSink].
+func (m *ExpvarMetrics) QueueHighWater(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if int64(n) > m.queueHigh.Value() {
+		m.queueHigh.Set(int64(n))
+	}
+}
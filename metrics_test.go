@@ -0,0 +1,123 @@
+package bfwalk
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"io/fs"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// recordingSink is a MetricsSink that records the metrics it receives.
+type recordingSink struct {
+	mu                   sync.Mutex
+	visited, errors      int
+	readDirs, readErrors int
+	entries              int
+	highWater            []int
+}
+
+func (s *recordingSink) EntryVisited(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.visited++
+	if err != nil {
+		s.errors++
+	}
+}
+
+func (s *recordingSink) ReadDir(d time.Duration, entries int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readDirs++
+	s.entries += entries
+	if err != nil {
+		s.readErrors++
+	}
+}
+
+func (s *recordingSink) QueueHighWater(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.highWater = append(s.highWater, n)
+}
+
+func TestWithMetrics(t *testing.T) {
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/file1.txt":      {Data: []byte("")},
+			"root/dirA/file1.txt": {Data: []byte("")},
+			"root/dirB/file1.txt": {Data: []byte("")},
+			"root/dirC/file1.txt": {Data: []byte("")},
+		},
+		errs: map[string]error{"root/dirC": errors.New("read failed")},
+	}
+
+	sink := new(recordingSink)
+	err := NewWalker(WithMetrics(sink)).WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sink.visited != 8 || sink.errors != 1 {
+		t.Errorf("expected 8 visited and 1 error, got %d and %d", sink.visited, sink.errors)
+	}
+	if sink.readDirs != 4 || sink.readErrors != 1 || sink.entries != 6 {
+		t.Errorf("expected 4 reads, 1 error and 6 entries, got %d, %d and %d", sink.readDirs, sink.readErrors, sink.entries)
+	}
+	if expected := []int{1, 3}; !slices.Equal(sink.highWater, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, sink.highWater)
+	}
+}
+
+// expvarRuns numbers the names published by TestExpvarMetrics, since
+// expvar names cannot be reused when the test runs more than once.
+var expvarRuns atomic.Int64
+
+func TestExpvarMetrics(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/dirA/file1.txt": {Data: []byte("")},
+	}
+
+	name := fmt.Sprintf("bfwalk_test_%d", expvarRuns.Add(1))
+	m := NewExpvarMetrics(name)
+	if expvar.Get(name) != m {
+		t.Fatalf("metrics not published")
+	}
+	for range 2 {
+		err := NewWalker(WithMetrics(m)).WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+			return err
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var got struct {
+		Visited        int            `json:"visited"`
+		ReadDirs       int            `json:"readdirs"`
+		QueueHighWater int            `json:"queue_high_water"`
+		ReadDirLatency map[string]int `json:"readdir_latency"`
+	}
+	if err := json.Unmarshal([]byte(m.String()), &got); err != nil {
+		t.Fatalf("invalid metrics %s: %v", m, err)
+	}
+	if got.Visited != 6 || got.ReadDirs != 4 || got.QueueHighWater != 1 {
+		t.Errorf("unexpected metrics %s", m)
+	}
+	total := 0
+	for _, n := range got.ReadDirLatency {
+		total += n
+	}
+	if total != 4 {
+		t.Errorf("expected 4 reads in latency buckets, got %s", m)
+	}
+}
@@ -0,0 +1,105 @@
+package bfwalk
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"runtime/debug"
+	"time"
+)
+
+// A Middleware wraps an [fs.WalkDirFunc] to add behavior around each call,
+// such as timing, logging or filtering, so that the same behavior can be
+// layered onto the callbacks passed to any walk function.
+type Middleware func(fs.WalkDirFunc) fs.WalkDirFunc
+
+// Chain returns a Middleware applying each of mws in turn, the first being
+// the outermost: Chain(a, b)(fn) calls a, then b, then fn.
+func Chain(mws ...Middleware) Middleware {
+	return func(fn fs.WalkDirFunc) fs.WalkDirFunc {
+		for i := len(mws) - 1; i >= 0; i-- {
+			fn = mws[i](fn)
+		}
+		return fn
+	}
+}
+
+// Timing returns a Middleware calling record after each call of the
+// callback with the path of the entry and the time the call took.
+func Timing(record func(path string, elapsed time.Duration)) Middleware {
+	return func(fn fs.WalkDirFunc) fs.WalkDirFunc {
+		return func(path string, d fs.DirEntry, err error) error {
+			start := time.Now()
+			defer func() { record(path, time.Since(start)) }()
+			return fn(path, d, err)
+		}
+	}
+}
+
+// Logging returns a Middleware logging each call of the callback to logger
+// at debug level, with the path of the entry, the duration of the call and
+// the error passed to or returned by the callback, if any, as attributes.
+func Logging(logger *slog.Logger) Middleware {
+	return func(fn fs.WalkDirFunc) fs.WalkDirFunc {
+		return func(path string, d fs.DirEntry, err error) error {
+			start := time.Now()
+			result := fn(path, d, err)
+			attrs := []slog.Attr{slog.String("path", path), slog.Duration("duration", time.Since(start))}
+			if err != nil {
+				attrs = append(attrs, slog.Any("error", err))
+			}
+			if result != nil {
+				attrs = append(attrs, slog.Any("result", result))
+			}
+			logger.LogAttrs(context.Background(), slog.LevelDebug, "visit", attrs...)
+			return result
+		}
+	}
+}
+
+// Filter returns a Middleware calling the callback only for the entries for
+// which match returns true, and for errors. Directories that do not match
+// are still walked.
+func Filter(match func(path string, d fs.DirEntry) bool) Middleware {
+	return func(fn fs.WalkDirFunc) fs.WalkDirFunc {
+		return func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !match(path, d) {
+				return nil
+			}
+			return fn(path, d, err)
+		}
+	}
+}
+
+// A PanicError records a panic recovered by [Recover].
+type PanicError struct {
+	Path  string // path of the entry whose callback panicked
+	Value any    // value passed to panic
+	Stack []byte // stack trace of the goroutine that panicked
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("bfwalk: panic visiting %s: %v", e.Path, e.Value)
+}
+
+// Unwrap returns the value passed to panic, if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Recover returns a Middleware recovering from panics in the callback,
+// which stop the walk with a [*PanicError] instead of crashing the program.
+func Recover() Middleware {
+	return func(fn fs.WalkDirFunc) fs.WalkDirFunc {
+		return func(path string, d fs.DirEntry, err error) (result error) {
+			defer func() {
+				if v := recover(); v != nil {
+					result = &PanicError{path, v, debug.Stack()}
+				}
+			}()
+			return fn(path, d, err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package bfwalk
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	tag := func(name string) Middleware {
+		return func(fn fs.WalkDirFunc) fs.WalkDirFunc {
+			return func(path string, d fs.DirEntry, err error) error {
+				calls = append(calls, name+" "+path)
+				return fn(path, d, err)
+			}
+		}
+	}
+	fn := Chain(tag("a"), tag("b"))(func(path string, d fs.DirEntry, err error) error {
+		calls = append(calls, "fn "+path)
+		return nil
+	})
+	if err := WalkDir(fstest.MapFS{"file": {}}, ".", fn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"a .", "b .", "fn .", "a file", "b file", "fn file"}
+	if !slices.Equal(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+
+	if err := WalkDir(fstest.MapFS{"file": {}}, ".", Chain()(func(path string, d fs.DirEntry, err error) error {
+		return err
+	})); err != nil {
+		t.Errorf("empty chain: %v", err)
+	}
+}
+
+func TestTiming(t *testing.T) {
+	var timed []string
+	fn := Timing(func(path string, elapsed time.Duration) {
+		if elapsed < time.Millisecond {
+			t.Errorf("%s: expected at least 1ms, got %v", path, elapsed)
+		}
+		timed = append(timed, path)
+	})(func(path string, d fs.DirEntry, err error) error {
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	if err := WalkDir(fstest.MapFS{"a/b": {}}, ".", fn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{".", "a", "a/b"}
+	if !slices.Equal(timed, expected) {
+		t.Errorf("expected %v, got %v", expected, timed)
+	}
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "duration" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	errStop := errors.New("stop")
+	fn := Logging(logger)(func(path string, d fs.DirEntry, err error) error {
+		if path == "b" {
+			return errStop
+		}
+		return nil
+	})
+	err := WalkDir(fstest.MapFS{"a": {}, "b": {}, "c": {}}, ".", fn)
+	if err != errStop {
+		t.Fatalf("expected %v, got %v", errStop, err)
+	}
+	expected := `level=DEBUG msg=visit path=.
+level=DEBUG msg=visit path=a
+level=DEBUG msg=visit path=b result=stop
+`
+	if got := buf.String(); got != expected {
+		t.Errorf("expected:\n%sgot:\n%s", expected, got)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	errDir := errors.New("unreadable")
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"a.go":      {},
+			"b.txt":     {},
+			"dir/c.go":  {},
+			"bad/d.go":  {},
+			"dir/e.txt": {},
+		},
+		errs: map[string]error{"bad": errDir},
+	}
+	var got []string
+	fn := Filter(func(path string, d fs.DirEntry) bool {
+		return strings.HasSuffix(path, ".go")
+	})(func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			got = append(got, "error "+path)
+			return nil
+		}
+		got = append(got, path)
+		return nil
+	})
+	if err := WalkDir(fsys, ".", fn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"a.go", "error bad", "dir/c.go"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	errBoom := errors.New("boom")
+	fn := Recover()(func(path string, d fs.DirEntry, err error) error {
+		if path == "b" {
+			panic(errBoom)
+		}
+		return nil
+	})
+	err := WalkDir(fstest.MapFS{"a": {}, "b": {}}, ".", fn)
+	var perr *PanicError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected a *PanicError, got %v", err)
+	}
+	if perr.Path != "b" || !errors.Is(err, errBoom) || len(perr.Stack) == 0 {
+		t.Errorf("unexpected panic error %+v", perr)
+	}
+	if expected := "bfwalk: panic visiting b: boom"; err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
@@ -0,0 +1,60 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"mime"
+	"strings"
+)
+
+// WithMIMEFilter makes the Walker pass to the walk callback only the files
+// whose content type, detected as with [WithDetectContentType], matches one
+// of patterns. Directories are still visited and descended into, and the
+// roots of the walk are always visited.
+//
+// Each pattern is a media type such as "text/html", or a type followed by
+// "/*", such as "image/*", which matches every subtype. Parameters such as
+// charset are ignored, and matching is case-insensitive. Files whose content
+// type could not be detected, and non-regular files such as symbolic links,
+// match no pattern. If the option is given more than once, files matching
+// any of the patterns are visited.
+func WithMIMEFilter(patterns ...string) Option {
+	patterns = normalizeMIME(patterns)
+	return func(w *Walker) {
+		w.sniff = true
+		w.mimeTypes = append(w.mimeTypes, patterns...)
+	}
+}
+
+// normalizeMIME returns patterns lowercased, without parameters.
+func normalizeMIME(patterns []string) []string {
+	normal := make([]string, len(patterns))
+	for i, p := range patterns {
+		p, _, _ = strings.Cut(p, ";")
+		normal[i] = strings.ToLower(strings.TrimSpace(p))
+	}
+	return normal
+}
+
+// mimeRejected reports whether d is a file left out of the walk by
+// [WithMIMEFilter].
+func (w *Walker) mimeRejected(d fs.DirEntry) bool {
+	return !d.IsDir() && !matchMIME(contentType(d), w.mimeTypes)
+}
+
+// matchMIME reports whether the content type ctype matches any of patterns.
+func matchMIME(ctype string, patterns []string) bool {
+	if ctype == "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(ctype)
+	if err != nil {
+		return false
+	}
+	major, _, _ := strings.Cut(mediaType, "/")
+	for _, p := range patterns {
+		if p == mediaType || p == "*/*" || p == major+"/*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithMIMEFilter(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/index.html":      {Data: []byte("<!DOCTYPE html><html></html>")},
+		"root/logo.png":        {Data: []byte("\x89PNG\x0D\x0A\x1A\x0A rest of image")},
+		"root/notes.txt":       {Data: []byte("plain notes\n")},
+		"root/link":            {Mode: fs.ModeSymlink},
+		"root/img/photo.jpg":   {Data: []byte("\xFF\xD8\xFF photo")},
+		"root/img/anim.gif":    {Data: []byte("GIF89a animation")},
+		"root/img/deep/x.html": {Data: []byte("<html><body></body></html>")},
+	}
+
+	tests := []struct {
+		patterns []string
+		expected []string
+	}{
+		{
+			[]string{"image/*", "text/html"},
+			[]string{"root", "root/img", "root/index.html", "root/logo.png", "root/img/anim.gif", "root/img/deep", "root/img/photo.jpg", "root/img/deep/x.html"},
+		},
+		{
+			[]string{"Text/Plain; charset=utf-8"},
+			[]string{"root", "root/img", "root/notes.txt", "root/img/deep"},
+		},
+		{
+			[]string{"*/*"},
+			[]string{"root", "root/img", "root/index.html", "root/logo.png", "root/notes.txt", "root/img/anim.gif", "root/img/deep", "root/img/photo.jpg", "root/img/deep/x.html"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Collect(fsys, "root", WithMIMEFilter(tt.patterns...))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("%q: expected %v, got %v", tt.patterns, tt.expected, got)
+		}
+	}
+
+	// Options given more than once match any pattern
+	got, err := Collect(fsys, "root/img", WithMIMEFilter("image/jpeg"), WithMIMEFilter("image/gif"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"root/img", "root/img/anim.gif", "root/img/deep", "root/img/photo.jpg"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
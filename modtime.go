@@ -0,0 +1,63 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"time"
+)
+
+// WithModifiedAfter makes the Walker pass to the walk callback only the
+// files modified after t. Directories are still visited, unless pruned with
+// [WithPruneStaleDirs], and the roots of the walk are always visited.
+//
+// The file info of every entry is read as each directory is read, as with
+// [WithStat]. Files whose info cannot be read are visited, and the error is
+// returned by Info.
+func WithModifiedAfter(t time.Time) Option {
+	filter := withFileFilter(func(d fs.DirEntry, info fs.FileInfo) bool {
+		return info.ModTime().After(t)
+	})
+	return func(w *Walker) {
+		filter(w)
+		if t.After(w.modAfter) {
+			w.modAfter = t
+		}
+	}
+}
+
+// WithModifiedBefore makes the Walker pass to the walk callback only the
+// files modified before t, like [WithModifiedAfter].
+func WithModifiedBefore(t time.Time) Option {
+	return withFileFilter(func(d fs.DirEntry, info fs.FileInfo) bool {
+		return info.ModTime().Before(t)
+	})
+}
+
+// WithPruneStaleDirs makes the Walker leave directories last modified
+// before the cutoff given to [WithModifiedAfter] out of the walk: they are
+// not passed to the walk callback and are not descended into. The roots of
+// the walk are always visited.
+//
+// The modification time of a directory usually changes only when entries
+// are added to, removed from or renamed within it, not when the contents of
+// its files or subdirectories change, so only use this on file systems and
+// trees where files are replaced rather than modified in place, such as
+// build outputs written by rename. Without [WithModifiedAfter], no
+// directory is pruned.
+func WithPruneStaleDirs() Option {
+	return func(w *Walker) {
+		w.pruneStale = true
+	}
+}
+
+// staleDir reports whether the directory d is pruned by
+// [WithPruneStaleDirs].
+func (w *Walker) staleDir(d fs.DirEntry) bool {
+	if !w.pruneStale || w.modAfter.IsZero() {
+		return false
+	}
+	info, err := d.Info()
+	if err != nil {
+		return false
+	}
+	return !info.ModTime().After(w.modAfter)
+}
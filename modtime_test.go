@@ -0,0 +1,46 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestWithModified(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return base.AddDate(0, 0, n) }
+	fsys := fstest.MapFS{
+		"root":               {Mode: fs.ModeDir, ModTime: day(5)},
+		"root/old":           {ModTime: day(1)},
+		"root/new":           {ModTime: day(9)},
+		"root/stale":         {Mode: fs.ModeDir, ModTime: day(2)},
+		"root/stale/changed": {ModTime: day(8)},
+		"root/fresh":         {Mode: fs.ModeDir, ModTime: day(7)},
+		"root/fresh/mid":     {ModTime: day(5)},
+		"root/fresh/new":     {ModTime: day(7)},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{"after", []Option{WithModifiedAfter(day(4))}, []string{"root", "root/fresh", "root/new", "root/stale", "root/fresh/mid", "root/fresh/new", "root/stale/changed"}},
+		{"before", []Option{WithModifiedBefore(day(6))}, []string{"root", "root/fresh", "root/old", "root/stale", "root/fresh/mid"}},
+		{"window", []Option{WithModifiedAfter(day(4)), WithModifiedBefore(day(8))}, []string{"root", "root/fresh", "root/stale", "root/fresh/mid", "root/fresh/new"}},
+		{"prune", []Option{WithModifiedAfter(day(4)), WithPruneStaleDirs()}, []string{"root", "root/fresh", "root/new", "root/fresh/mid", "root/fresh/new"}},
+		{"latest cutoff", []Option{WithModifiedAfter(day(1)), WithModifiedAfter(day(6)), WithPruneStaleDirs()}, []string{"root", "root/fresh", "root/new", "root/fresh/new"}},
+		{"prune without cutoff", []Option{WithPruneStaleDirs()}, []string{"root", "root/fresh", "root/new", "root/old", "root/stale", "root/fresh/mid", "root/fresh/new", "root/stale/changed"}},
+	}
+	for _, tt := range tests {
+		got, err := Collect(fsys, "root", tt.opts...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"slices"
+)
+
+// FindNearest returns the path of the file of one of the given names
+// closest to start in fsys, such as the layout.html applying to a page or
+// the .editorconfig applying to a source file.
+//
+// The directory start, or the directory holding start if it is a file, is
+// searched first, then its parent, and so on up to the root of fsys, so the
+// file found is the deepest one whose directory contains start. Within a
+// directory, names are tried in the order given. Directories of a matching
+// name are not files and are passed over.
+//
+// If no file is found, FindNearest returns an error wrapping
+// [fs.ErrNotExist]. An error stating start or any of the candidate files,
+// other than the candidate not existing, stops the search and is returned.
+//
+// To search the directories below start instead, use [FindNearestBelow].
+func FindNearest(fsys fs.FS, start string, names ...string) (string, error) {
+	if !fs.ValidPath(start) {
+		return "", &fs.PathError{Op: "findnearest", Path: start, Err: fs.ErrInvalid}
+	}
+	info, err := stat(fsys, start)
+	if err != nil {
+		return "", err
+	}
+	dir := start
+	if !info.IsDir() {
+		dir = path.Dir(start)
+	}
+	for {
+		for _, name := range names {
+			candidate := path.Join(dir, name)
+			info, err := stat(fsys, candidate)
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				continue
+			case err != nil:
+				return "", err
+			case !info.IsDir():
+				return candidate, nil
+			}
+		}
+		if dir == "." {
+			return "", &fs.PathError{Op: "findnearest", Path: start, Err: fs.ErrNotExist}
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+// FindNearestBelow returns the path of the file of one of the given names
+// closest to start in fsys among the files below it, such as the nearest
+// go.mod of a repository checkout. It is the outward counterpart of
+// [FindNearest].
+//
+// The directory start, or the directory holding start if it is a file, is
+// walked breadth-first, so the file found is the shallowest one below it.
+// Among the directories of one level, the first in lexical order holding a
+// match wins, and within a directory, names are tried in the order given.
+// Directories of a matching name are not files and are passed over.
+//
+// If no file is found, FindNearestBelow returns an error wrapping
+// [fs.ErrNotExist]. An error stating start or reading any directory below
+// it stops the search and is returned.
+func FindNearestBelow(fsys fs.FS, start string, names ...string) (string, error) {
+	if !fs.ValidPath(start) {
+		return "", &fs.PathError{Op: "findnearest", Path: start, Err: fs.ErrInvalid}
+	}
+	info, err := stat(fsys, start)
+	if err != nil {
+		return "", err
+	}
+	dir := start
+	if !info.IsDir() {
+		dir = path.Dir(start)
+	}
+
+	// The entries of a directory are visited together, so the best match
+	// of the first directory holding one is known once the walk leaves it.
+	found, rank := "", len(names)
+	err = WalkDir(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+		if found != "" && path.Dir(name) != path.Dir(found) {
+			return fs.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if name == dir || d.IsDir() {
+			return nil
+		}
+		if i := slices.Index(names, d.Name()); i >= 0 && i < rank {
+			found, rank = name, i
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", &fs.PathError{Op: "findnearest", Path: start, Err: fs.ErrNotExist}
+	}
+	return found, nil
+}
@@ -0,0 +1,134 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFindNearest(t *testing.T) {
+	memFS := fstest.MapFS{
+		".editorconfig":                  {},
+		"site/layout.html":               {},
+		"site/blog/page.html":            {},
+		"site/blog/2024/layout.tmpl":     {},
+		"site/blog/2024/post.html":       {},
+		"site/docs/layout.html/x.html":   {}, // A directory named like the file
+		"site/docs/guide/index.html":     {},
+		"site/docs/guide/layout.html":    {},
+		"site/docs/guide/layout.tmpl":    {},
+		"site/docs/reference/index.html": {},
+	}
+
+	cases := []struct {
+		start    string
+		names    []string
+		expected string
+	}{
+		{"site/blog/page.html", []string{"layout.html"}, "site/layout.html"},
+		{"site/blog/2024/post.html", []string{"layout.html"}, "site/layout.html"},
+		{"site/blog/2024/post.html", []string{"layout.html", "layout.tmpl"}, "site/blog/2024/layout.tmpl"},
+		{"site/blog/2024", []string{"layout.html", "layout.tmpl"}, "site/blog/2024/layout.tmpl"},
+		{"site/docs/guide/index.html", []string{"layout.tmpl", "layout.html"}, "site/docs/guide/layout.tmpl"},
+		{"site/docs/reference/index.html", []string{"layout.html"}, "site/layout.html"},
+		{"site/docs/reference/index.html", []string{".editorconfig"}, ".editorconfig"},
+		{".", []string{".editorconfig"}, ".editorconfig"},
+	}
+	for _, c := range cases {
+		got, err := FindNearest(memFS, c.start, c.names...)
+		if err != nil {
+			t.Errorf("FindNearest(%q, %q): unexpected error: %v", c.start, c.names, err)
+			continue
+		}
+		if got != c.expected {
+			t.Errorf("FindNearest(%q, %q): expected %q, got %q", c.start, c.names, c.expected, got)
+		}
+	}
+}
+
+func TestFindNearestErrors(t *testing.T) {
+	memFS := fstest.MapFS{"site/page.html": {}}
+
+	if _, err := FindNearest(memFS, "site/page.html", "layout.html"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+	if _, err := FindNearest(memFS, "site/missing.html", "page.html"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not exist error for missing start, got %v", err)
+	}
+	if _, err := FindNearest(memFS, "/site", "page.html"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("expected invalid path error, got %v", err)
+	}
+
+	errs := statErrFS{errFS{
+		MapFS: fstest.MapFS{"a/b/c.txt": {}, "layout.html": {}},
+		errs:  map[string]error{"a/layout.html": fs.ErrPermission},
+	}}
+	if _, err := FindNearest(errs, "a/b/c.txt", "layout.html"); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("expected permission error, got %v", err)
+	}
+}
+
+func TestFindNearestBelow(t *testing.T) {
+	memFS := fstest.MapFS{
+		"repo/README.md":                {},
+		"repo/a/go.mod":                 {},
+		"repo/a/go.work":                {},
+		"repo/b/go.mod/x.go":            {}, // A directory named like the file
+		"repo/b/c/go.mod":               {},
+		"repo/z/go.work":                {},
+		"repo/vendor/deep/x/y/go.mod":   {},
+		"repo/tools/go.mod":             {},
+		"repo/tools/internal/go.work":   {},
+		"other/sub/sub/sub/layout.html": {},
+	}
+
+	cases := []struct {
+		start    string
+		names    []string
+		expected string
+	}{
+		{"repo", []string{"go.mod"}, "repo/a/go.mod"},
+		{"repo", []string{"go.mod", "go.work"}, "repo/a/go.mod"},
+		{"repo", []string{"go.work", "go.mod"}, "repo/a/go.work"},
+		{"repo/README.md", []string{"go.work"}, "repo/a/go.work"},
+		{"repo/b", []string{"go.mod"}, "repo/b/c/go.mod"},
+		{"repo/tools", []string{"go.work", "go.mod"}, "repo/tools/go.mod"},
+		{"repo/vendor", []string{"go.mod"}, "repo/vendor/deep/x/y/go.mod"},
+		{".", []string{"layout.html"}, "other/sub/sub/sub/layout.html"},
+	}
+	for _, c := range cases {
+		got, err := FindNearestBelow(memFS, c.start, c.names...)
+		if err != nil {
+			t.Errorf("FindNearestBelow(%q, %q): unexpected error: %v", c.start, c.names, err)
+			continue
+		}
+		if got != c.expected {
+			t.Errorf("FindNearestBelow(%q, %q): expected %q, got %q", c.start, c.names, c.expected, got)
+		}
+	}
+
+	if _, err := FindNearestBelow(memFS, "repo/b", "go.work"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+	if _, err := FindNearestBelow(memFS, "/repo", "go.mod"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("expected invalid path error, got %v", err)
+	}
+	errs := errFS{memFS, map[string]error{"repo/b/c": fs.ErrPermission}}
+	if _, err := FindNearestBelow(errs, "repo/b", "go.mod"); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("expected permission error, got %v", err)
+	}
+}
+
+// statErrFS is an errFS whose Stat method also fails with the errors of
+// errs.
+type statErrFS struct {
+	errFS
+}
+
+func (f statErrFS) Stat(name string) (fs.FileInfo, error) {
+	if err, ok := f.errs[name]; ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	return f.MapFS.Stat(name)
+}
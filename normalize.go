@@ -0,0 +1,54 @@
+package bfwalk
+
+import (
+	"io/fs"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// WithNormalizePaths makes the Walker normalize the paths it passes to the
+// walk callback to the Unicode normalization form f, and match names in
+// that form against its filters, so that names stored decomposed, as
+// macOS file systems return them, compare equal to names written composed
+// in configuration, or the other way around with [norm.NFD].
+//
+// Names are normalized for the patterns of [WithInclude],
+// [WithPreferNames] and [WithIgnoreFiles], the names of [WithPruneNames],
+// the extensions of [WithExtensions] and the expressions of [WithFilter]
+// and [WithPrune], which give the patterns they are matched with in the
+// form f. Entries passed to the walk callback keep the names the file
+// system lists, and are still read by those names, so normalized paths may
+// not open the files they name on file systems that are not
+// normalization-insensitive, such as those of Linux, though [Copy] copies
+// files by the names listed. With [WithInclude],
+// directories are read in full rather than with Glob.
+func WithNormalizePaths(f norm.Form) Option {
+	return func(w *Walker) {
+		w.normalize = f.String
+	}
+}
+
+// norm returns s normalized with WithNormalizePaths.
+func (w *Walker) norm(s string) string {
+	if w.normalize == nil {
+		return s
+	}
+	return w.normalize(s)
+}
+
+// normEntry returns d with its name normalized with WithNormalizePaths,
+// for matching against filters.
+func (w *Walker) normEntry(d fs.DirEntry) fs.DirEntry {
+	if w.normalize == nil {
+		return d
+	}
+	return normalizedEntry{d, w.normalize(d.Name())}
+}
+
+// normalizedEntry is an [fs.DirEntry] reporting a normalized name.
+type normalizedEntry struct {
+	fs.DirEntry
+	name string
+}
+
+func (e normalizedEntry) Name() string { return e.name }
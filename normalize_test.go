@@ -0,0 +1,60 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestWithNormalizePaths(t *testing.T) {
+	// Decomposed names, as listed by macOS
+	cafe, resume := "cafe\u0301", "re\u0301sume\u0301"
+	memFS := fstest.MapFS{
+		"root/" + cafe + "/" + resume + ".txt": {Data: []byte("cv")},
+		"root/" + cafe + "/menu.txt":           {},
+		"root/.ignore":                         {Data: []byte("menu.txt\n")},
+		"root/" + resume + "/old.txt":          {},
+	}
+
+	var visited, names []string
+	w := NewWalker(
+		WithNormalizePaths(norm.NFC),
+		WithInclude("café/*", ".ignore"),
+		WithIgnoreFiles(".ignore"),
+		WithFilter(Not(Name("*.ignore"))),
+		WithStat(),
+	)
+	err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if _, err := d.Info(); err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		names = append(names, d.Name())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"root", "root/café", "root/café/résumé.txt"}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %q\ngot\n: %q", expected, visited)
+	}
+	if expected := []string{"root", cafe, resume + ".txt"}; !slices.Equal(names, expected) {
+		t.Errorf("expected entries named as listed %q, got %q", expected, names)
+	}
+
+	got, err := Collect(memFS, "root", WithNormalizePaths(norm.NFC), WithPruneNames("café"), WithPreferNames("résumé"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []string{"root", "root/résumé", "root/.ignore", "root/résumé/old.txt"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected:\n  %q\ngot\n: %q", expected, got)
+	}
+}
@@ -0,0 +1,110 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+)
+
+// An Order compares two entries of a directory to decide which is visited
+// first, returning a negative number if a comes before b, a positive number
+// if b comes before a, and zero if their order is left as listed.
+type Order func(a, b fs.DirEntry) int
+
+// WithOrder makes the Walker visit the entries of each directory in the
+// order given by o instead of lexical order. Entries that o considers equal
+// keep their lexical order, or their listing order with [WithUnsorted].
+//
+// Subdirectories are queued in the same order, so with a breadth-first walk
+// the order also applies to the contents of sibling directories.
+func WithOrder(o Order) Option {
+	return func(w *Walker) {
+		w.order = o
+	}
+}
+
+// WithPreferNames makes the Walker visit the entries of each directory
+// whose names match one of patterns, in the syntax of [path.Match], before
+// their siblings: entries matching the first pattern come first, then those
+// matching the second, and so on, followed by the entries matching none.
+// Entries matching the same pattern keep the order given by [WithOrder], or
+// their lexical order. Malformed patterns match nothing.
+//
+// As with [WithOrder], subdirectories are queued in the same order. If the
+// option is given more than once, the patterns are added to those given
+// before.
+func WithPreferNames(patterns ...string) Option {
+	patterns = slices.Clone(patterns)
+	return func(w *Walker) {
+		w.prefer = append(w.prefer, patterns...)
+	}
+}
+
+// preferNames returns an order putting entries matching the patterns of
+// WithPreferNames first, and then ordering them by o, if not nil.
+func (w *Walker) preferNames(o Order) Order {
+	patterns := w.prefer
+	rank := func(d fs.DirEntry) int {
+		for i, pattern := range patterns {
+			if w.matchName(pattern, d.Name()) {
+				return i
+			}
+		}
+		return len(patterns)
+	}
+	return func(a, b fs.DirEntry) int {
+		if n := rank(a) - rank(b); n != 0 || o == nil {
+			return n
+		}
+		return o(a, b)
+	}
+}
+
+// OrderByName orders entries lexically by name, the default order.
+func OrderByName(a, b fs.DirEntry) int {
+	return strings.Compare(a.Name(), b.Name())
+}
+
+// OrderByModTime orders entries by modification time, oldest first. Entries
+// whose file info cannot be read come first.
+func OrderByModTime(a, b fs.DirEntry) int {
+	return modTime(a).Compare(modTime(b))
+}
+
+// OrderBySize orders entries by size, smallest first. Entries whose file
+// info cannot be read come first.
+func OrderBySize(a, b fs.DirEntry) int {
+	sa, sb := size(a), size(b)
+	switch {
+	case sa < sb:
+		return -1
+	case sa > sb:
+		return 1
+	}
+	return 0
+}
+
+// Descending returns the reverse of o, such as newest first for
+// [OrderByModTime] or largest first for [OrderBySize].
+func Descending(o Order) Order {
+	return func(a, b fs.DirEntry) int {
+		return o(b, a)
+	}
+}
+
+func modTime(d fs.DirEntry) time.Time {
+	info, err := d.Info()
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func size(d fs.DirEntry) int64 {
+	info, err := d.Info()
+	if err != nil {
+		return -1
+	}
+	return info.Size()
+}
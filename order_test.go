@@ -0,0 +1,110 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestWithOrder(t *testing.T) {
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	memFS := fstest.MapFS{
+		"root/a.txt":        {Data: []byte("333"), ModTime: mtime},
+		"root/b.txt":        {Data: []byte("1"), ModTime: mtime.Add(2 * time.Hour)},
+		"root/c.txt":        {Data: []byte("22"), ModTime: mtime.Add(time.Hour)},
+		"root/dirA":         {Mode: fs.ModeDir, ModTime: mtime.Add(-time.Hour)},
+		"root/dirB":         {Mode: fs.ModeDir, ModTime: mtime.Add(3 * time.Hour)},
+		"root/dirA/old.txt": {ModTime: mtime},
+		"root/dirA/new.txt": {ModTime: mtime.Add(time.Hour)},
+		"root/dirB/one.txt": {ModTime: mtime},
+	}
+
+	cases := []struct {
+		name     string
+		order    Order
+		expected []string
+	}{
+		{"Name", OrderByName, []string{
+			"root", "root/a.txt", "root/b.txt", "root/c.txt", "root/dirA", "root/dirB",
+			"root/dirA/new.txt", "root/dirA/old.txt", "root/dirB/one.txt",
+		}},
+		{"ModTime", OrderByModTime, []string{
+			"root", "root/dirA", "root/a.txt", "root/c.txt", "root/b.txt", "root/dirB",
+			"root/dirA/old.txt", "root/dirA/new.txt", "root/dirB/one.txt",
+		}},
+		{"ModTimeDescending", Descending(OrderByModTime), []string{
+			"root", "root/dirB", "root/b.txt", "root/c.txt", "root/a.txt", "root/dirA",
+			"root/dirB/one.txt", "root/dirA/new.txt", "root/dirA/old.txt",
+		}},
+		{"Size", OrderBySize, []string{
+			"root", "root/dirA", "root/dirB", "root/b.txt", "root/c.txt", "root/a.txt",
+			"root/dirA/new.txt", "root/dirA/old.txt", "root/dirB/one.txt",
+		}},
+		{"SizeDescending", Descending(OrderBySize), []string{
+			"root", "root/a.txt", "root/c.txt", "root/b.txt", "root/dirA", "root/dirB",
+			"root/dirA/new.txt", "root/dirA/old.txt", "root/dirB/one.txt",
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var visited []string
+			err := NewWalker(WithOrder(c.order)).WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				visited = append(visited, path)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(visited, c.expected) {
+				t.Errorf("expected:\n  %v\ngot\n: %v", c.expected, visited)
+			}
+		})
+	}
+}
+
+func TestWithPreferNames(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/about.html":        {Data: []byte("1")},
+		"root/index.html":        {Data: []byte("1")},
+		"root/layout.html":       {Data: []byte("1")},
+		"root/blog/post.html":    {Data: []byte("1")},
+		"root/blog/layout.html":  {Data: []byte("1")},
+		"root/blog/layout.css":   {Data: []byte("333")},
+		"root/blog/index.html":   {Data: []byte("1")},
+		"root/docs/a/index.html": {Data: []byte("1")},
+	}
+
+	cases := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{"Lexical", []Option{WithPreferNames("layout.*", "index.*")}, []string{
+			"root", "root/layout.html", "root/index.html", "root/about.html", "root/blog", "root/docs",
+			"root/blog/layout.css", "root/blog/layout.html", "root/blog/index.html", "root/blog/post.html",
+			"root/docs/a", "root/docs/a/index.html",
+		}},
+		{"Order", []Option{WithPreferNames("layout.*"), WithOrder(Descending(OrderBySize)), WithPreferNames("[")}, []string{
+			"root", "root/layout.html", "root/about.html", "root/index.html", "root/blog", "root/docs",
+			"root/blog/layout.css", "root/blog/layout.html", "root/blog/index.html", "root/blog/post.html",
+			"root/docs/a", "root/docs/a/index.html",
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			visited, err := Collect(memFS, "root", c.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(visited, c.expected) {
+				t.Errorf("expected:\n  %v\ngot\n: %v", c.expected, visited)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package osfs
+
+// WithSameDevice makes the FS read the directories on a device other than
+// that of its root as empty, like find -xdev, so that walks list mount
+// points but do not descend into the file systems mounted on them.
+//
+// On systems where the device of a directory cannot be found, and for
+// directories whose device cannot be read, every directory is taken to be
+// on the device of the root.
+func WithSameDevice() Option {
+	return func(f *FS) {
+		f.sameDevice = true
+	}
+}
+
+// otherDevice reports whether the directory at the operating system path
+// full is on a device other than that of the root of the FS.
+func (f *FS) otherDevice(full string) bool {
+	f.rootDev.Do(func() {
+		f.dev, f.devErr = deviceOf(f.dir)
+	})
+	if f.devErr != nil {
+		return false
+	}
+	dev, err := deviceOf(full)
+	return err == nil && dev != f.dev
+}
+
+// deviceOf returns the device of the file at path; tests replace it.
+var deviceOf = device
@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package osfs
+
+import "errors"
+
+// device returns the device of the file at path, which cannot be found on
+// this system.
+func device(path string) (uint64, error) {
+	return 0, errors.ErrUnsupported
+}
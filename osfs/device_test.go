@@ -0,0 +1,77 @@
+package osfs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+func TestWithSameDevice(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"local/file.txt", "mnt/file.txt", "mnt/sub/file.txt"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	mnt := filepath.Join(dir, "mnt")
+	deviceOf = func(path string) (uint64, error) {
+		if path == mnt || strings.HasPrefix(path, mnt+string(filepath.Separator)) {
+			return 2, nil
+		}
+		return 1, nil
+	}
+	defer func() { deviceOf = device }()
+
+	for _, tt := range []struct {
+		opts     []Option
+		walk     []bfwalk.Option
+		expected []string
+	}{
+		{nil, nil, []string{".", "local", "mnt", "local/file.txt", "mnt/file.txt", "mnt/sub", "mnt/sub/file.txt"}},
+		{[]Option{WithSameDevice()}, nil, []string{".", "local", "mnt", "local/file.txt"}},
+		{[]Option{WithSameDevice()}, []bfwalk.Option{bfwalk.WithUnsorted()}, []string{".", "local", "mnt", "local/file.txt"}},
+	} {
+		var visited []string
+		err := bfwalk.NewWalker(tt.walk...).WalkDir(New(dir, tt.opts...), ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			visited = append(visited, path)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tt.walk != nil {
+			// Unsorted walks list the entries of a directory in any order
+			slices.Sort(visited)
+			tt.expected = slices.Sorted(slices.Values(tt.expected))
+		}
+		if !slices.Equal(visited, tt.expected) {
+			t.Errorf("expected:\n  %v\ngot\n: %v", tt.expected, visited)
+		}
+	}
+}
+
+func TestDevice(t *testing.T) {
+	dir := t.TempDir()
+	root, err := device(dir)
+	if err != nil {
+		t.Skipf("cannot read devices: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dev, err := device(filepath.Join(dir, "sub")); err != nil || dev != root {
+		t.Errorf("expected device %v for sub, got %v, %v", root, dev, err)
+	}
+}
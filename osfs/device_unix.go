@@ -0,0 +1,22 @@
+//go:build unix
+
+package osfs
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// device returns the device of the file at path.
+func device(path string) (uint64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, errors.ErrUnsupported
+	}
+	return uint64(st.Dev), nil
+}
@@ -0,0 +1,120 @@
+package osfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// ErrCycleDetected is the error, wrapped in a [*CycleError], returned when
+// reading a directory of an FS that follows links that is also one of its
+// ancestors.
+var ErrCycleDetected = errors.New("cycle detected")
+
+// A CycleError records a directory that is also one of its ancestors, which
+// would be walked forever.
+type CycleError struct {
+	Path     string // name of the directory read
+	Ancestor string // name of the ancestor it is the same directory as
+}
+
+func (e *CycleError) Error() string {
+	return "osfs: cycle detected: " + e.Path + " is " + e.Ancestor
+}
+
+func (e *CycleError) Unwrap() error {
+	return ErrCycleDetected
+}
+
+// WithFollowLinks makes the FS follow symbolic links, NTFS junctions and
+// other reparse points found in directories, reading them as entries
+// describing their target, so that walks descend into linked directories.
+// Links whose target does not exist are still read as entries of type
+// [fs.ModeSymlink].
+//
+// Reading a directory that is the same directory as one of its ancestors,
+// as reached through a link to an ancestor, a loop of relative links or a
+// bind mount, fails with a [*CycleError], since walking it would never end.
+// Directories are compared by the device and inode number they resolve to,
+// as [os.SameFile] does, rather than by path.
+func WithFollowLinks() Option {
+	return func(f *FS) {
+		f.follow = true
+	}
+}
+
+// IsLink reports whether d, read from a directory of an [FS], is a symbolic
+// link, an NTFS junction or another reparse point that stands for another
+// file.
+func IsLink(d fs.DirEntry) bool {
+	return d.Type()&fs.ModeSymlink != 0 || isReparsePoint(d)
+}
+
+// links replaces the links among dirs, the entries of the directory named
+// name, with entries of type [fs.ModeSymlink] or, when links are followed,
+// with entries describing their target.
+func (f *FS) links(name string, dirs []fs.DirEntry) []fs.DirEntry {
+	for i, d := range dirs {
+		if !IsLink(d) {
+			continue
+		}
+		info, err := d.Info()
+		if err != nil {
+			continue
+		}
+		dirs[i] = fs.FileInfoToDirEntry(linkInfo{info})
+		if !f.follow {
+			continue
+		}
+		target, err := f.Stat(path.Join(name, d.Name()))
+		if err != nil {
+			continue
+		}
+		dirs[i] = fs.FileInfoToDirEntry(namedInfo{target, d.Name()})
+	}
+	return dirs
+}
+
+// checkCycle returns a [*CycleError] if the directory named name is the
+// same directory as one of its ancestors.
+func (f *FS) checkCycle(name string) error {
+	if name == "." {
+		return nil
+	}
+	dir, err := f.Stat(name)
+	if err != nil {
+		return nil // Reported by ReadDir
+	}
+	for a := path.Dir(name); ; a = path.Dir(a) {
+		if info, err := f.Stat(a); err == nil && os.SameFile(dir, info) {
+			return &CycleError{name, a}
+		}
+		if a == "." {
+			return nil
+		}
+	}
+}
+
+// linkInfo describes a link as a file of type [fs.ModeSymlink].
+type linkInfo struct {
+	fs.FileInfo
+}
+
+func (i linkInfo) Mode() fs.FileMode {
+	return i.FileInfo.Mode()&fs.ModePerm | fs.ModeSymlink
+}
+
+func (i linkInfo) IsDir() bool {
+	return false
+}
+
+// namedInfo describes the target of a link under the name of the link.
+type namedInfo struct {
+	fs.FileInfo
+	name string
+}
+
+func (i namedInfo) Name() string {
+	return i.name
+}
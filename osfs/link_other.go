@@ -0,0 +1,11 @@
+//go:build !windows
+
+package osfs
+
+import "io/fs"
+
+// isReparsePoint reports whether d is a Windows reparse point that stands
+// for another file, which is never the case on other systems.
+func isReparsePoint(d fs.DirEntry) bool {
+	return false
+}
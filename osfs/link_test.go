@@ -0,0 +1,146 @@
+package osfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+// linkTree creates a tree holding links to a directory, to a file, to an
+// ancestor, to each other and to nothing.
+func linkTree(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"a/sub", "b"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a/sub/file.txt"), nil, 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	links := []struct{ target, name string }{
+		{"../a/sub", "b/tosub"},
+		{"../a/sub/file.txt", "b/tofile"},
+		{"..", "a/toroot"},
+		{"../../b", "a/sub/tob"},
+		{"missing", "b/broken"},
+	}
+	for _, l := range links {
+		if err := os.Symlink(filepath.FromSlash(l.target), filepath.Join(dir, filepath.FromSlash(l.name))); err != nil {
+			t.Skipf("cannot create symbolic links: %v", err)
+		}
+	}
+	return dir
+}
+
+func walkTypes(t *testing.T, fsys fs.FS, opts ...bfwalk.Option) (map[string]fs.FileMode, map[string]*CycleError) {
+	t.Helper()
+	types := make(map[string]fs.FileMode)
+	cycles := make(map[string]*CycleError)
+	err := bfwalk.NewWalker(opts...).WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		var cerr *CycleError
+		if errors.As(err, &cerr) {
+			cycles[path] = cerr
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(types) > 100 {
+			t.Fatal("walk does not end")
+		}
+		types[path] = d.Type()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return types, cycles
+}
+
+func TestLinks(t *testing.T) {
+	types, cycles := walkTypes(t, New(linkTree(t)))
+	if len(cycles) > 0 {
+		t.Errorf("cycles detected without following links: %v", cycles)
+	}
+	for _, name := range []string{"b/tosub", "b/tofile", "a/toroot", "a/sub/tob", "b/broken"} {
+		if types[name] != fs.ModeSymlink {
+			t.Errorf("%s: expected type %v, got %v", name, fs.ModeSymlink, types[name])
+		}
+	}
+	if _, ok := types["b/tosub/file.txt"]; ok {
+		t.Error("walk descended into a link")
+	}
+}
+
+func TestWithFollowLinks(t *testing.T) {
+	t.Run("sorted", func(t *testing.T) {
+		testFollowLinks(t)
+	})
+	t.Run("unsorted", func(t *testing.T) {
+		testFollowLinks(t, bfwalk.WithUnsorted())
+	})
+}
+
+func testFollowLinks(t *testing.T, opts ...bfwalk.Option) {
+	types, cycles := walkTypes(t, New(linkTree(t), WithFollowLinks()), opts...)
+	expected := map[string]fs.FileMode{
+		"b/tosub":          fs.ModeDir,
+		"b/tosub/file.txt": 0,
+		"b/tosub/tob":      fs.ModeDir,
+		"b/tofile":         0,
+		"a/toroot":         fs.ModeDir,
+		"a/sub/tob":        fs.ModeDir,
+		"a/sub/tob/tosub":  fs.ModeDir,
+		"a/sub/tob/tofile": 0,
+		"a/sub/tob/broken": fs.ModeSymlink,
+		"b/broken":         fs.ModeSymlink,
+	}
+	expectedCycles := map[string]string{
+		"b/tosub/tob":     "b", // b/tosub/tob links back to b
+		"a/toroot":        ".",
+		"a/sub/tob/tosub": "a/sub",
+	}
+	for name, ancestor := range expectedCycles {
+		cerr, ok := cycles[name]
+		if !ok {
+			t.Errorf("no cycle detected at %s", name)
+			continue
+		}
+		if cerr.Path != name || cerr.Ancestor != ancestor || !errors.Is(cerr, ErrCycleDetected) {
+			t.Errorf("%s: expected a cycle to %s, got %+v", name, ancestor, cerr)
+		}
+	}
+	if len(cycles) != len(expectedCycles) {
+		t.Errorf("expected cycles:\n  %v\ngot\n: %v", expectedCycles, cycles)
+	}
+	for name, typ := range expected {
+		got, ok := types[name]
+		if !ok {
+			t.Errorf("walk did not visit %s", name)
+		} else if got != typ {
+			t.Errorf("%s: expected type %v, got %v", name, typ, got)
+		}
+	}
+	for name := range types {
+		if _, ok := expected[name]; !ok && !slices.Contains([]string{".", "a", "b", "a/sub", "a/sub/file.txt"}, name) {
+			t.Errorf("walk visited %s", name)
+		}
+	}
+}
+
+func TestReadLink(t *testing.T) {
+	fsys := New(linkTree(t))
+	if target, err := fsys.ReadLink("b/tosub"); err != nil || target != "../a/sub" {
+		t.Errorf("expected target %q, got %q, %v", "../a/sub", target, err)
+	}
+	if _, err := fsys.ReadLink("a/sub/file.txt"); err == nil {
+		t.Error("expected an error reading a file as a link")
+	}
+}
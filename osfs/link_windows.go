@@ -0,0 +1,22 @@
+package osfs
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// isReparsePoint reports whether d is a reparse point that stands for
+// another file, such as an NTFS junction. The attributes of such entries
+// mark them as directories, though they are not read as directories.
+func isReparsePoint(d fs.DirEntry) bool {
+	if d.IsDir() {
+		return false
+	}
+	info, err := d.Info()
+	if err != nil {
+		return false
+	}
+	attrs, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	const mask = syscall.FILE_ATTRIBUTE_REPARSE_POINT | syscall.FILE_ATTRIBUTE_DIRECTORY
+	return ok && attrs.FileAttributes&mask == mask
+}
@@ -0,0 +1,256 @@
+// Package osfs provides an [fs.FS] over a directory of the operating system,
+// for walking with [bfwalk.WalkDir]. Unlike [os.DirFS], it can walk trees
+// whose paths are longer than the 260 character MAX_PATH limit of Windows.
+// Its [WalkDir] function is a breadth-first replacement for
+// [filepath.WalkDir] taking and reporting native paths.
+package osfs
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// FS is the file system of the tree rooted at a directory of the operating
+// system. It implements [fs.ReadDirFS], [fs.ReadFileFS] and [fs.StatFS],
+// reads the targets of symbolic links with ReadLink, and can be written to
+// as a [bfwalk.WriteFS] and synced to as a [bfwalk.SyncFS].
+type FS struct {
+	dir        string   // path of the root directory, in extended-length form on Windows
+	follow     bool     // whether to follow links found in directories
+	sameDevice bool     // whether to read directories on other devices as empty
+	xattrs     []string // names of extended attributes to read
+
+	rootDev sync.Once
+	dev     uint64 // device of the root directory
+	devErr  error
+}
+
+// An Option configures an [FS].
+type Option func(*FS)
+
+// New returns the file system of the tree rooted at the directory dir,
+// configured with opts.
+//
+// On Windows, dir is made absolute and given the \\?\ extended-length
+// prefix, so that the tree can be walked however deep it is. Errors report
+// the slash-separated names given to the methods of FS, as with [os.DirFS].
+//
+// Symbolic links, NTFS junctions and other reparse points that stand for
+// another file are read from directories as entries of type
+// [fs.ModeSymlink], so that walks do not descend into them unless the FS is
+// created with [WithFollowLinks].
+func New(dir string, opts ...Option) *FS {
+	if runtime.GOOS == "windows" {
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = extendedPath(abs)
+		}
+	}
+	f := &FS{dir: dir}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Open opens the named file. Directories opened list their entries with
+// ReadDir as [FS.ReadDir] does, in the order the operating system lists
+// them, so that walks listing open directories, such as those of
+// [bfwalk.WithUnsorted], read the same entries.
+func (f *FS) Open(name string) (fs.File, error) {
+	full, err := f.join("open", name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(full)
+	if err != nil {
+		return nil, rename(err, name)
+	}
+	return &dir{File: file, fsys: f, name: name, full: full}, nil
+}
+
+// ReadDir reads the named directory and returns its entries sorted by name.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	full, err := f.join("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if read, err := f.readable(name, full); !read {
+		return nil, err
+	}
+	dirs, err := os.ReadDir(full)
+	if err != nil {
+		return dirs, rename(err, name)
+	}
+	return f.entries(name, dirs), nil
+}
+
+// readable reports whether the directory name, at the native path full,
+// should be read, and the error reading it fails with if not.
+func (f *FS) readable(name, full string) (bool, error) {
+	if f.sameDevice && f.otherDevice(full) {
+		return false, nil
+	}
+	if f.follow {
+		if err := f.checkCycle(name); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// entries prepares dirs, entries read from the directory name, to be
+// returned by ReadDir.
+func (f *FS) entries(name string, dirs []fs.DirEntry) []fs.DirEntry {
+	dirs = f.links(name, dirs)
+	if len(f.xattrs) > 0 {
+		dirs = f.withXattrs(name, dirs)
+	}
+	return dirs
+}
+
+// dir is a file opened from an [FS], whose ReadDir method lists the entries
+// of a directory as FS.ReadDir does, without sorting them.
+type dir struct {
+	*os.File
+	fsys    *FS
+	name    string
+	full    string
+	checked bool // whether the directory was checked before its first read
+	skip    bool // whether the directory is read as empty
+}
+
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.checked {
+		d.checked = true
+		read, err := d.fsys.readable(d.name, d.full)
+		if err != nil {
+			return nil, err
+		}
+		d.skip = !read
+	}
+	if d.skip {
+		if n > 0 {
+			return nil, io.EOF
+		}
+		return nil, nil
+	}
+	dirs, err := d.File.ReadDir(n)
+	if err != nil && err != io.EOF {
+		err = rename(err, d.name)
+	}
+	return d.fsys.entries(d.name, dirs), err
+}
+
+// ReadFile reads the named file and returns its contents.
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	full, err := f.join("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(full)
+	return data, rename(err, name)
+}
+
+// Stat returns a [fs.FileInfo] describing the named file.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	full, err := f.join("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(full)
+	if err != nil {
+		return nil, rename(err, name)
+	}
+	return info, nil
+}
+
+// ReadLink returns the target of the named symbolic link, with slashes as
+// separators.
+func (f *FS) ReadLink(name string) (string, error) {
+	full, err := f.join("readlink", name)
+	if err != nil {
+		return "", err
+	}
+	target, err := os.Readlink(full)
+	if err != nil {
+		return "", rename(err, name)
+	}
+	return filepath.ToSlash(target), nil
+}
+
+// MkdirAll creates the named directory along with any missing parents, with
+// permission bits perm before the umask, doing nothing if it already exists.
+func (f *FS) MkdirAll(name string, perm fs.FileMode) error {
+	full, err := f.join("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return rename(os.MkdirAll(full, perm), name)
+}
+
+// WriteFile writes data to the named file, creating it with permission bits
+// perm before the umask if needed, and truncating it otherwise.
+func (f *FS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	full, err := f.join("writefile", name)
+	if err != nil {
+		return err
+	}
+	return rename(os.WriteFile(full, data, perm), name)
+}
+
+// RemoveAll removes the named file or directory and everything it
+// contains, doing nothing if it does not exist. The root itself cannot be
+// removed.
+func (f *FS) RemoveAll(name string) error {
+	full, err := f.join("removeall", name)
+	if err != nil {
+		return err
+	}
+	if name == "." {
+		return &fs.PathError{Op: "removeall", Path: name, Err: fs.ErrInvalid}
+	}
+	return rename(os.RemoveAll(full), name)
+}
+
+// join returns the operating system path of the file named name, or an
+// error for operation op if name is not a valid path.
+func (f *FS) join(op, name string) (string, error) {
+	local, err := filepath.Localize(name)
+	if err != nil || !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if local == "." {
+		return f.dir, nil
+	}
+	if f.dir != "" && os.IsPathSeparator(f.dir[len(f.dir)-1]) {
+		return f.dir + local, nil
+	}
+	return f.dir + string(filepath.Separator) + local, nil
+}
+
+// rename replaces the operating system path in err, if any, with name.
+func rename(err error, name string) error {
+	if pe, ok := err.(*fs.PathError); ok {
+		pe.Path = name
+	}
+	return err
+}
+
+// extendedPath returns the extended-length form of the absolute Windows path
+// p, which lifts the MAX_PATH limit on its length. UNC paths take the
+// \\?\UNC\ prefix, and paths that already have a \\?\ or \\.\ prefix are
+// returned unchanged.
+func extendedPath(p string) string {
+	switch {
+	case strings.HasPrefix(p, `\\?\`), strings.HasPrefix(p, `\\.\`):
+		return p
+	case strings.HasPrefix(p, `\\`):
+		return `\\?\UNC\` + p[2:]
+	}
+	return `\\?\` + p
+}
@@ -0,0 +1,188 @@
+package osfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+func TestFS(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a/file1.txt", "a/b/file2.txt", "c/file3.txt"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(name), 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := fstest.TestFS(New(dir), "a/file1.txt", "a/b/file2.txt", "c/file3.txt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFSLongPaths(t *testing.T) {
+	dir := t.TempDir()
+	elem := strings.Repeat("d", 50)
+	names := make([]string, 8)
+	for i := range names {
+		names[i] = elem
+	}
+	deep := filepath.Join(append([]string{dir}, names...)...)
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(deep, "file.txt"), nil, 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	long := strings.Join(names, "/") + "/file.txt"
+	if len(long) <= 260 {
+		t.Fatalf("path %q is not longer than MAX_PATH", long)
+	}
+	var found bool
+	err := bfwalk.WalkDir(New(dir), ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		found = found || path == long
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !found {
+		t.Errorf("walk did not visit %s", long)
+	}
+}
+
+func TestFSErrors(t *testing.T) {
+	fsys := New(t.TempDir())
+
+	_, err := fsys.Open("missing/file.txt")
+	var pe *fs.PathError
+	if !errors.As(err, &pe) || pe.Path != "missing/file.txt" || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected a not exist error for missing/file.txt, got %v", err)
+	}
+	for _, name := range []string{"../escape", "/abs", "a//b"} {
+		if _, err := fsys.ReadDir(name); !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("%s: expected %v, got %v", name, fs.ErrInvalid, err)
+		}
+	}
+}
+
+func TestExtendedPath(t *testing.T) {
+	tests := []struct {
+		path, expected string
+	}{
+		{`C:\dir\file`, `\\?\C:\dir\file`},
+		{`C:\`, `\\?\C:\`},
+		{`\\server\share\dir`, `\\?\UNC\server\share\dir`},
+		{`\\?\C:\dir`, `\\?\C:\dir`},
+		{`\\.\pipe\name`, `\\.\pipe\name`},
+	}
+	for _, tt := range tests {
+		if got := extendedPath(tt.path); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestFSWrite(t *testing.T) {
+	src := fstest.MapFS{
+		"root/file1.txt":      {Data: []byte("one"), Mode: 0o644},
+		"root/dirA/file2.txt": {Data: []byte("two"), Mode: 0o644},
+	}
+	dst := New(t.TempDir())
+	if err := bfwalk.Copy(dst, src, "root"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fstest.TestFS(dst, "root/file1.txt", "root/dirA/file2.txt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, err := dst.ReadFile("root/dirA/file2.txt"); err != nil || string(data) != "two" {
+		t.Errorf("expected contents %q, got %q, %v", "two", data, err)
+	}
+	if err := dst.WriteFile("../escape", nil, 0o644); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("expected %v writing ../escape, got %v", fs.ErrInvalid, err)
+	}
+}
+
+func TestFSSync(t *testing.T) {
+	src := fstest.MapFS{
+		"file1.txt":      {Data: []byte("one"), Mode: 0o644},
+		"dirA/file2.txt": {Data: []byte("two"), Mode: 0o644},
+	}
+	dst := New(t.TempDir())
+	if err := dst.WriteFile("extra.txt", []byte("extra"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := bfwalk.Sync(dst, src, "."); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fstest.TestFS(dst, "file1.txt", "dirA/file2.txt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := dst.Stat("extra.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected extra.txt to be removed, got %v", err)
+	}
+	if err := dst.RemoveAll("."); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("expected %v removing the root, got %v", fs.ErrInvalid, err)
+	}
+}
+
+func TestFSSyncDirToFile(t *testing.T) {
+	src := fstest.MapFS{"x": {Data: []byte("file"), Mode: 0o644}}
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "x", "child"), 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dst := New(dir)
+	if _, err := bfwalk.Sync(dst, src, "."); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, err := dst.ReadFile("x"); err != nil || string(data) != "file" {
+		t.Errorf("expected directory to be replaced by the file, got %q, %v", data, err)
+	}
+}
+
+func TestFSSyncSymlink(t *testing.T) {
+	dir := t.TempDir()
+	outside := filepath.Join(dir, "outside.txt")
+	if err := os.WriteFile(outside, []byte("outside"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root := filepath.Join(dir, "dst")
+	if err := os.Mkdir(root, 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "file.txt")); err != nil {
+		t.Skipf("cannot create symlinks: %v", err)
+	}
+
+	src := fstest.MapFS{"file.txt": {Data: []byte("inside"), Mode: 0o644}}
+	dst := New(root)
+	if _, err := bfwalk.Sync(dst, src, "."); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, err := os.ReadFile(outside); err != nil || string(data) != "outside" {
+		t.Errorf("expected file outside dst to be left alone, got %q, %v", data, err)
+	}
+	info, err := os.Lstat(filepath.Join(root, "file.txt"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.Mode().IsRegular() {
+		t.Errorf("expected link to be replaced by a regular file, got %v", info.Mode())
+	}
+	if data, _ := dst.ReadFile("file.txt"); string(data) != "inside" {
+		t.Errorf("expected synced contents %q, got %q", "inside", data)
+	}
+}
@@ -0,0 +1,31 @@
+package osfs
+
+import (
+	"io/fs"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+// Owner returns a [bfwalk.Expr] matching the entries of an [FS] owned by
+// the user with the numeric id uid, for use with [bfwalk.WithFilter] and
+// [bfwalk.WithPrune]. For example, the files of a tree not owned by root
+// are walked with
+//
+//	bfwalk.WithFilter(bfwalk.And(bfwalk.Type('f'), bfwalk.Not(osfs.Owner(0))))
+//
+// Owners are only known on Unix systems; elsewhere Owner matches no entry.
+func Owner(uid int) bfwalk.Expr {
+	return func(d fs.DirEntry) bool {
+		id, _, ok := owner(d)
+		return ok && id == uid
+	}
+}
+
+// Group returns a [bfwalk.Expr] matching the entries of an [FS] owned by
+// the group with the numeric id gid, like [Owner].
+func Group(gid int) bfwalk.Expr {
+	return func(d fs.DirEntry) bool {
+		_, id, ok := owner(d)
+		return ok && id == gid
+	}
+}
@@ -0,0 +1,47 @@
+//go:build unix
+
+package osfs
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+func TestOwner(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "sub/b.txt"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	fsys := New(dir)
+	uid, gid := os.Getuid(), os.Getgid()
+
+	tests := []struct {
+		name     string
+		expr     bfwalk.Expr
+		expected []string
+	}{
+		{"owner", bfwalk.And(bfwalk.Type('f'), Owner(uid)), []string{".", "a.txt", "sub/b.txt"}},
+		{"other owner", Owner(uid + 1), []string{"."}},
+		{"group", bfwalk.And(Group(gid), bfwalk.Not(bfwalk.Type('d'))), []string{".", "a.txt", "sub/b.txt"}},
+		{"other group", Group(gid + 1), []string{"."}},
+	}
+	for _, tt := range tests {
+		got, err := bfwalk.Collect(fsys, ".", bfwalk.WithFilter(tt.expr))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
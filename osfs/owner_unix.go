@@ -0,0 +1,21 @@
+//go:build unix
+
+package osfs
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// owner returns the user and group ids owning the file of d.
+func owner(d fs.DirEntry) (uid, gid int, ok bool) {
+	info, err := d.Info()
+	if err != nil {
+		return 0, 0, false
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(st.Uid), int(st.Gid), true
+}
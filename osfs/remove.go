@@ -0,0 +1,131 @@
+package osfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+// A RemoveOption configures [RemoveMatching].
+type RemoveOption func(*removeConfig)
+
+type removeConfig struct {
+	dryRun    bool
+	emptyDirs bool
+}
+
+// RemoveDryRun makes [RemoveMatching] report the paths it would remove
+// without removing anything.
+func RemoveDryRun() RemoveOption {
+	return func(c *removeConfig) {
+		c.dryRun = true
+	}
+}
+
+// RemoveEmptyDirs makes [RemoveMatching] also remove the directories that
+// removing matched entries leaves empty, deepest first. Directories that
+// were empty before are kept.
+func RemoveEmptyDirs() RemoveOption {
+	return func(c *removeConfig) {
+		c.emptyDirs = true
+	}
+}
+
+// RemoveMatching walks the tree rooted at the directory root breadth-first
+// and removes the entries for which match returns true, returning the paths
+// removed in the order they were removed. Paths are slash-separated and
+// relative to root, as passed to match.
+//
+// A matched directory is removed along with everything in it, without
+// descending into it. Symbolic links, junctions and other reparse points are
+// removed themselves, never what they link to. The root itself is never
+// removed, and nothing outside it is.
+//
+// The first error encountered stops the removal and is returned, together
+// with the paths removed before it.
+func RemoveMatching(root string, match func(path string, d fs.DirEntry) bool, opts ...RemoveOption) ([]string, error) {
+	var c removeConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if root == "" {
+		return nil, &fs.PathError{Op: "remove", Path: root, Err: fs.ErrInvalid}
+	}
+	fsys := New(root)
+
+	var removed, dirs []string
+	entries := map[string]int{} // entries of each directory
+	gone := map[string]int{}    // entries removed from each directory
+	remove := func(name string, all bool) error {
+		if !c.dryRun {
+			full, err := fsys.join("remove", name)
+			if err != nil {
+				return err
+			}
+			if all {
+				err = os.RemoveAll(full)
+			} else {
+				err = os.Remove(full)
+			}
+			if err != nil {
+				return rename(err, name)
+			}
+		}
+		removed = append(removed, name)
+		gone[path.Dir(name)]++
+		return nil
+	}
+
+	w := bfwalk.NewWalker(bfwalk.WithOnDirComplete(func(path string, n int) {
+		entries[path] = n
+	}))
+	err := w.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+		if !match(name, d) {
+			if d.IsDir() {
+				dirs = append(dirs, name)
+			}
+			return nil
+		}
+		if err := remove(name, d.IsDir()); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil || !c.emptyDirs {
+		return removed, err
+	}
+
+	// Directories are visited breadth-first, so children come after their
+	// parents and are removed first.
+	for _, name := range slices.Backward(dirs) {
+		if n := entries[name]; n == 0 || gone[name] < n {
+			continue
+		}
+		if err := remove(name, false); err != nil {
+			if errors.Is(err, fs.ErrExist) || isNotEmpty(fsys, name) {
+				continue // Holds entries created since it was read
+			}
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// isNotEmpty reports whether the directory name of fsys has entries.
+func isNotEmpty(fsys *FS, name string) bool {
+	dirs, err := fsys.ReadDir(name)
+	return err == nil && len(dirs) > 0
+}
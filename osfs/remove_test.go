@@ -0,0 +1,121 @@
+package osfs
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// cacheTree creates a tree of sources and caches to clean up.
+func cacheTree(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{
+		"main.go",
+		"main.pyc",
+		"pkg/__pycache__/a.pyc",
+		"pkg/lib.py",
+		"pkg/lib.pyc",
+		"only/cached/x.pyc",
+		"only/y.pyc",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "empty"), 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return dir
+}
+
+func isCache(name string, d fs.DirEntry) bool {
+	return path.Ext(name) == ".pyc" || d.IsDir() && path.Base(name) == "__pycache__"
+}
+
+func remaining(t *testing.T, dir string) []string {
+	t.Helper()
+	var names []string
+	err := fs.WalkDir(os.DirFS(dir), ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return names
+}
+
+func TestRemoveMatching(t *testing.T) {
+	dir := cacheTree(t)
+	removed, err := RemoveMatching(dir, isCache)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"main.pyc", "only/y.pyc", "pkg/__pycache__", "pkg/lib.pyc", "only/cached/x.pyc"}
+	if !slices.Equal(removed, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, removed)
+	}
+	left := []string{".", "empty", "main.go", "only", "only/cached", "pkg", "pkg/lib.py"}
+	if got := remaining(t, dir); !slices.Equal(got, left) {
+		t.Errorf("expected left:\n  %v\ngot\n: %v", left, got)
+	}
+}
+
+func TestRemoveMatchingEmptyDirs(t *testing.T) {
+	dir := cacheTree(t)
+	expected := []string{
+		"main.pyc", "only/y.pyc", "pkg/__pycache__", "pkg/lib.pyc", "only/cached/x.pyc",
+		"only/cached", "only",
+	}
+
+	removed, err := RemoveMatching(dir, isCache, RemoveEmptyDirs(), RemoveDryRun())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(removed, expected) {
+		t.Errorf("dry run: expected:\n  %v\ngot\n: %v", expected, removed)
+	}
+	if got := remaining(t, dir); len(got) != 13 {
+		t.Errorf("dry run changed the tree: %v", got)
+	}
+
+	removed, err = RemoveMatching(dir, isCache, RemoveEmptyDirs())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(removed, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, removed)
+	}
+	left := []string{".", "empty", "main.go", "pkg", "pkg/lib.py"}
+	if got := remaining(t, dir); !slices.Equal(got, left) {
+		t.Errorf("expected left:\n  %v\ngot\n: %v", left, got)
+	}
+}
+
+func TestRemoveMatchingRoot(t *testing.T) {
+	dir := cacheTree(t)
+	removed, err := RemoveMatching(dir, func(string, fs.DirEntry) bool { return true }, RemoveEmptyDirs())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(removed) != 5 {
+		t.Errorf("expected the 5 entries of the root to be removed, got %v", removed)
+	}
+	if got := remaining(t, dir); !slices.Equal(got, []string{"."}) {
+		t.Errorf("expected only the root to be left, got %v", got)
+	}
+	if _, err := RemoveMatching("", isCache); err == nil {
+		t.Error("RemoveMatching with an empty root succeeded")
+	}
+}
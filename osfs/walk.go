@@ -0,0 +1,62 @@
+package osfs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+// WalkDir walks the file tree rooted at root breadth-first, calling fn for
+// each file or directory in the tree, including root, with a
+// [bfwalk.Walker] configured with opts.
+//
+// WalkDir is a drop-in replacement for [filepath.WalkDir] that visits every
+// entry at a given depth before descending further: root is a native path,
+// which may be relative, have a Windows drive letter or be a UNC path, fn
+// is passed the native paths filepath.WalkDir would pass it, starting with
+// root itself as given, and errors report native paths. As with
+// filepath.WalkDir, symbolic links are not followed, including root
+// itself, and the entries of each directory are visited in lexical order
+// unless opts say otherwise. The paths of deep trees on Windows are read
+// in extended-length form, as with [New]. Paths are always joined to root,
+// so [bfwalk.WithPathPrefix] has no effect.
+func WalkDir(root string, fn fs.WalkDirFunc, opts ...bfwalk.Option) error {
+	info, err := os.Lstat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else if !info.IsDir() {
+		// A file, or a link that is not followed
+		err = fn(root, fs.FileInfoToDirEntry(info), nil)
+	} else {
+		opts = append(slices.Clone(opts), bfwalk.WithRelativePaths(), bfwalk.WithPathPrefix(""))
+		err = bfwalk.NewWalker(opts...).WalkDir(New(root), ".", func(name string, d fs.DirEntry, err error) error {
+			path := root
+			if name != "." {
+				path = filepath.Join(root, filepath.FromSlash(name))
+			}
+			return fn(path, d, nativeError(err, root))
+		})
+	}
+	if err == fs.SkipDir || err == fs.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// nativeError returns err with the slash-separated path below root it
+// reports, if any, replaced with the native path.
+func nativeError(err error, root string) error {
+	pe, ok := err.(*fs.PathError)
+	if !ok {
+		return err
+	}
+	native := *pe
+	native.Path = root
+	if pe.Path != "." {
+		native.Path = filepath.Join(root, filepath.FromSlash(pe.Path))
+	}
+	return &native
+}
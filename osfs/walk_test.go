@@ -0,0 +1,124 @@
+package osfs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+func TestWalkDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a/b/file", "a/file", "c"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(filepath.Join(dir, "a"), link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	walk := func(root string, walkDir func(string, fs.WalkDirFunc) error) []string {
+		t.Helper()
+		var paths []string
+		err := walkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			paths = append(paths, path)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return paths
+	}
+	bfs := func(root string, fn fs.WalkDirFunc) error { return WalkDir(root, fn) }
+
+	root := dir + string(filepath.Separator) // Reported as given
+	got := walk(root, bfs)
+	expected := []string{
+		root,
+		filepath.Join(dir, "a"),
+		filepath.Join(dir, "c"),
+		link,
+		filepath.Join(dir, "a", "b"),
+		filepath.Join(dir, "a", "file"),
+		filepath.Join(dir, "a", "b", "file"),
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, got)
+	}
+	depthFirst := walk(root, filepath.WalkDir)
+	if !slices.Equal(slices.Sorted(slices.Values(got)), slices.Sorted(slices.Values(depthFirst))) {
+		t.Errorf("expected the paths of filepath.WalkDir %v, got %v", depthFirst, got)
+	}
+
+	// Paths are joined to root whatever prefix is given, and the options of
+	// the caller are left unchanged
+	opts := make([]bfwalk.Option, 1, 4)
+	opts[0] = bfwalk.WithPathPrefix("other")
+	prefixed := func(root string, fn fs.WalkDirFunc) error { return WalkDir(root, fn, opts...) }
+	if got := walk(root, prefixed); !slices.Equal(got, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, got)
+	}
+	if opts[:2][1] != nil {
+		t.Errorf("expected the options of the caller to be left unchanged")
+	}
+
+	// A link as root is not followed
+	if got := walk(link, bfs); !slices.Equal(got, []string{link}) {
+		t.Errorf("expected only %s, got %v", link, got)
+	}
+}
+
+func TestWalkDirErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	var calls int
+	err := WalkDir(missing, func(path string, d fs.DirEntry, err error) error {
+		calls++
+		if path != missing || d != nil {
+			t.Errorf("expected %s without entry, got %s and %v", missing, path, d)
+		}
+		return err
+	})
+	var pe *fs.PathError
+	if !errors.As(err, &pe) || pe.Path != missing || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected a not exist error for %s, got %v", missing, err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+
+	err = WalkDir(missing, func(path string, d fs.DirEntry, err error) error {
+		return fs.SkipDir
+	})
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if os.Getuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+	dir := t.TempDir()
+	locked := filepath.Join(dir, "locked")
+	if err := os.Mkdir(locked, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Chmod(locked, 0o755)
+	err = WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		return err
+	})
+	if !errors.As(err, &pe) || pe.Path != locked {
+		t.Errorf("expected an error for %s, got %v", locked, err)
+	}
+}
@@ -0,0 +1,93 @@
+package osfs
+
+import (
+	"io/fs"
+	"path"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+// WithXattrs makes the FS read the extended attributes of the given names,
+// such as "user.checksum" or "com.apple.quarantine", of every entry as each
+// directory is read, so that walks get them with [Xattrs] or
+// [FS.WalkDirXattrs] without a second pass over the tree.
+//
+// Attributes an entry does not have, or that cannot be read, are left out.
+// Extended attributes are read on Linux, macOS, FreeBSD and NetBSD; on
+// other systems no attribute is read.
+func WithXattrs(names ...string) Option {
+	return func(f *FS) {
+		f.xattrs = append(f.xattrs, names...)
+	}
+}
+
+// A WalkDirXattrFunc is the type of the function called by
+// [FS.WalkDirXattrs] for each file or directory visited. It behaves like an
+// [fs.WalkDirFunc], and is passed the extended attributes read for the
+// entry, keyed by name.
+type WalkDirXattrFunc func(path string, d fs.DirEntry, xattrs map[string][]byte, err error) error
+
+// WalkDirXattrs walks the file tree of f rooted at root with a
+// [bfwalk.Walker] configured with opts, passing fn the extended attributes
+// read for each entry, as configured with [WithXattrs].
+func (f *FS) WalkDirXattrs(root string, fn WalkDirXattrFunc, opts ...bfwalk.Option) error {
+	first := true
+	return bfwalk.NewWalker(opts...).WalkDir(f, root, func(p string, d fs.DirEntry, err error) error {
+		attrs := Xattrs(d)
+		if first {
+			// The root is not read from a directory
+			first = false
+			if d != nil && len(f.xattrs) > 0 {
+				attrs = f.readXattrs(root, IsLink(d))
+			}
+		}
+		return fn(p, d, attrs, err)
+	})
+}
+
+// Xattrs returns the extended attributes read for d, an entry read from a
+// directory of an [FS] created with [WithXattrs], keyed by name. It returns
+// nil for other entries.
+func Xattrs(d fs.DirEntry) map[string][]byte {
+	for {
+		switch e := d.(type) {
+		case xattrEntry:
+			return e.attrs
+		case *bfwalk.CachedEntry:
+			d = e.DirEntry
+		default:
+			return nil
+		}
+	}
+}
+
+// An xattrEntry is a directory entry with its extended attributes.
+type xattrEntry struct {
+	fs.DirEntry
+	attrs map[string][]byte
+}
+
+// withXattrs replaces each of dirs, the entries of the directory named
+// name, with an entry holding its extended attributes.
+func (f *FS) withXattrs(name string, dirs []fs.DirEntry) []fs.DirEntry {
+	for i, d := range dirs {
+		dirs[i] = xattrEntry{d, f.readXattrs(path.Join(name, d.Name()), IsLink(d))}
+	}
+	return dirs
+}
+
+// readXattrs reads the extended attributes of the file named name, or of
+// the link itself if link is true.
+func (f *FS) readXattrs(name string, link bool) map[string][]byte {
+	full, err := f.join("getxattr", name)
+	if err != nil {
+		return nil
+	}
+	attrs := make(map[string][]byte)
+	for _, attr := range f.xattrs {
+		if value, err := getxattr(full, attr, link); err == nil {
+			attrs[attr] = value
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd || netbsd)
+
+package osfs
+
+import "errors"
+
+// getxattr returns the value of the extended attribute attr of the file at
+// path, which cannot be read on this system.
+func getxattr(path, attr string, link bool) ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
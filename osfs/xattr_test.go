@@ -0,0 +1,77 @@
+//go:build linux || darwin || freebsd || netbsd
+
+package osfs
+
+import (
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestWithXattrs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "sub/b.txt", "sub/c.txt"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	set := func(name, attr, value string) {
+		t.Helper()
+		err := unix.Setxattr(filepath.Join(dir, filepath.FromSlash(name)), attr, []byte(value), 0)
+		if err == unix.ENOTSUP || err == unix.EPERM {
+			t.Skipf("extended attributes not supported: %v", err)
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	set(".", "user.checksum", "root")
+	set("a.txt", "user.checksum", "abc")
+	set("a.txt", "user.other", "x")
+	set("sub/b.txt", "user.origin", "https://example.com")
+	set("sub/c.txt", "user.unrequested", "y")
+
+	fsys := New(dir, WithXattrs("user.checksum", "user.origin"))
+	got := make(map[string]map[string][]byte)
+	err := fsys.WalkDirXattrs(".", func(path string, d fs.DirEntry, xattrs map[string][]byte, err error) error {
+		if err != nil {
+			return err
+		}
+		got[path] = xattrs
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]map[string][]byte{
+		".":         {"user.checksum": []byte("root")},
+		"a.txt":     {"user.checksum": []byte("abc")},
+		"sub":       {},
+		"sub/b.txt": {"user.origin": []byte("https://example.com")},
+		"sub/c.txt": {},
+	}
+	if !maps.EqualFunc(got, expected, func(a, b map[string][]byte) bool {
+		return maps.EqualFunc(a, b, func(x, y []byte) bool { return string(x) == string(y) })
+	}) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	// Without the option, no attribute is read
+	dirs, err := New(dir).ReadDir(".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, d := range dirs {
+		if attrs := Xattrs(d); attrs != nil {
+			t.Errorf("%s: expected no attributes, got %q", d.Name(), attrs)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+//go:build linux || darwin || freebsd || netbsd
+
+package osfs
+
+import "golang.org/x/sys/unix"
+
+// getxattr returns the value of the extended attribute attr of the file at
+// path, or of the link itself if link is true.
+func getxattr(path, attr string, link bool) ([]byte, error) {
+	get := unix.Getxattr
+	if link {
+		get = unix.Lgetxattr
+	}
+	for {
+		n, err := get(path, attr, nil)
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, n)
+		n, err = get(path, attr, buf)
+		if err == unix.ERANGE {
+			continue // The value grew since its size was read
+		}
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	}
+}
@@ -0,0 +1,67 @@
+// Package otelbfwalk traces bfwalk walks with OpenTelemetry.
+//
+// It is a separate module, so that programs using bfwalk do not depend on
+// OpenTelemetry unless they import it.
+package otelbfwalk
+
+import (
+	"context"
+	"time"
+
+	"github.com/eriicafes/bfwalk"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanName is the name of the span created for each walk.
+const SpanName = "bfwalk.Walk"
+
+// WithTracer makes a Walker trace each walk with tracer, as a span named
+// [SpanName] started with opts, as a child of the span of the context of the
+// walk given to [bfwalk.Walker.WalkDirContext] or [bfwalk.Walker.Run], if
+// any. The callback of WalkDirContext is passed a context holding the
+// span, so that spans started by the callback are its children. The
+// callback of Run takes no context, so work it does cannot be parented to
+// the span; use WalkDirContext to trace it.
+//
+// The span records an event named "readdir" for each directory read, with
+// the path of the directory, the number of entries read, the read duration
+// and any error, and ends with the walk statistics as attributes. If the
+// walk fails, its error is recorded and the span status is set to error.
+// Each walk has a span of its own, so walks with Walker.Run may be traced
+// while they run at once.
+func WithTracer(tracer trace.Tracer, opts ...trace.SpanStartOption) bfwalk.Option {
+	return bfwalk.WithWalkObserver(func(ctx context.Context) bfwalk.WalkObserver {
+		ctx, span := tracer.Start(ctx, SpanName, opts...)
+		return bfwalk.WalkObserver{
+			Context: ctx,
+			ReadDir: func(path string, elapsed time.Duration, entries int, err error) {
+				attrs := []attribute.KeyValue{
+					attribute.String("bfwalk.path", path),
+					attribute.Int("bfwalk.entries", entries),
+					attribute.Float64("bfwalk.duration_ms", float64(elapsed)/float64(time.Millisecond)),
+				}
+				if err != nil {
+					attrs = append(attrs, attribute.String("bfwalk.error", err.Error()))
+				}
+				span.AddEvent("readdir", trace.WithAttributes(attrs...))
+			},
+			End: func(s bfwalk.Stats, err error) {
+				span.SetAttributes(
+					attribute.Int64("bfwalk.visited", s.Visited),
+					attribute.Int64("bfwalk.dirs", s.Dirs),
+					attribute.Int64("bfwalk.files", s.Files),
+					attribute.Int64("bfwalk.errors", s.Errors),
+					attribute.Int64("bfwalk.readdirs", s.ReadDirs),
+					attribute.Int64("bfwalk.retries", s.Retries),
+				)
+				if err != nil {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+				}
+				span.End()
+			},
+		}
+	})
+}
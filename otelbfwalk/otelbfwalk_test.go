@@ -0,0 +1,155 @@
+package otelbfwalk
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/eriicafes/bfwalk"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithTracer(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":      {Data: []byte("")},
+		"root/dirA/file1.txt": {Data: []byte("")},
+	}
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	w := bfwalk.NewWalker(WithTracer(tracer))
+	err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	errStop := errors.New("stop")
+	err = w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		return errStop
+	})
+	if err != errStop {
+		t.Fatalf("expected error %v, got %v", errStop, err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != SpanName {
+		t.Errorf("expected span %s, got %s", SpanName, span.Name())
+	}
+	if !hasAttr(span.Attributes(), attribute.Int64("bfwalk.visited", 4)) {
+		t.Errorf("expected visited attribute, got %v", span.Attributes())
+	}
+	events := span.Events()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if !hasAttr(events[0].Attributes, attribute.String("bfwalk.path", "root")) ||
+		!hasAttr(events[0].Attributes, attribute.Int("bfwalk.entries", 2)) {
+		t.Errorf("unexpected event attributes %v", events[0].Attributes)
+	}
+	if status := spans[1].Status(); status.Code != codes.Error || status.Description != "stop" {
+		t.Errorf("expected error status, got %v", status)
+	}
+}
+
+func TestWithTracerRun(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":      {Data: []byte("")},
+		"root/dirA/file1.txt": {Data: []byte("")},
+	}
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	w := bfwalk.NewWalker(WithTracer(tracer))
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := w.Run(context.Background(), memFS, "root", func(path string, d fs.DirEntry, err error) error {
+				return err
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("expected 4 spans, got %d", len(spans))
+	}
+	for _, span := range spans {
+		if !hasAttr(span.Attributes(), attribute.Int64("bfwalk.visited", 4)) {
+			t.Errorf("expected visited attribute, got %v", span.Attributes())
+		}
+		if n := len(span.Events()); n != 2 {
+			t.Errorf("expected 2 events, got %d", n)
+		}
+	}
+}
+
+func TestWithTracerContext(t *testing.T) {
+	memFS := fstest.MapFS{"root/file1.txt": {Data: []byte("")}}
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	w := bfwalk.NewWalker(WithTracer(tracer))
+	fn := func(path string, d fs.DirEntry, err error) error { return err }
+	if err := w.Run(ctx, memFS, "root", fn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := w.WalkDirContext(ctx, memFS, "root", func(ctx context.Context, path string, d fs.DirEntry, err error) error {
+		if path == "root/file1.txt" {
+			_, child := tracer.Start(ctx, "child")
+			child.End()
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.WalkDir(memFS, "root", fn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 5 {
+		t.Fatalf("expected 5 spans, got %d", len(spans))
+	}
+	walks := []sdktrace.ReadOnlySpan{spans[0], spans[2]}
+	for i, span := range walks {
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("walk %d: expected parent %v, got %v", i, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		}
+	}
+	// Spans of the WalkDirContext callback are children of the walk span
+	if child := spans[1]; child.Name() != "child" || child.Parent().SpanID() != spans[2].SpanContext().SpanID() {
+		t.Errorf("expected child of walk span %v, got %s with parent %v", spans[2].SpanContext().SpanID(), child.Name(), child.Parent().SpanID())
+	}
+	if spans[3].Parent().IsValid() {
+		t.Errorf("expected walk without context to start a root span, got parent %v", spans[3].Parent().SpanID())
+	}
+}
+
+func hasAttr(attrs []attribute.KeyValue, kv attribute.KeyValue) bool {
+	for _, a := range attrs {
+		if a == kv {
+			return true
+		}
+	}
+	return false
+}
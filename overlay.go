@@ -0,0 +1,135 @@
+package bfwalk
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// Overlay returns a file system that stacks layers on top of each other,
+// with entries in earlier layers shadowing entries at the same path in later
+// layers.
+//
+// Directories present in several layers are merged: reading one lists each
+// name once, described by the earliest layer that has it. A file shadows a
+// directory at the same path in later layers, and a directory shadows a file.
+// Shadowing is resolved per directory as it is read, so opening a path
+// directly does not check whether one of its parents is shadowed.
+func Overlay(layers ...fs.FS) fs.FS {
+	return overlayFS(layers)
+}
+
+// OverlayWalk walks the union of layers breadth-first from its root, with
+// entries in earlier layers shadowing later ones, calling fn for each path
+// exactly once. See [Overlay] for how the layers are combined.
+func OverlayWalk(fn fs.WalkDirFunc, layers ...fs.FS) error {
+	return WalkDir(Overlay(layers...), ".", fn)
+}
+
+type overlayFS []fs.FS
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	for i, layer := range o {
+		f, err := layer.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if !info.IsDir() {
+			return f, nil
+		}
+		return &overlayDir{File: f, fsys: o[i:], name: name}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (o overlayFS) Stat(name string) (fs.FileInfo, error) {
+	for _, layer := range o {
+		info, err := fs.Stat(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return info, err
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	var entries []fs.DirEntry
+	seen := make(map[string]bool)
+	found := false
+	for _, layer := range o {
+		info, err := fs.Stat(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if !found {
+				return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+			}
+			break // Shadowed by the directory above
+		}
+		found = true
+		list, err := fs.ReadDir(layer, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range list {
+			if !seen[d.Name()] {
+				seen[d.Name()] = true
+				entries = append(entries, d)
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
+
+// overlayDir is a directory opened from an [overlayFS], which lists the
+// merged entries of all layers.
+type overlayDir struct {
+	fs.File
+	fsys    overlayFS
+	name    string
+	entries []fs.DirEntry
+	read    bool
+}
+
+func (d *overlayDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.read = entries, true
+	}
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
@@ -0,0 +1,75 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestOverlayWalk(t *testing.T) {
+	user := fstest.MapFS{
+		"layout.html":       {Data: []byte("user")},
+		"pages/index.html":  {Data: []byte("user")},
+		"partials":          {Data: []byte("file shadows dir")},
+		"static/custom.css": {Data: []byte("user")},
+	}
+	defaults := fstest.MapFS{
+		"layout.html":        {Data: []byte("default")},
+		"pages/about.html":   {Data: []byte("default")},
+		"pages/index.html":   {Data: []byte("default")},
+		"partials/nav.html":  {Data: []byte("default")},
+		"static/custom.css":  {Data: []byte("default")},
+		"static/default.css": {Data: []byte("default")},
+	}
+
+	var visited []string
+	err := OverlayWalk(func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	}, user, defaults)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		".",
+		"layout.html",
+		"pages",
+		"partials",
+		"static",
+		"pages/about.html",
+		"pages/index.html",
+		"static/custom.css",
+		"static/default.css",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+
+	fsys := Overlay(user, defaults)
+	for name, data := range map[string]string{
+		"layout.html":        "user",
+		"pages/index.html":   "user",
+		"pages/about.html":   "default",
+		"static/default.css": "default",
+	} {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil || string(b) != data {
+			t.Errorf("%s: expected %q, got %q (%v)", name, data, b, err)
+		}
+	}
+}
+
+func TestOverlayFS(t *testing.T) {
+	fsys := Overlay(
+		fstest.MapFS{"a/one.txt": {Data: []byte("1")}},
+		fstest.MapFS{"a/two.txt": {Data: []byte("2")}, "b/three.txt": {Data: []byte("3")}},
+	)
+	if err := fstest.TestFS(fsys, "a/one.txt", "a/two.txt", "b/three.txt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package bfwalk
+
+import "io/fs"
+
+// ReadDirPager is implemented by file systems that list directories in
+// pages, such as those backed by cloud storage APIs.
+//
+// ReadDirPage reads at most n entries of the directory name, starting at the
+// page identified by token, and returns them with the token of the next
+// page. The first page has an empty token, and an empty next token means
+// there are no more pages.
+//
+// When a file system implements ReadDirPager, Walkers visit the entries of
+// each page before reading the next, in the order the pages list them, and
+// every page read counts as a directory read. With [WithOrder], all pages of
+// a directory are read before its entries are sorted and visited.
+type ReadDirPager interface {
+	fs.FS
+	ReadDirPage(name, token string, n int) (entries []fs.DirEntry, next string, err error)
+}
+
+// DefaultPageSize is the number of entries Walkers request per page from a
+// [ReadDirPager], unless set with [WithPageSize].
+const DefaultPageSize = 1000
+
+// WithPageSize sets the number of entries the Walker requests per page from
+// file systems that implement [ReadDirPager]. A size of zero or less uses
+// [DefaultPageSize].
+func WithPageSize(n int) Option {
+	if n <= 0 {
+		n = DefaultPageSize
+	}
+	return func(w *Walker) {
+		w.pageSize = n
+	}
+}
+
+// readAllPages reads every page of the directory name.
+func readAllPages(pager ReadDirPager, name string, n int) ([]fs.DirEntry, string, error) {
+	var dirs []fs.DirEntry
+	token := ""
+	for {
+		page, next, err := pager.ReadDirPage(name, token, n)
+		dirs = append(dirs, page...)
+		if err != nil || next == "" {
+			return dirs, "", err
+		}
+		token = next
+	}
+}
@@ -0,0 +1,110 @@
+package bfwalk
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadDirPager(t *testing.T) {
+	var events []string
+	fsys := &pagerFS{
+		MapFS: fstest.MapFS{
+			"root/a.txt":      {Data: []byte("")},
+			"root/b.txt":      {Data: []byte("")},
+			"root/c.txt":      {Data: []byte("")},
+			"root/dirA/a.txt": {Data: []byte("")},
+			"root/e.txt":      {Data: []byte("")},
+		},
+		events: &events,
+	}
+
+	w := NewWalker(WithPageSize(2))
+	err := w.WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		events = append(events, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"root",
+		"page root 0",
+		"root/a.txt",
+		"root/b.txt",
+		"page root 2",
+		"root/c.txt",
+		"root/dirA",
+		"page root 4",
+		"root/e.txt",
+		"page root/dirA 0",
+		"root/dirA/a.txt",
+	}
+	if !slices.Equal(events, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, events)
+	}
+	if s := w.Stats(); s.ReadDirs != 4 {
+		t.Errorf("expected 4 page reads, got %d", s.ReadDirs)
+	}
+
+	// sorting reads all pages first
+	events = nil
+	err = NewWalker(WithPageSize(2), WithOrder(Descending(OrderByName))).WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		events = append(events, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []string{
+		"root",
+		"page root 0",
+		"page root 2",
+		"page root 4",
+		"root/e.txt",
+		"root/dirA",
+		"root/c.txt",
+		"root/b.txt",
+		"root/a.txt",
+		"page root/dirA 0",
+		"root/dirA/a.txt",
+	}
+	if !slices.Equal(events, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, events)
+	}
+}
+
+// pagerFS is a file system that lists directories in pages, recording each
+// page read in events.
+type pagerFS struct {
+	fstest.MapFS
+	events *[]string
+}
+
+func (f *pagerFS) ReadDirPage(name, token string, n int) ([]fs.DirEntry, string, error) {
+	start := 0
+	if token != "" {
+		var err error
+		if start, err = strconv.Atoi(token); err != nil {
+			return nil, "", err
+		}
+	}
+	*f.events = append(*f.events, fmt.Sprintf("page %s %d", name, start))
+	dirs, err := f.MapFS.ReadDir(name)
+	if err != nil {
+		return nil, "", err
+	}
+	end := min(start+n, len(dirs))
+	next := ""
+	if end < len(dirs) {
+		next = strconv.Itoa(end)
+	}
+	return dirs[start:end], next, nil
+}
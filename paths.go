@@ -0,0 +1,33 @@
+package bfwalk
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+)
+
+// WritePaths walks the file tree rooted at root and writes the path of each
+// visited entry to w, in breadth-first order, each followed by sep.
+//
+// With a sep of '\n' the output is one path per line; with '\x00' it can be
+// passed safely to consumers such as xargs -0, since paths may contain
+// newlines but never NUL bytes. Paths are written as they are visited
+// through a buffer, so output for a walk that fails part way is truncated
+// rather than discarded. The first error encountered stops the walk and is
+// returned.
+func WritePaths(w io.Writer, fsys fs.FS, root string, sep byte) error {
+	bw := bufio.NewWriter(w)
+	err := WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if _, err := bw.WriteString(path); err != nil {
+			return err
+		}
+		return bw.WriteByte(sep)
+	})
+	if ferr := bw.Flush(); err == nil {
+		err = ferr
+	}
+	return err
+}
@@ -0,0 +1,37 @@
+package bfwalk
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWritePaths(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":      {Data: []byte("")},
+		"root/dirA/new\nline": {Data: []byte("")},
+	}
+
+	var sb strings.Builder
+	if err := WritePaths(&sb, memFS, "root", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "root\x00root/dirA\x00root/file1.txt\x00root/dirA/new\nline\x00"
+	if sb.String() != expected {
+		t.Errorf("expected:\n  %q\ngot\n: %q", expected, sb.String())
+	}
+
+	// output is flushed before a failed walk returns
+	errRead := errors.New("read failed")
+	fsys := errFS{MapFS: memFS, errs: map[string]error{"root/dirA": errRead}}
+	sb.Reset()
+	if err := WritePaths(&sb, fsys, "root", '\n'); err != errRead {
+		t.Errorf("expected error %v, got %v", errRead, err)
+	}
+	expected = "root\nroot/dirA\nroot/file1.txt\n"
+	if sb.String() != expected {
+		t.Errorf("expected:\n  %q\ngot\n: %q", expected, sb.String())
+	}
+}
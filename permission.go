@@ -0,0 +1,30 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+)
+
+// WithSkipPermissionErrors makes the Walker leave out the entries of
+// directories it is denied permission to read, instead of passing the
+// [fs.ErrPermission] error to the walk callback. The directories themselves
+// are still visited, and are counted in [Stats].Denied.
+func WithSkipPermissionErrors() Option {
+	return func(w *Walker) {
+		w.skipDenied = true
+	}
+}
+
+// denied reports whether err, the error reading the directory dir, is left
+// out of the walk because permission to read dir was denied.
+func (w *Walker) denied(dir namedEntry, err error) bool {
+	if !w.skipDenied || !errors.Is(err, fs.ErrPermission) {
+		return false
+	}
+	w.stats.denied.Add(1)
+	if w.debugLog {
+		w.debug("skip unreadable directory", dir, slog.Any("error", err))
+	}
+	return true
+}
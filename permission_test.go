@@ -0,0 +1,58 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithSkipPermissionErrors(t *testing.T) {
+	errBroken := errors.New("broken")
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/private/secret.txt":  {},
+			"root/public/file.txt":     {},
+			"root/public/locked/a.txt": {},
+			"root/broken/b.txt":        {},
+		},
+		errs: map[string]error{
+			"root/private":       &fs.PathError{Op: "readdir", Path: "root/private", Err: fs.ErrPermission},
+			"root/public/locked": fs.ErrPermission,
+			"root/broken":        errBroken,
+		},
+	}
+	expected := []string{
+		"root", "root/broken", "root/private", "root/public",
+		"error root/broken", "root/public/file.txt", "root/public/locked",
+	}
+
+	walks := map[string]func(w *Walker, fn fs.WalkDirFunc) error{
+		"WalkDir": func(w *Walker, fn fs.WalkDirFunc) error { return w.WalkDir(fsys, "root", fn) },
+		"IDWalk":  func(w *Walker, fn fs.WalkDirFunc) error { return w.IDWalk(fsys, "root", fn) },
+	}
+	for name, walk := range walks {
+		w := NewWalker(WithSkipPermissionErrors())
+		var visited []string
+		err := walk(w, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if !errors.Is(err, errBroken) {
+					t.Errorf("%s: unexpected error for %s: %v", name, path, err)
+				}
+				path = "error " + path
+			}
+			visited = append(visited, path)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !slices.Equal(visited, expected) {
+			t.Errorf("%s: expected:\n  %v\ngot\n: %v", name, expected, visited)
+		}
+		if s := w.Stats(); s.Denied != 2 {
+			t.Errorf("%s: expected 2 denied directories, got %d", name, s.Denied)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"iter"
+)
+
+// A Pipeline describes a walk of a file tree and the steps that select the
+// entries it yields. Pipelines are built with [From] and the chainable
+// methods of Pipeline, and run by [Pipeline.Seq], [Pipeline.Each] or
+// [Pipeline.Collect]:
+//
+//	err := bfwalk.From(fsys, ".").
+//		Filter(func(e bfwalk.Entry) bool { return !e.DirEntry.IsDir() }).
+//		MaxDepth(2).
+//		SortBy(bfwalk.OrderBySize).
+//		Each(func(e bfwalk.Entry) error {
+//			fmt.Println(e.Path)
+//			return nil
+//		})
+//
+// Each method returns a new Pipeline, leaving the one it is called on
+// unchanged, so a Pipeline may be extended in several ways and run any
+// number of times.
+type Pipeline struct {
+	fsys     fs.FS
+	root     string
+	opts     []Option
+	filters  []func(Entry) bool
+	maxDepth int // deepest entries yielded, or -1
+}
+
+// From returns a Pipeline that walks the file tree rooted at root
+// breadth-first with a [Walker] configured with opts, yielding every file
+// and directory in the tree.
+func From(fsys fs.FS, root string, opts ...Option) *Pipeline {
+	return &Pipeline{fsys: fsys, root: root, opts: opts, maxDepth: -1}
+}
+
+// clone returns a copy of p that can be changed without changing p.
+func (p *Pipeline) clone() *Pipeline {
+	q := *p
+	q.opts = append([]Option(nil), p.opts...)
+	q.filters = append([]func(Entry) bool(nil), p.filters...)
+	return &q
+}
+
+// With returns a Pipeline whose Walker is also configured with opts.
+func (p *Pipeline) With(opts ...Option) *Pipeline {
+	q := p.clone()
+	q.opts = append(q.opts, opts...)
+	return q
+}
+
+// Filter returns a Pipeline that only yields the entries for which keep
+// returns true. Filtered directories are still descended into. Entries
+// reporting errors are not passed to keep and are always yielded.
+func (p *Pipeline) Filter(keep func(Entry) bool) *Pipeline {
+	q := p.clone()
+	q.filters = append(q.filters, keep)
+	return q
+}
+
+// MaxDepth returns a Pipeline that does not descend into directories n
+// levels below the root, so that only entries up to n levels deep are
+// yielded.
+func (p *Pipeline) MaxDepth(n int) *Pipeline {
+	q := p.clone()
+	q.maxDepth = max(n, 0)
+	return q
+}
+
+// SortBy returns a Pipeline that visits the entries of each directory in
+// the order given by o, as with [WithOrder]. Entries are still yielded
+// breadth-first.
+func (p *Pipeline) SortBy(o Order) *Pipeline {
+	return p.With(WithOrder(o))
+}
+
+// Seq returns an iterator over the entries selected by p, in the order they
+// are visited. Errors are yielded as by [Walker.Entries].
+func (p *Pipeline) Seq() iter.Seq[Entry] {
+	return func(yield func(Entry) bool) {
+		w := NewWalker(p.opts...)
+		if p.maxDepth >= 0 {
+			w.descend = append(w.descend, func(string, fs.DirEntry) bool {
+				return w.current.depth < p.maxDepth
+			})
+		}
+		for e := range w.Entries(p.fsys, p.root) {
+			if e.Err == nil && !p.keep(e) {
+				continue
+			}
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// keep reports whether every filter of p keeps e.
+func (p *Pipeline) keep(e Entry) bool {
+	for _, keep := range p.filters {
+		if !keep(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Each calls fn for each entry selected by p, in the order they are
+// visited. The first error encountered, whether returned by fn or reported
+// by the walk, stops the walk and is returned.
+func (p *Pipeline) Each(fn func(Entry) error) error {
+	for e := range p.Seq() {
+		if e.Err != nil {
+			return e.Err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Collect returns the entries selected by p, in the order they are visited.
+// The first error encountered stops the walk and is returned, together with
+// the entries selected before it.
+func (p *Pipeline) Collect() ([]Entry, error) {
+	var entries []Entry
+	err := p.Each(func(e Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	return entries, err
+}
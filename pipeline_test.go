@@ -0,0 +1,116 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func entryPaths(entries []Entry) []string {
+	paths := make([]string, len(entries))
+	for i, e := range entries {
+		paths[i] = e.Path
+	}
+	return paths
+}
+
+func TestPipeline(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/big.txt":            {Data: []byte("0123456789")},
+		"root/small.txt":          {Data: []byte("0")},
+		"root/dirA/mid.txt":       {Data: []byte("01234")},
+		"root/dirA/sub/deep.txt":  {Data: []byte("0")},
+		"root/dirB/image.png":     {Data: []byte("0")},
+		"root/dirB/sub/other.txt": {Data: []byte("0")},
+	}
+	isTxt := func(e Entry) bool { return path.Ext(e.Path) == ".txt" }
+
+	base := From(memFS, "root")
+	txt := base.Filter(isTxt)
+
+	entries, err := txt.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"root/big.txt",
+		"root/small.txt",
+		"root/dirA/mid.txt",
+		"root/dirA/sub/deep.txt",
+		"root/dirB/sub/other.txt",
+	}
+	if got := entryPaths(entries); !slices.Equal(got, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, got)
+	}
+
+	entries, err = txt.MaxDepth(2).SortBy(Descending(OrderBySize)).Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []string{"root/big.txt", "root/small.txt", "root/dirA/mid.txt"}
+	if got := entryPaths(entries); !slices.Equal(got, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, got)
+	}
+
+	entries, err = base.MaxDepth(0).Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := entryPaths(entries); !slices.Equal(got, []string{"root"}) {
+		t.Errorf("expected only the root, got %v", got)
+	}
+
+	// Directories filtered out of the walk still stop at the depth limit.
+	filtered := fstest.MapFS{
+		"root/a/y.txt": {Data: []byte("0")},
+		"root/b.txt":   {Data: []byte("0")},
+	}
+	entries, err = From(filtered, "root", WithFilter(Type('f'))).MaxDepth(1).Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []string{"root", "root/b.txt"}
+	if got := entryPaths(entries); !slices.Equal(got, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, got)
+	}
+
+	// Extending a pipeline leaves it unchanged.
+	n := 0
+	for range base.Seq() {
+		n++
+	}
+	if n != 11 {
+		t.Errorf("expected 11 entries from the base pipeline, got %d", n)
+	}
+}
+
+func TestPipelineErrors(t *testing.T) {
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/dirA/file1.txt": {},
+			"root/dirB/file1.txt": {},
+		},
+		errs: map[string]error{"root/dirB": fs.ErrPermission},
+	}
+
+	entries, err := From(fsys, "root").Filter(func(e Entry) bool { return !e.DirEntry.IsDir() }).Collect()
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Fatalf("expected permission error, got %v", err)
+	}
+	if got := entryPaths(entries); !slices.Equal(got, []string{"root/dirA/file1.txt"}) {
+		t.Errorf("unexpected entries %v", got)
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	err = From(fsys, "root").Each(func(e Entry) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Errorf("expected Each to stop with %v after 1 call, got %v after %d", errStop, err, calls)
+	}
+}
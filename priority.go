@@ -0,0 +1,51 @@
+package bfwalk
+
+import "io/fs"
+
+// WithPriority makes the Walker read queued directories best-first instead
+// of in the order they were found: of the directories waiting to be read,
+// the one with the highest score is read next, regardless of its depth.
+// Directories with equal scores are read breadth-first.
+//
+// score is called once for each directory when it is queued, with the path
+// as reported to the walk callback. Since directories are no longer read
+// level by level, the levels reported by [Walker.Levels] and
+// [WithLevelHooks] may be entered more than once.
+func WithPriority(score func(path string, d fs.DirEntry) int) Option {
+	return func(w *Walker) {
+		w.priority = score
+	}
+}
+
+// A prioEntry is a directory queued by a Walker created with
+// [WithPriority].
+type prioEntry struct {
+	namedEntry
+	score int
+	seq   int // order the directory was queued in
+}
+
+func (e prioEntry) less(o prioEntry) bool {
+	if e.score != o.score {
+		return e.score > o.score
+	}
+	return e.seq < o.seq
+}
+
+// prioQueue is a max-heap of queued directories.
+type prioQueue struct {
+	entries []prioEntry
+	seq     int
+}
+
+func (q *prioQueue) Len() int           { return len(q.entries) }
+func (q *prioQueue) Less(i, j int) bool { return q.entries[i].less(q.entries[j]) }
+func (q *prioQueue) Swap(i, j int)      { q.entries[i], q.entries[j] = q.entries[j], q.entries[i] }
+func (q *prioQueue) Push(x any)         { q.entries = append(q.entries, x.(prioEntry)) }
+
+func (q *prioQueue) Pop() any {
+	n := len(q.entries) - 1
+	e := q.entries[n]
+	q.entries = q.entries[:n]
+	return e
+}
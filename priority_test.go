@@ -0,0 +1,50 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithPriority(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/docs/file1.txt":      {Data: []byte("")},
+		"root/src/pkg/file1.txt":   {Data: []byte("")},
+		"root/vendor/dep/file1.go": {Data: []byte("")},
+	}
+
+	var visited []string
+	w := NewWalker(WithPriority(func(path string, d fs.DirEntry) int {
+		if strings.HasPrefix(path, "root/vendor") {
+			return -1
+		}
+		return 0
+	}))
+	err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"root",
+		"root/docs",
+		"root/src",
+		"root/vendor",
+		"root/docs/file1.txt",
+		"root/src/pkg",
+		"root/src/pkg/file1.txt",
+		"root/vendor/dep",
+		"root/vendor/dep/file1.go",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
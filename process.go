@@ -0,0 +1,146 @@
+package bfwalk
+
+import (
+	"iter"
+	"sync"
+)
+
+// Process returns an iterator over the results of calling fn for each entry
+// selected by p on a pool of n worker goroutines, so that expensive work on
+// each file, such as hashing, resizing or uploading it, runs alongside the
+// walk:
+//
+//	sizes := bfwalk.Process(bfwalk.From(fsys, "."), 8, func(e bfwalk.Entry) (int64, error) {
+//		info, err := e.DirEntry.Info()
+//		if err != nil {
+//			return 0, err
+//		}
+//		return info.Size(), nil
+//	})
+//	for size, err := range sizes {
+//		...
+//	}
+//
+// Results are yielded in the order the entries are visited, regardless of
+// which worker finishes first, and at most n entries are processed ahead of
+// the iteration. Errors reported by the walk are yielded in place of a
+// result, without calling fn, and errors returned by fn are yielded with its
+// result; in both cases the walk continues. Stopping the iteration stops the
+// walk and waits for the workers to exit. An n of one or less uses a single
+// worker.
+func Process[R any](p *Pipeline, n int, fn func(Entry) (R, error)) iter.Seq2[R, error] {
+	return Then(entryErrors(p.Seq()), n, fn)
+}
+
+// Then returns an iterator over the results of calling fn for each value
+// yielded by in on a pool of n worker goroutines, as [Process] does for the
+// entries of a walk, so that further stages can be attached to the results
+// of Process or Then:
+//
+//	uploads := bfwalk.Then(bfwalk.Process(p, 8, thumbnail), 2, upload)
+//
+// Values yielded by in with an error are passed through in order without
+// calling fn.
+func Then[T, R any](in iter.Seq2[T, error], n int, fn func(T) (R, error)) iter.Seq2[R, error] {
+	return func(yield func(R, error) bool) {
+		values := func(yield func(stageResult[T]) bool) {
+			for v, err := range in {
+				if !yield(stageResult[T]{v, err}) {
+					return
+				}
+			}
+		}
+		results := process(values, n, func(v stageResult[T]) stageResult[R] {
+			if v.err != nil {
+				return stageResult[R]{err: v.err}
+			}
+			r, err := fn(v.value)
+			return stageResult[R]{r, err}
+		})
+		for r := range results {
+			if !yield(r.value, r.err) {
+				return
+			}
+		}
+	}
+}
+
+// A stageResult is a value passed between the stages of [Then], or the
+// error in its place.
+type stageResult[T any] struct {
+	value T
+	err   error
+}
+
+// entryErrors returns an iterator over entries, paired with the error each
+// reports.
+func entryErrors(entries iter.Seq[Entry]) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		for e := range entries {
+			if !yield(e, e.Err) {
+				return
+			}
+		}
+	}
+}
+
+type processJob[T, R any] struct {
+	value T
+	res   chan<- R
+}
+
+// process returns an iterator over the results of calling fn for each value
+// yielded by in on a pool of n worker goroutines, in the order of in. in is
+// iterated on a goroutine of its own, at most n values ahead of the
+// iteration. Stopping the iteration stops in and waits for the workers to
+// exit.
+func process[T, R any](in iter.Seq[T], n int, fn func(T) R) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		n := max(n, 1)
+		done := make(chan struct{})
+		jobs := make(chan processJob[T, R])
+		results := make(chan chan R, n)
+
+		var wg sync.WaitGroup
+		for range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for job := range jobs {
+					job.res <- fn(job.value)
+				}
+			}()
+		}
+
+		go func() {
+			defer close(results)
+			defer close(jobs)
+			for v := range in {
+				res := make(chan R, 1)
+				select {
+				case results <- res:
+				case <-done:
+					return
+				}
+				select {
+				case jobs <- processJob[T, R]{v, res}:
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		defer func() {
+			close(done)
+			for range results {
+				// Drain until in stops
+			}
+			wg.Wait()
+		}()
+		for res := range results {
+			if !yield(<-res) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestProcess(t *testing.T) {
+	fsys := generateFS("data", 10, 3)
+	p := From(fsys, "data").Filter(func(e Entry) bool { return !e.DirEntry.IsDir() })
+
+	expected, err := p.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, n := range []int{0, 1, 8} {
+		var got []string
+		results := Process(p, n, func(e Entry) (string, error) {
+			time.Sleep(time.Duration(len(e.Path)%3) * time.Millisecond)
+			return strings.ToUpper(e.Path), nil
+		})
+		for r, err := range results {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got = append(got, r)
+		}
+		upper := entryPaths(expected)
+		for i := range upper {
+			upper[i] = strings.ToUpper(upper[i])
+		}
+		if !slices.Equal(got, upper) {
+			t.Errorf("%d workers: expected:\n  %v\ngot\n: %v", n, upper, got)
+		}
+	}
+}
+
+func TestProcessErrors(t *testing.T) {
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/dirA/file1.txt": {},
+			"root/dirB/file1.txt": {},
+		},
+		errs: map[string]error{"root/dirB": fs.ErrPermission},
+	}
+	errSkip := errors.New("skip")
+
+	var got []string
+	results := Process(From(fsys, "root"), 2, func(e Entry) (string, error) {
+		if e.Err != nil {
+			t.Errorf("fn called for %s with error %v", e.Path, e.Err)
+		}
+		if e.Path == "root/dirA" {
+			return "", errSkip
+		}
+		return e.Path, nil
+	})
+	results = Then(results, 1, func(name string) (string, error) {
+		return "<" + name + ">", nil
+	})
+	for r, err := range results {
+		switch {
+		case errors.Is(err, fs.ErrPermission):
+			got = append(got, "permission")
+		case err == errSkip:
+			got = append(got, "skip")
+		case err != nil:
+			t.Fatalf("unexpected error: %v", err)
+		default:
+			got = append(got, r)
+		}
+	}
+	expected := []string{"<root>", "skip", "<root/dirB>", "<root/dirA/file1.txt>", "permission"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, got)
+	}
+}
+
+func TestProcessStop(t *testing.T) {
+	fsys := generateFS("data", 10, 3)
+
+	n := 0
+	for _, err := range Process(From(fsys, "data"), 4, func(e Entry) (int, error) { return e.Depth, nil }) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		n++
+		if n == 5 {
+			break
+		}
+	}
+	if n != 5 {
+		t.Errorf("expected 5 results, got %d", n)
+	}
+}
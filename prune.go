@@ -0,0 +1,41 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+)
+
+// DefaultPruneNames are the names of the directories pruned by
+// [WithDefaultPrunes]: version control metadata, and dependency and build
+// output directories of common toolchains.
+var DefaultPruneNames = []string{
+	".git",
+	".hg",
+	".svn",
+	"node_modules",
+	"vendor",
+	"target",
+	"__pycache__",
+	".venv",
+}
+
+// WithPruneNames makes the Walker leave directories with any of the given
+// names out of the walk, at any depth: they are not passed to the walk
+// callback and are not descended into. Files with those names are still
+// visited, and the roots of the walk are always visited.
+func WithPruneNames(names ...string) Option {
+	names = slices.Clone(names)
+	return func(w *Walker) {
+		w.prune = append(w.prune, func(d fs.DirEntry) bool {
+			return d.IsDir() && slices.ContainsFunc(names, func(name string) bool {
+				return w.sameName(name, d.Name())
+			})
+		})
+	}
+}
+
+// WithDefaultPrunes makes the Walker prune the directories named in
+// [DefaultPruneNames], as with [WithPruneNames].
+func WithDefaultPrunes() Option {
+	return WithPruneNames(DefaultPruneNames...)
+}
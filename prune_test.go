@@ -0,0 +1,51 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithPruneNames(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":                 {Data: []byte("")},
+		"root/target":                    {Data: []byte("")},
+		"root/.git/config":               {Data: []byte("")},
+		"root/dirA/file1.txt":            {Data: []byte("")},
+		"root/dirA/node_modules/x/a.js":  {Data: []byte("")},
+		"root/dirA/sub/target/file1.txt": {Data: []byte("")},
+	}
+
+	tests := map[string][]Option{
+		"names":    {WithPruneNames("node_modules", ".git", "target")},
+		"defaults": {WithDefaultPrunes()},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			var visited []string
+			err := NewWalker(opts...).WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				visited = append(visited, path)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expected := []string{
+				"root",
+				"root/dirA",
+				"root/file1.txt",
+				"root/target",
+				"root/dirA/file1.txt",
+				"root/dirA/sub",
+			}
+			if !slices.Equal(visited, expected) {
+				t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+			}
+		})
+	}
+}
@@ -6,15 +6,18 @@ NEW_VERSION=$(grep -m 1 '"version"' package.json | awk -F '"' '{print $4}')
 
 echo "Publishing version v$NEW_VERSION"
 
-# Check if tag already exists
-if git rev-parse "v$NEW_VERSION" >/dev/null 2>&1; then
-  echo "Tag v$NEW_VERSION already exists, skipping tag creation"
-else
-  # Create tag
-  git tag -a v$NEW_VERSION -m "Release v$NEW_VERSION"
-  echo "✓ Created tag v$NEW_VERSION"
+# Tag the root module and the otelbfwalk module, which requires it
+for TAG in "v$NEW_VERSION" "otelbfwalk/v$NEW_VERSION"; do
+  # Check if tag already exists
+  if git rev-parse "$TAG" >/dev/null 2>&1; then
+    echo "Tag $TAG already exists, skipping tag creation"
+  else
+    # Create tag
+    git tag -a "$TAG" -m "Release $TAG"
+    echo "✓ Created tag $TAG"
 
-  # Push the tag to remote
-  git push origin v$NEW_VERSION
-  echo "✓ Pushed tag v$NEW_VERSION to remote"
-fi
+    # Push the tag to remote
+    git push origin "$TAG"
+    echo "✓ Pushed tag $TAG to remote"
+  fi
+done
@@ -0,0 +1,94 @@
+package bfwalk
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// WithQueueThresholds makes the Walker call fn when the number of
+// directories waiting to be read first grows to or past each of thresholds,
+// with the number then waiting. Thresholds passed by the same growth of the
+// queue are reported by a single call. The most directories waiting at once
+// is also reported by [Stats].MaxQueue.
+func WithQueueThresholds(fn func(queued int), thresholds ...int) Option {
+	return func(w *Walker) {
+		w.onQueue = append(w.onQueue, queueHook{slices.Sorted(slices.Values(thresholds)), fn})
+	}
+}
+
+type queueHook struct {
+	thresholds []int // sorted
+	fn         func(queued int)
+}
+
+// grown calls the hook if the queue growing from most to n directories
+// passes one of its thresholds.
+func (h queueHook) grown(most, n int64) {
+	i, _ := slices.BinarySearch(h.thresholds, int(most)+1)
+	if i < len(h.thresholds) && int64(h.thresholds[i]) <= n {
+		h.fn(int(n))
+	}
+}
+
+// enqueue adds dirs to the directories waiting to be read.
+func (w *Walker) enqueue(dirs ...namedEntry) {
+	if n, most := w.stats.queued.Add(int64(len(dirs))), w.stats.maxQueue.Load(); n > most {
+		w.stats.maxQueue.Store(n)
+		if w.metrics != nil {
+			w.metrics.QueueHighWater(int(n))
+		}
+		for _, h := range w.onQueue {
+			h.grown(most, n)
+		}
+	}
+	if w.priority == nil {
+		w.queue.pushBack(dirs...)
+		return
+	}
+	for _, dir := range dirs {
+		score := w.priority(w.report(dir.root, dir.name), dir.d)
+		heap.Push(&w.pq, prioEntry{dir, score, w.pq.seq})
+		w.pq.seq++
+	}
+}
+
+// queued returns the number of directories waiting to be read.
+func (w *Walker) queued() int {
+	return w.queue.len() + len(w.pq.entries)
+}
+
+// dequeue removes and returns the next directory to be read, reporting
+// false if there is none.
+func (w *Walker) dequeue() (namedEntry, bool) {
+	var dir namedEntry
+	switch {
+	case w.priority != nil && len(w.pq.entries) > 0:
+		dir = heap.Pop(&w.pq).(prioEntry).namedEntry
+	case w.priority == nil && w.queue.len() > 0:
+		dir, _ = w.queue.popFront()
+	default:
+		return namedEntry{}, false
+	}
+	w.stats.queued.Add(-1)
+	return dir, true
+}
+
+// pending returns the directories waiting to be read, in the order they
+// would be read.
+func (w *Walker) pending() []namedEntry {
+	if w.priority == nil {
+		return w.queue.slice()
+	}
+	entries := slices.Clone(w.pq.entries)
+	slices.SortFunc(entries, func(a, b prioEntry) int {
+		if a.less(b) {
+			return -1
+		}
+		return 1
+	})
+	dirs := make([]namedEntry, len(entries))
+	for i, e := range entries {
+		dirs[i] = e.namedEntry
+	}
+	return dirs
+}
@@ -0,0 +1,38 @@
+package bfwalk
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithQueueThresholds(t *testing.T) {
+	memFS := fstest.MapFS{}
+	for i := range 5 {
+		for j := range 3 {
+			memFS[fmt.Sprintf("root/dir%d/sub%d/file.txt", i, j)] = &fstest.MapFile{}
+		}
+	}
+
+	var calls []int
+	w := NewWalker(WithQueueThresholds(func(queued int) {
+		calls = append(calls, queued)
+	}, 100, 6, 2))
+	err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The root's 5 directories pass 2, and the first 3 subdirectories
+	// queued after them pass 6.
+	if expected := []int{5, 7}; !slices.Equal(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+	if s := w.Stats(); s.MaxQueue != 15 {
+		t.Errorf("expected max queue 15, got %d", s.MaxQueue)
+	}
+}
@@ -0,0 +1,51 @@
+package bfwalk
+
+import (
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the Walker to opsPerSecond file system operations per
+// second, so that walking a file system backed by a remote API stays within
+// its rate limits. Directory reads, stats of roots and, with [WithStat],
+// stats of entries each count as one operation.
+//
+// The limit is shared by all walks of Walkers created with the same Option,
+// including walks running concurrently and concurrent operations within a
+// walk. A limit of zero or less disables rate limiting.
+func WithRateLimit(opsPerSecond float64) Option {
+	var l *limiter
+	if opsPerSecond > 0 {
+		l = &limiter{interval: time.Duration(float64(time.Second) / opsPerSecond)}
+	}
+	return func(w *Walker) {
+		w.limit = l
+	}
+}
+
+// limiter spaces operations evenly, interval apart.
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // earliest time of the next operation
+}
+
+// wait blocks until the next operation may start.
+func (l *limiter) wait() {
+	l.mu.Lock()
+	now := time.Now()
+	t := l.next
+	if t.Before(now) {
+		t = now
+	}
+	l.next = t.Add(l.interval)
+	l.mu.Unlock()
+	time.Sleep(t.Sub(now))
+}
+
+// throttle waits for the rate limit of the Walker, if any.
+func (w *Walker) throttle() {
+	if w.limit != nil {
+		w.limit.wait()
+	}
+}
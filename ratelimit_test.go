@@ -0,0 +1,38 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/dirA/file1.txt": {Data: []byte("")},
+		"root/dirB/file1.txt": {Data: []byte("")},
+		"root/dirC/file1.txt": {Data: []byte("")},
+	}
+
+	// 1 root stat and 4 directory reads per walk, over two concurrent walks
+	limit := WithRateLimit(200)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := NewWalker(limit).WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+				return err
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Errorf("expected 10 operations to take at least 45ms, took %v", elapsed)
+	}
+}
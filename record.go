@@ -0,0 +1,138 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// A Trace is a recording of the calls made to a walk callback, made with
+// [Walker.Record]. It can be serialized as JSON and fed back to a callback
+// with [Replay], to test code that depends on the traversal or to
+// reproduce a walk without the file system it ran on.
+type Trace struct {
+	Calls []TraceCall `json:"calls"`
+}
+
+// A TraceCall is a single recorded call of a walk callback.
+type TraceCall struct {
+	Path   string      `json:"path"`             // path passed to the callback
+	Parent string      `json:"parent,omitempty"` // path of the parent directory
+	Depth  int         `json:"depth"`            // depth of the entry below its root
+	Type   fs.FileMode `json:"type"`             // type bits of the entry
+	Nil    bool        `json:"nil,omitempty"`    // whether the entry was nil
+	Err    string      `json:"err,omitempty"`    // error passed to the callback
+	Result string      `json:"result,omitempty"` // error returned by the callback
+}
+
+// Trace results recorded for the special errors returned by callbacks.
+const (
+	traceSkipDir = "SkipDir"
+	traceSkipAll = "SkipAll"
+)
+
+// Record returns a walk callback that calls fn and appends the call to t,
+// for use with a walk of w. Errors are recorded by their message, except
+// [fs.SkipDir] and [fs.SkipAll] returned by fn.
+func (w *Walker) Record(t *Trace, fn fs.WalkDirFunc) fs.WalkDirFunc {
+	return func(path string, d fs.DirEntry, err error) error {
+		e := w.entry(path, d, err)
+		call := TraceCall{Path: path, Parent: e.Parent, Depth: e.Depth, Nil: d == nil}
+		if d != nil {
+			call.Type = d.Type()
+		}
+		if err != nil {
+			call.Err = err.Error()
+		}
+		err = fn(path, d, err)
+		switch err {
+		case nil:
+		case fs.SkipDir:
+			call.Result = traceSkipDir
+		case fs.SkipAll:
+			call.Result = traceSkipAll
+		default:
+			call.Result = err.Error()
+		}
+		t.Calls = append(t.Calls, call)
+		return err
+	}
+}
+
+// Replay calls fn for each call recorded in t, in order, as a walk would,
+// without accessing any file system. Recorded errors are passed to fn as
+// errors with the same message.
+//
+// The results of fn are honored rather than the recorded ones: returning
+// [fs.SkipDir] skips the recorded calls for the contents of a directory, or
+// for the remaining entries of the parent directory of a file and the
+// contents of the directories visited before it in that parent, and any
+// other error stops the replay. Replay returns the error that stopped it,
+// or nil if fn returned [fs.SkipAll] or the trace was replayed in full.
+//
+// The entries passed to fn only record a name and type; their Info method
+// returns file info with a zero size and modification time.
+func Replay(t *Trace, fn fs.WalkDirFunc) error {
+	skipped := make(map[string]bool)
+	dirs := make(map[string][]string) // directories replayed in each parent
+	for _, call := range t.Calls {
+		if skipped[call.Parent] || (call.Err != "" && skipped[call.Path]) {
+			if call.Type.IsDir() {
+				skipped[call.Path] = true
+			}
+			continue
+		}
+		var d fs.DirEntry
+		if !call.Nil {
+			d = replayEntry{call}
+		}
+		var err error
+		if call.Err != "" {
+			err = errors.New(call.Err)
+		}
+		switch err := fn(call.Path, d, err); err {
+		case nil:
+			if call.Type.IsDir() && call.Err == "" {
+				dirs[call.Parent] = append(dirs[call.Parent], call.Path)
+			}
+		case fs.SkipDir:
+			if call.Type.IsDir() {
+				skipped[call.Path] = true
+			} else if call.Parent != "" {
+				skipped[call.Parent] = true
+				for _, dir := range dirs[call.Parent] {
+					skipped[dir] = true
+				}
+			}
+		case fs.SkipAll:
+			return nil
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// replayEntry is the [fs.DirEntry] of a call replayed by [Replay].
+type replayEntry struct {
+	call TraceCall
+}
+
+func (e replayEntry) Name() string               { return filepath.Base(e.call.Path) }
+func (e replayEntry) IsDir() bool                { return e.call.Type.IsDir() }
+func (e replayEntry) Type() fs.FileMode          { return e.call.Type }
+func (e replayEntry) Info() (fs.FileInfo, error) { return replayInfo{e}, nil }
+func (e replayEntry) String() string             { return fs.FormatDirEntry(e) }
+
+// replayInfo is the [fs.FileInfo] of a replayed entry.
+type replayInfo struct {
+	e replayEntry
+}
+
+func (i replayInfo) Name() string       { return i.e.Name() }
+func (i replayInfo) Size() int64        { return 0 }
+func (i replayInfo) Mode() fs.FileMode  { return i.e.Type() }
+func (i replayInfo) ModTime() time.Time { return time.Time{} }
+func (i replayInfo) IsDir() bool        { return i.e.IsDir() }
+func (i replayInfo) Sys() any           { return nil }
@@ -0,0 +1,126 @@
+package bfwalk
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRecordReplay(t *testing.T) {
+	errRead := errors.New("read failed")
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/file1.txt":          {Data: []byte("")},
+			"root/dirA/file1.txt":     {Data: []byte("")},
+			"root/dirB/afile1.txt":    {Data: []byte("")},
+			"root/dirB/sub/file1.txt": {Data: []byte("")},
+			"root/dirC/file1.txt":     {Data: []byte("")},
+		},
+		errs: map[string]error{"root/dirC": errRead},
+	}
+	record := func(visited *[]string, skip string) fs.WalkDirFunc {
+		return func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				*visited = append(*visited, "error:"+path)
+				return nil
+			}
+			*visited = append(*visited, path)
+			if path == skip {
+				return fs.SkipDir
+			}
+			return nil
+		}
+	}
+
+	var trace Trace
+	var walked []string
+	w := NewWalker()
+	if err := w.WalkDir(fsys, "root", w.Record(&trace, record(&walked, "root/dirB/afile1.txt"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded Trace
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// replaying with the same callback visits the same entries
+	var replayed []string
+	if err := Replay(&decoded, record(&replayed, "root/dirB/afile1.txt")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(replayed, walked) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", walked, replayed)
+	}
+
+	// replaying honors skipped directories
+	replayed = nil
+	if err := Replay(&decoded, record(&replayed, "root/dirC")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"root",
+		"root/dirA",
+		"root/dirB",
+		"root/dirC",
+		"root/file1.txt",
+		"root/dirA/file1.txt",
+		"root/dirB/afile1.txt",
+	}
+	if !slices.Equal(replayed, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, replayed)
+	}
+
+	call := decoded.Calls[len(decoded.Calls)-1]
+	expectedCall := TraceCall{Path: "root/dirC", Parent: "root", Depth: 1, Type: fs.ModeDir, Err: "read failed"}
+	if call != expectedCall {
+		t.Errorf("expected:\n  %+v\ngot\n: %+v", expectedCall, call)
+	}
+}
+
+func TestReplaySkipParent(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/dirA/file1.txt":     {Data: []byte("")},
+		"root/dirA/sub/file1.txt": {Data: []byte("")},
+		"root/dirB/file1.txt":     {Data: []byte("")},
+		"root/file1.txt":          {Data: []byte("")},
+		"root/zdir/file1.txt":     {Data: []byte("")},
+	}
+	var trace Trace
+	w := NewWalker()
+	err := w.WalkDir(fsys, "root", w.Record(&trace, func(path string, d fs.DirEntry, err error) error {
+		return err
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Replay must visit entries exactly like a walk with the same callback
+	for _, skip := range []string{"root/file1.txt", "root/dirA/file1.txt", "root/dirB"} {
+		record := func(visited *[]string) fs.WalkDirFunc {
+			return func(path string, d fs.DirEntry, err error) error {
+				*visited = append(*visited, path)
+				if path == skip {
+					return fs.SkipDir
+				}
+				return err
+			}
+		}
+		var expected, replayed []string
+		if err := WalkDir(fsys, "root", record(&expected)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := Replay(&trace, record(&replayed)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(replayed, expected) {
+			t.Errorf("skip %s: expected:\n  %v\ngot\n: %v", skip, expected, replayed)
+		}
+	}
+}
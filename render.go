@@ -0,0 +1,82 @@
+package bfwalk
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// A RenderOption configures [Render].
+type RenderOption func(*renderConfig)
+
+type renderConfig struct {
+	maxDepth int
+	dirsOnly bool
+}
+
+// RenderMaxDepth limits the listing to entries at most depth levels below
+// the root.
+func RenderMaxDepth(depth int) RenderOption {
+	return func(c *renderConfig) {
+		c.maxDepth = depth
+	}
+}
+
+// RenderDirsOnly limits the listing to directories.
+func RenderDirsOnly() RenderOption {
+	return func(c *renderConfig) {
+		c.dirsOnly = true
+	}
+}
+
+// Render writes a tree(1)-style listing of the file tree rooted at root to w,
+// followed by a summary of the number of directories and files listed.
+//
+// The tree is built with a breadth-first walk before it is written, so
+// nothing is written if the walk fails.
+func Render(w io.Writer, fsys fs.FS, root string, opts ...RenderOption) error {
+	c := renderConfig{maxDepth: -1}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	tree, err := buildTree(fsys, root, c.maxDepth, c.dirsOnly)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	var dirs, files int
+	fmt.Fprintln(bw, tree.Path)
+	var render func(n *Node, prefix string)
+	render = func(n *Node, prefix string) {
+		for i, c := range n.Children {
+			branch, indent := "├── ", "│   "
+			if i == len(n.Children)-1 {
+				branch, indent = "└── ", "    "
+			}
+			fmt.Fprintf(bw, "%s%s%s\n", prefix, branch, c.Name)
+			if c.Entry.IsDir() {
+				dirs++
+				render(c, prefix+indent)
+			} else {
+				files++
+			}
+		}
+	}
+	render(tree, "")
+
+	fmt.Fprintf(bw, "\n%d %s", dirs, plural(dirs, "directory", "directories"))
+	if !c.dirsOnly {
+		fmt.Fprintf(bw, ", %d %s", files, plural(files, "file", "files"))
+	}
+	fmt.Fprintln(bw)
+	return bw.Flush()
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
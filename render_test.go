@@ -0,0 +1,69 @@
+package bfwalk
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRender(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":          {Data: []byte("")},
+		"root/dirA/file1.txt":     {Data: []byte("")},
+		"root/dirB/file1.txt":     {Data: []byte("")},
+		"root/dirB/sub/file1.txt": {Data: []byte("")},
+	}
+
+	cases := []struct {
+		name     string
+		root     string
+		opts     []RenderOption
+		expected string
+	}{
+		{"Default", "root", nil, `root
+├── dirA
+│   └── file1.txt
+├── dirB
+│   ├── file1.txt
+│   └── sub
+│       └── file1.txt
+└── file1.txt
+
+3 directories, 4 files
+`},
+		{"MaxDepth", "root", []RenderOption{RenderMaxDepth(1)}, `root
+├── dirA
+├── dirB
+└── file1.txt
+
+2 directories, 1 file
+`},
+		{"DirsOnly", "root", []RenderOption{RenderDirsOnly()}, `root
+├── dirA
+└── dirB
+    └── sub
+
+3 directories
+`},
+		{"FileRoot", "root/file1.txt", nil, `root/file1.txt
+
+0 directories, 0 files
+`},
+		{"DirsOnlyFileRoot", "root/file1.txt", []RenderOption{RenderDirsOnly()}, `root/file1.txt
+
+0 directories
+`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := Render(&sb, memFS, c.root, c.opts...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sb.String() != c.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", c.expected, sb.String())
+			}
+		})
+	}
+}
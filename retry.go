@@ -0,0 +1,70 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"time"
+)
+
+// WithRetry makes the Walker try reading a directory up to attempts times
+// before reporting the error to the walk callback, waiting backoff(n) before
+// the nth retry. Only errors for which retryable returns true are retried.
+//
+// A nil backoff retries immediately. A nil retryable retries every error
+// except those matching [fs.ErrNotExist], [fs.ErrPermission] and
+// [fs.ErrInvalid], which are not transient. Retries stop early once the
+// deadline set with [WithTimeout] or [WithDeadline] has passed.
+func WithRetry(attempts int, backoff func(int) time.Duration, retryable func(error) bool) Option {
+	return func(w *Walker) {
+		w.retry = retryPolicy{attempts, backoff, retryable}
+	}
+}
+
+type retryPolicy struct {
+	attempts  int
+	backoff   func(int) time.Duration
+	retryable func(error) bool
+}
+
+// shouldRetry reports whether a read that failed with err on attempt n,
+// counting from 1, should be retried.
+func (p retryPolicy) shouldRetry(n int, err error) bool {
+	if n >= p.attempts {
+		return false
+	}
+	if p.retryable != nil {
+		return p.retryable(err)
+	}
+	return !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, fs.ErrPermission) && !errors.Is(err, fs.ErrInvalid)
+}
+
+// readDirRetry reads a page of the directory dir, retrying failures
+// according to the retry policy of the Walker.
+func (w *Walker) readDirRetry(fsys fs.FS, dir namedEntry, token string) ([]fs.DirEntry, string, error) {
+	for n := 1; ; n++ {
+		w.throttle()
+		dirs, next, err := w.readEntries(fsys, dir, token)
+		if err == nil || !w.retry.shouldRetry(n, err) || w.interrupted() != nil {
+			return dirs, next, err
+		}
+		select {
+		case <-w.cancelled():
+			return dirs, next, err
+		default:
+		}
+		w.stats.retries.Add(1)
+		if w.debugLog {
+			w.debug("retry directory read", dir, slog.Int("attempt", n), slog.Any("error", err))
+		}
+		if w.retry.backoff != nil {
+			t := time.NewTimer(w.retry.backoff(n))
+			select {
+			case <-t.C:
+			case <-w.cancelled():
+				t.Stop()
+				return dirs, next, err
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestWithRetry(t *testing.T) {
+	errBlip := errors.New("transient")
+	memFS := fstest.MapFS{
+		"root/dirA/file1.txt": {Data: []byte("")},
+		"root/dirB/file1.txt": {Data: []byte("")},
+	}
+
+	cases := []struct {
+		name      string
+		failures  int
+		opt       Option
+		expected  []string
+		retries   int64
+		backoffed []int
+	}{
+		{"Recovered", 2, WithRetry(3, nil, nil), []string{
+			"root", "root/dirA", "root/dirB", "root/dirA/file1.txt", "root/dirB/file1.txt",
+		}, 2, nil},
+		{"Exhausted", 3, WithRetry(3, nil, nil), []string{
+			"root", "root/dirA", "root/dirB", "error:root/dirA", "root/dirB/file1.txt",
+		}, 2, nil},
+		{"NotRetryable", 1, WithRetry(3, nil, func(err error) bool { return false }), []string{
+			"root", "root/dirA", "root/dirB", "error:root/dirA", "root/dirB/file1.txt",
+		}, 0, nil},
+		{"Backoff", 2, nil, []string{
+			"root", "root/dirA", "root/dirB", "root/dirA/file1.txt", "root/dirB/file1.txt",
+		}, 2, []int{1, 2}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var backoffed []int
+			opt := c.opt
+			if opt == nil {
+				opt = WithRetry(5, func(n int) time.Duration {
+					backoffed = append(backoffed, n)
+					return time.Millisecond
+				}, nil)
+			}
+			fsys := &flakyFS{MapFS: memFS, name: "root/dirA", failures: c.failures, err: errBlip}
+
+			var visited []string
+			w := NewWalker(opt)
+			err := w.WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					visited = append(visited, "error:"+path)
+					return fs.SkipDir
+				}
+				visited = append(visited, path)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(visited, c.expected) {
+				t.Errorf("expected:\n  %v\ngot\n: %v", c.expected, visited)
+			}
+			if s := w.Stats(); s.Retries != c.retries {
+				t.Errorf("expected %d retries, got %d", c.retries, s.Retries)
+			}
+			if !slices.Equal(backoffed, c.backoffed) {
+				t.Errorf("expected backoff for %v, got %v", c.backoffed, backoffed)
+			}
+		})
+	}
+}
+
+func TestWithRetryNotTransient(t *testing.T) {
+	fsys := &flakyFS{MapFS: fstest.MapFS{"root/file1.txt": {}}, name: "root", failures: 1, err: fs.ErrPermission}
+	w := NewWalker(WithRetry(3, nil, nil))
+	err := w.WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		return err
+	})
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("expected %v, got %v", fs.ErrPermission, err)
+	}
+	if s := w.Stats(); s.Retries != 0 {
+		t.Errorf("expected no retries, got %d", s.Retries)
+	}
+}
+
+// flakyFS is a file system that fails to read the directory name the first
+// failures times.
+type flakyFS struct {
+	fstest.MapFS
+	name     string
+	failures int
+	err      error
+}
+
+func (f *flakyFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name == f.name && f.failures > 0 {
+		f.failures--
+		return nil, f.err
+	}
+	return f.MapFS.ReadDir(name)
+}
@@ -0,0 +1,266 @@
+// Package routes builds route trees for file-based routers from directory
+// trees, in the style of the app directories of web frameworks:
+//
+//	pages/
+//		layout.html        -> layout of every route
+//		index.html         -> /
+//		blog/
+//			index.html     -> /blog
+//			[slug]/
+//				index.html -> /blog/[slug]
+//		(marketing)/
+//			about/
+//				index.html -> /about
+//		docs/
+//			[...path]/
+//				index.html -> /docs/[...path]
+//
+// Each directory is a route, whose segment is the name of the directory.
+// A file named index, with any extension, such as index.html or index.tsx,
+// is the page of its route, and a file named layout is its layout. Names in
+// brackets, parentheses and double brackets are parsed as described by
+// [Kind].
+package routes
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+// A Kind is the kind of segment of a [Route], given by the name of its
+// directory.
+type Kind int
+
+const (
+	Static           Kind = iota // name: matches the segment name
+	Group                        // (name): matches no segment, grouping routes
+	Dynamic                      // [param]: matches any single segment
+	CatchAll                     // [...param]: matches one or more segments
+	OptionalCatchAll             // [[...param]]: matches zero or more segments
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case Static:
+		return "static"
+	case Group:
+		return "group"
+	case Dynamic:
+		return "dynamic"
+	case CatchAll:
+		return "catch-all"
+	case OptionalCatchAll:
+		return "optional catch-all"
+	}
+	return "unknown"
+}
+
+// A Route is a directory of a route tree built by [Build].
+type Route struct {
+	Segment  string   // name of the directory, "" for the root
+	Kind     Kind     // kind of segment
+	Param    string   // name of the parameter of dynamic and catch-all segments
+	Pattern  string   // URL path of the route, such as "/blog/[slug]"
+	Dir      string   // path of the directory
+	Index    string   // path of the index file, "" if there is none
+	Layout   string   // path of the layout file, "" if there is none
+	Files    []string // paths of the other files of the directory
+	Parent   *Route   // parent route, nil for the root
+	Children []*Route // routes of the subdirectories, in the order they are matched
+}
+
+// Build walks the directory tree rooted at root breadth-first and returns
+// it as a tree of routes, with the root route at the top. Paths of
+// directories and files are paths in fsys, as passed to [fs.WalkDirFunc].
+//
+// Children of each route are ordered as they are matched by [Route.Match]:
+// static segments first, then groups, dynamic segments, catch-all segments
+// and optional catch-all segments, each in lexical order. A directory with
+// several index or layout files uses the first in lexical order and lists the
+// others among its Files. The first error encountered stops the walk and is
+// returned.
+func Build(fsys fs.FS, root string) (*Route, error) {
+	var top *Route
+	dirs := make(map[string]*Route)
+	w := bfwalk.NewWalker(bfwalk.WithRelativePaths(), bfwalk.WithOrder(bySpecificity))
+	err := w.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			if !d.IsDir() {
+				return &fs.PathError{Op: "routes", Path: root, Err: errors.New("not a directory")}
+			}
+			top = &Route{Pattern: "/", Dir: root}
+			dirs[name] = top
+			return nil
+		}
+		parent, full := dirs[path.Dir(name)], path.Join(root, name)
+		if d.IsDir() {
+			r := newRoute(parent, d.Name(), full)
+			parent.Children = append(parent.Children, r)
+			dirs[name] = r
+			return nil
+		}
+		switch stem(d.Name()) {
+		case "index":
+			if parent.Index == "" {
+				parent.Index = full
+				return nil
+			}
+		case "layout":
+			if parent.Layout == "" {
+				parent.Layout = full
+				return nil
+			}
+		}
+		parent.Files = append(parent.Files, full)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return top, nil
+}
+
+// newRoute returns the route of the directory dir named segment, below
+// parent.
+func newRoute(parent *Route, segment, dir string) *Route {
+	kind, param := parseSegment(segment)
+	r := &Route{Segment: segment, Kind: kind, Param: param, Dir: dir, Parent: parent}
+	switch {
+	case kind == Group:
+		r.Pattern = parent.Pattern
+	case parent.Pattern == "/":
+		r.Pattern = "/" + segment
+	default:
+		r.Pattern = parent.Pattern + "/" + segment
+	}
+	return r
+}
+
+// parseSegment returns the kind of the segment named name, and the name of
+// its parameter, if any. Names that are malformed or name no parameter are
+// static.
+func parseSegment(name string) (Kind, string) {
+	if p, ok := enclosed(name, "[[...", "]]"); ok {
+		return OptionalCatchAll, p
+	}
+	if p, ok := enclosed(name, "[...", "]"); ok {
+		return CatchAll, p
+	}
+	if p, ok := enclosed(name, "[", "]"); ok {
+		return Dynamic, p
+	}
+	if p, ok := enclosed(name, "(", ")"); ok {
+		return Group, p
+	}
+	return Static, ""
+}
+
+// enclosed returns the part of name between prefix and suffix, if name has
+// both and that part is not empty and holds no brackets or parentheses.
+func enclosed(name, prefix, suffix string) (string, bool) {
+	inner, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return "", false
+	}
+	inner, ok = strings.CutSuffix(inner, suffix)
+	if !ok || inner == "" || strings.ContainsAny(inner, "[]()") {
+		return "", false
+	}
+	return inner, true
+}
+
+// stem returns the name of a file up to its first dot.
+func stem(name string) string {
+	s, _, _ := strings.Cut(name, ".")
+	return s
+}
+
+// bySpecificity orders entries by the kind of segment they name, then by
+// name, so that routes are matched most specific first.
+func bySpecificity(a, b fs.DirEntry) int {
+	ka, _ := parseSegment(a.Name())
+	kb, _ := parseSegment(b.Name())
+	if ka != kb {
+		return int(ka) - int(kb)
+	}
+	return bfwalk.OrderByName(a, b)
+}
+
+// Layouts returns the paths of the layout files applying to r, from the
+// root route down to r.
+func (r *Route) Layouts() []string {
+	var layouts []string
+	for ; r != nil; r = r.Parent {
+		if r.Layout != "" {
+			layouts = append(layouts, r.Layout)
+		}
+	}
+	slices.Reverse(layouts)
+	return layouts
+}
+
+// Match returns the route below r, or r itself, whose index file serves the
+// slash-separated URL path urlPath, relative to the pattern of r, along with
+// the values of the parameters of its dynamic and catch-all segments.
+// Catch-all parameters hold the segments they match joined by slashes, and
+// an optional catch-all matching no segment is absent from params. Children
+// are tried in order, and Match reports false if no route matches.
+func (r *Route) Match(urlPath string) (route *Route, params map[string]string, ok bool) {
+	var segs []string
+	for seg := range strings.SplitSeq(urlPath, "/") {
+		if seg != "" {
+			segs = append(segs, seg)
+		}
+	}
+	params = make(map[string]string)
+	if route = r.match(segs, params); route == nil {
+		return nil, nil, false
+	}
+	return route, params, true
+}
+
+// match returns the route below r matching segs, the segments of a URL path
+// below r, recording the parameters it binds in params.
+func (r *Route) match(segs []string, params map[string]string) *Route {
+	if len(segs) == 0 && r.Index != "" {
+		return r
+	}
+	for _, c := range r.Children {
+		var m *Route
+		switch c.Kind {
+		case Static:
+			if len(segs) > 0 && segs[0] == c.Segment {
+				m = c.match(segs[1:], params)
+			}
+		case Group:
+			m = c.match(segs, params)
+		case Dynamic:
+			if len(segs) > 0 {
+				params[c.Param] = segs[0]
+				if m = c.match(segs[1:], params); m == nil {
+					delete(params, c.Param)
+				}
+			}
+		case CatchAll, OptionalCatchAll:
+			if c.Index != "" && (len(segs) > 0 || c.Kind == OptionalCatchAll) {
+				if len(segs) > 0 {
+					params[c.Param] = strings.Join(segs, "/")
+				}
+				m = c
+			}
+		}
+		if m != nil {
+			return m
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package routes
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+var site = fstest.MapFS{
+	"pages/layout.html":                      {},
+	"pages/index.html":                       {},
+	"pages/favicon.ico":                      {},
+	"pages/blog/index.html":                  {},
+	"pages/blog/layout.tsx":                  {},
+	"pages/blog/[slug]/index.html":           {},
+	"pages/blog/[slug]/index.test.js":        {},
+	"pages/blog/new/index.html":              {},
+	"pages/(marketing)/about/index.html":     {},
+	"pages/(marketing)/layout.html":          {},
+	"pages/docs/[...path]/index.html":        {},
+	"pages/shop/[[...filters]]/index.html":   {},
+	"pages/users/[id]/settings/index.html":   {},
+	"pages/users/[id/index.html":             {}, // Malformed, static
+	"pages/users/[id]/[tab]/index.html":      {},
+	"pages/users/[id]/profile/avatar.png":    {}, // No index, not a page
+	"pages/users/[id]/profile/[]/index.html": {},
+}
+
+func TestBuild(t *testing.T) {
+	top, err := Build(site, "pages")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	var visit func(r *Route)
+	visit = func(r *Route) {
+		got = append(got, r.Kind.String()+" "+r.Pattern+" "+r.Param)
+		for _, c := range r.Children {
+			visit(c)
+		}
+	}
+	visit(top)
+	expected := []string{
+		"static / ",
+		"static /blog ",
+		"static /blog/new ",
+		"dynamic /blog/[slug] slug",
+		"static /docs ",
+		"catch-all /docs/[...path] path",
+		"static /shop ",
+		"optional catch-all /shop/[[...filters]] filters",
+		"static /users ",
+		"static /users/[id ",
+		"dynamic /users/[id] id",
+		"static /users/[id]/profile ",
+		"static /users/[id]/profile/[] ",
+		"static /users/[id]/settings ",
+		"dynamic /users/[id]/[tab] tab",
+		"group / marketing",
+		"static /about ",
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected:\n  %q\ngot:\n  %q", expected, got)
+	}
+
+	if top.Index != "pages/index.html" || top.Layout != "pages/layout.html" {
+		t.Errorf("unexpected root index %q and layout %q", top.Index, top.Layout)
+	}
+	if !slices.Equal(top.Files, []string{"pages/favicon.ico"}) {
+		t.Errorf("unexpected root files %v", top.Files)
+	}
+	slug := top.Children[0].Children[1]
+	if slug.Dir != "pages/blog/[slug]" || slug.Index != "pages/blog/[slug]/index.html" {
+		t.Errorf("unexpected route %+v", slug)
+	}
+	if !slices.Equal(slug.Files, []string{"pages/blog/[slug]/index.test.js"}) {
+		t.Errorf("expected second index among files, got %v", slug.Files)
+	}
+	if got := slug.Layouts(); !slices.Equal(got, []string{"pages/layout.html", "pages/blog/layout.tsx"}) {
+		t.Errorf("unexpected layouts %v", got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	top, err := Build(site, "pages")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		url    string
+		index  string
+		params map[string]string
+	}{
+		{"/", "pages/index.html", map[string]string{}},
+		{"", "pages/index.html", map[string]string{}},
+		{"/blog/", "pages/blog/index.html", map[string]string{}},
+		{"/blog/new", "pages/blog/new/index.html", map[string]string{}},
+		{"/blog/hello", "pages/blog/[slug]/index.html", map[string]string{"slug": "hello"}},
+		{"/about", "pages/(marketing)/about/index.html", map[string]string{}},
+		{"/docs/a/b/c", "pages/docs/[...path]/index.html", map[string]string{"path": "a/b/c"}},
+		{"/shop", "pages/shop/[[...filters]]/index.html", map[string]string{}},
+		{"/shop/red/xl", "pages/shop/[[...filters]]/index.html", map[string]string{"filters": "red/xl"}},
+		{"/users/7/settings", "pages/users/[id]/settings/index.html", map[string]string{"id": "7"}},
+		{"/users/7/posts", "pages/users/[id]/[tab]/index.html", map[string]string{"id": "7", "tab": "posts"}},
+		{"/users/7/profile", "pages/users/[id]/[tab]/index.html", map[string]string{"id": "7", "tab": "profile"}}, // No page at profile
+		{"/users/[id", "pages/users/[id/index.html", map[string]string{}},
+		{"/users/7/profile/[]", "pages/users/[id]/profile/[]/index.html", map[string]string{"id": "7"}},
+	}
+	for _, c := range cases {
+		r, params, ok := top.Match(c.url)
+		if !ok {
+			t.Errorf("Match(%q): no match", c.url)
+			continue
+		}
+		if r.Index != c.index || !maps.Equal(params, c.params) {
+			t.Errorf("Match(%q): expected %s %v, got %s %v", c.url, c.index, c.params, r.Index, params)
+		}
+	}
+
+	for _, url := range []string{"/docs", "/users", "/users/7", "/missing"} {
+		if r, params, ok := top.Match(url); ok {
+			t.Errorf("Match(%q): unexpected match %s %v", url, r.Index, params)
+		}
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	if _, err := Build(site, "pages/index.html"); err == nil {
+		t.Error("expected error building routes of a file")
+	}
+	if _, err := Build(site, "missing"); err == nil {
+		t.Error("expected error building routes of a missing directory")
+	}
+}
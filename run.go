@@ -0,0 +1,32 @@
+package bfwalk
+
+import (
+	"context"
+	"io/fs"
+)
+
+// Run walks the file tree rooted at root like [Walker.WalkDirContext],
+// calling fn for each file or directory in the tree, including root.
+//
+// Unlike the other walk methods, Run keeps the state of the walk, including
+// its statistics, in a run of its own instead of in w, so that a Walker may
+// be configured once and used by any number of goroutines walking at once.
+// The statistics of runs are not reported by [Walker.Stats]; observe them
+// with [WithWalkObserver].
+//
+// The functions passed to the options of w are shared by its runs, and may
+// be called by several runs at once, so they must be safe for concurrent
+// use. Hooks keeping state for the walk in progress, such as those of
+// [WithWalkHooks] pairing each start with its end, are not: keep that state
+// in the [WalkObserver] of each walk instead.
+func (w *Walker) Run(ctx context.Context, fsys fs.FS, root string, fn fs.WalkDirFunc) error {
+	return w.newRun().WalkDirContext(ctx, fsys, root, func(_ context.Context, path string, d fs.DirEntry, err error) error {
+		return fn(path, d, err)
+	})
+}
+
+// newRun returns a Walker configured as w, with no walk state, to walk in
+// isolation from w.
+func (w *Walker) newRun() *Walker {
+	return &Walker{config: w.config}
+}
@@ -0,0 +1,87 @@
+package bfwalk
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"slices"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWalkerRun(t *testing.T) {
+	fsys := generateFS("data", 10, 3)
+	fsys.(fstest.MapFS)["data/.gitignore"] = &fstest.MapFile{Data: []byte("*.css\n")}
+	w := NewWalker(
+		WithIgnoreFiles(".gitignore"),
+		WithPruneNames("dir3_0"),
+		WithOrder(OrderBySize),
+		WithConcurrency(4),
+		WithRelativePaths(),
+	)
+
+	var expected []string
+	err := w.WalkDir(fsys, "data", func(path string, d fs.DirEntry, err error) error {
+		expected = append(expected, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stats := w.Stats()
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var visited []string
+			err := w.Run(context.Background(), fsys, "data", func(path string, d fs.DirEntry, err error) error {
+				visited = append(visited, path)
+				return err
+			})
+			if err != nil {
+				t.Errorf("run %d: unexpected error: %v", i, err)
+			}
+			if !slices.Equal(visited, expected) {
+				t.Errorf("run %d: expected:\n  %v\ngot:\n  %v", i, expected, visited)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := w.Stats(); got != stats {
+		t.Errorf("runs changed the stats of the Walker: expected %+v, got %+v", stats, got)
+	}
+}
+
+func TestWalkerRunCancel(t *testing.T) {
+	memFS := fstest.MapFS{}
+	for i := range 3 {
+		memFS[fmt.Sprintf("root/dir%d/file.txt", i)] = &fstest.MapFile{}
+	}
+	w := NewWalker()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := w.Run(ctx, memFS, "root", func(string, fs.DirEntry, error) error { return nil }); err != context.Canceled {
+		t.Errorf("expected error %v, got %v", context.Canceled, err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	var visited []string
+	err := w.Run(ctx, memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		visited = append(visited, path)
+		if path == "root" {
+			cancel()
+		}
+		return err
+	})
+	if err != context.Canceled {
+		t.Errorf("expected error %v, got %v", context.Canceled, err)
+	}
+	if !slices.Equal(visited, []string{"root"}) {
+		t.Errorf("unexpected visited %v", visited)
+	}
+}
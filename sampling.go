@@ -0,0 +1,92 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"math"
+	"sync/atomic"
+)
+
+// WithSampling makes the Walker descend into a random subset of the
+// subdirectories of each directory, to estimate the size of trees too large
+// to walk in full: at most fanOut subdirectories of each directory are
+// chosen at random, and each of them is then descended into with
+// probability p. The other subdirectories are still visited, but not read.
+// A p of 1 or more, or of 0 or less, descends into every chosen
+// subdirectory, and a fanOut of 0 or less chooses them all.
+//
+// The estimated totals of [Stats] extrapolate from the entries visited
+// the number of directories, files and bytes of the whole tree, weighting
+// each entry by the inverse of the probability of its directory being
+// read. Sampling only applies to directories walked breadth-first, or by
+// [Walker.IDWalk]: with [WithStrategy] DFS or below the depth of
+// [WithHybridStrategy], every subdirectory is descended into. As with [WithMaxEntriesPerDir], the random
+// choices are seeded with the path of each directory.
+func WithSampling(p float64, fanOut int) Option {
+	if p <= 0 || p > 1 {
+		p = 1
+	}
+	return func(w *Walker) {
+		w.sampleP, w.fanOut = p, max(fanOut, 0)
+		w.sampling = p < 1 || fanOut > 0
+	}
+}
+
+// sampleDirs returns the subdirectories of dir, found during its visit, to
+// descend into, recording the weight of each in the walk estimates.
+func (w *Walker) sampleDirs(dir namedEntry, found []namedEntry) []namedEntry {
+	m := len(found)
+	if m == 0 {
+		return found
+	}
+	r := pathRand(dir.name)
+	if w.fanOut > 0 && m > w.fanOut {
+		found = sample(r, found, w.fanOut)
+	}
+	q := w.sampleP * float64(len(found)) / float64(m) // Probability of each being read
+	kept := found[:0]
+	for _, sub := range found {
+		if w.sampleP < 1 && r.Float64() >= w.sampleP {
+			continue
+		}
+		if w.weights == nil {
+			w.weights = make(map[string]float64)
+		}
+		w.weights[readKey(sub)] = w.weight / q
+		kept = append(kept, sub)
+	}
+	return kept
+}
+
+// estimate records the visit of the entry d, read from a directory of the
+// given weight, in the walk estimates.
+func (w *Walker) estimate(d fs.DirEntry, weight float64) {
+	if d.IsDir() {
+		addFloat(&w.stats.estDirs, weight)
+		return
+	}
+	addFloat(&w.stats.estFiles, weight)
+	if d.Type().IsRegular() {
+		if info, err := d.Info(); err == nil {
+			addFloat(&w.stats.estBytes, weight*float64(info.Size()))
+		}
+	}
+}
+
+// addFloat adds v to the float64 stored in a. It must not be called
+// concurrently for the same a.
+func addFloat(a *atomic.Uint64, v float64) {
+	a.Store(math.Float64bits(math.Float64frombits(a.Load()) + v))
+}
+
+// dirWeight returns the weight of the entries of dir in the walk estimates,
+// forgetting the weight recorded when dir was queued. Directories that were
+// not queued have the weight of the directory being visited.
+func (w *Walker) dirWeight(dir namedEntry) float64 {
+	key := readKey(dir)
+	weight, ok := w.weights[key]
+	if !ok {
+		return w.weight // Root, or directory walked depth-first
+	}
+	delete(w.weights, key)
+	return weight
+}
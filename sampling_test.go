@@ -0,0 +1,97 @@
+package bfwalk
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithSampling(t *testing.T) {
+	memFS := fstest.MapFS{}
+	for i := range 100 {
+		for j := range 10 {
+			memFS[fmt.Sprintf("root/d%02d/f%d", i, j)] = &fstest.MapFile{Data: []byte("ab")}
+		}
+	}
+	walk := func(opts ...Option) Stats {
+		t.Helper()
+		w := NewWalker(opts...)
+		err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+			return err
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return w.Stats()
+	}
+
+	// Fan-out alone gives exact estimates of a uniform tree
+	stats := walk(WithSampling(1, 10))
+	if stats.ReadDirs != 11 || stats.Files != 100 {
+		t.Errorf("expected 11 reads and 100 files, got %d and %d", stats.ReadDirs, stats.Files)
+	}
+	if stats.EstimatedDirs != 101 || stats.EstimatedFiles != 1000 || stats.EstimatedBytes != 2000 {
+		t.Errorf("expected estimates of 101 dirs, 1000 files and 2000 bytes, got %v, %v and %v",
+			stats.EstimatedDirs, stats.EstimatedFiles, stats.EstimatedBytes)
+	}
+
+	stats = walk(WithSampling(0.2, 0))
+	if stats.ReadDirs <= 1 || stats.ReadDirs >= 101 {
+		t.Errorf("expected some directories to be read, got %d reads", stats.ReadDirs)
+	}
+	if est := stats.EstimatedFiles; est < 500 || est > 1500 {
+		t.Errorf("expected an estimate of about 1000 files, got %v", est)
+	}
+	if again := walk(WithSampling(0.2, 0)); again.ReadDirs != stats.ReadDirs {
+		t.Errorf("expected the same sample in every walk, got %d and %d reads", stats.ReadDirs, again.ReadDirs)
+	}
+
+	stats = walk(WithSampling(1, 0))
+	if stats.ReadDirs != 101 || stats.EstimatedFiles != 0 {
+		t.Errorf("expected a full walk without estimates, got %d reads and %v files", stats.ReadDirs, stats.EstimatedFiles)
+	}
+}
+
+func TestWithSamplingIDWalk(t *testing.T) {
+	memFS := fstest.MapFS{}
+	for i := range 10 {
+		for j := range 5 {
+			for k := range 2 {
+				memFS[fmt.Sprintf("root/d%d/e%d/f%d", i, j, k)] = &fstest.MapFile{Data: []byte("ab")}
+			}
+		}
+	}
+
+	// IDWalk must sample the same directories as WalkDir
+	var expected, visited []string
+	w := NewWalker(WithSampling(0.5, 3))
+	err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		expected = append(expected, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stats := w.Stats()
+	err = w.IDWalk(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		visited = append(visited, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+	if len(visited) == len(memFS)+1 {
+		t.Errorf("expected only some entries to be visited, got %d", len(visited))
+	}
+	again := w.Stats()
+	if again.EstimatedDirs != stats.EstimatedDirs || again.EstimatedFiles != stats.EstimatedFiles || again.EstimatedBytes != stats.EstimatedBytes {
+		t.Errorf("expected estimates of %v dirs, %v files and %v bytes, got %v, %v and %v",
+			stats.EstimatedDirs, stats.EstimatedFiles, stats.EstimatedBytes,
+			again.EstimatedDirs, again.EstimatedFiles, again.EstimatedBytes)
+	}
+}
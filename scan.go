@@ -0,0 +1,38 @@
+package bfwalk
+
+import "io/fs"
+
+// A ScanFunc is the type of the function called by [Scan] for each file or
+// directory visited. Returning false for a directory skips its contents; the
+// result is ignored for other entries.
+type ScanFunc func(path string, d fs.DirEntry) bool
+
+// Scan walks the file tree rooted at root breadth-first, calling fn for each
+// file or directory in the tree, including root, in the order the file
+// system lists them.
+//
+// Scan is a minimal variant of [WalkDir] for hot scanning loops: entries are
+// not sorted and directories that cannot be read are skipped without calling
+// fn. Scan only returns an error if root itself cannot be stat'ed.
+func Scan(fsys fs.FS, root string, fn ScanFunc) error {
+	return NewWalker(WithUnsorted()).Scan(fsys, root, fn)
+}
+
+// Scan walks the file tree rooted at root like the package-level [Scan]
+// function, with the traversal adjusted by the options the Walker was
+// created with. Unlike the package-level function, entries are only
+// unsorted if the Walker was created with [WithUnsorted].
+func (w *Walker) Scan(fsys fs.FS, root string, fn ScanFunc) error {
+	return w.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d == nil {
+				return err // Root could not be read
+			}
+			return fs.SkipDir
+		}
+		if !fn(path, d) && d.IsDir() {
+			return fs.SkipDir
+		}
+		return nil
+	})
+}
@@ -0,0 +1,57 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestScan(t *testing.T) {
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/file1.txt":          {Data: []byte("")},
+			"root/dirA/file1.txt":     {Data: []byte("")},
+			"root/dirB/file1.txt":     {Data: []byte("")},
+			"root/dirB/sub/file1.txt": {Data: []byte("")},
+			"root/dirC/file1.txt":     {Data: []byte("")},
+		},
+		errs: map[string]error{"root/dirC": errors.New("read failed")},
+	}
+
+	var visited []string
+	err := Scan(fsys, "root", func(path string, d fs.DirEntry) bool {
+		visited = append(visited, path)
+		return path != "root/dirA"
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"root",
+		"root/dirA",
+		"root/dirB",
+		"root/dirC",
+		"root/file1.txt",
+		"root/dirB/file1.txt",
+		"root/dirB/sub",
+		"root/dirB/sub/file1.txt",
+	}
+	slices.Sort(visited[1:5])
+	slices.Sort(visited[5:7])
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+func TestScanMissingRoot(t *testing.T) {
+	err := Scan(fstest.MapFS{}, "missing", func(path string, d fs.DirEntry) bool {
+		t.Errorf("unexpected call for %s", path)
+		return true
+	})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected %v, got %v", fs.ErrNotExist, err)
+	}
+}
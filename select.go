@@ -0,0 +1,100 @@
+package bfwalk
+
+import (
+	"hash/fnv"
+	"io/fs"
+	"math/rand/v2"
+)
+
+// A Selection is the way [WithMaxEntriesPerDir] chooses the entries of a
+// directory to visit.
+type Selection int
+
+const (
+	// SelectFirst visits the first entries of each directory, in the order
+	// they would be visited without a limit.
+	SelectFirst Selection = iota
+
+	// SelectRandom visits a random sample of the entries of each directory,
+	// in the order they would be visited without a limit.
+	SelectRandom
+)
+
+// WithMaxEntriesPerDir makes the Walker visit at most n entries of any
+// single directory, chosen as given by s, leaving the others out of the
+// walk: they are not passed to the walk callback and directories are not
+// descended into. This bounds the work spent on very wide directories. The
+// limit applies to the entries left once the entries of the directory have
+// been filtered by the other options, and a limit of 0 or less means no
+// limit.
+//
+// With [SelectFirst], file systems that implement [ReadDirPager] are not
+// read past the page in which the limit is reached. With [SelectRandom],
+// directories are read in full, and the sample is drawn from a generator
+// seeded with the path of the directory, so that a directory listing the
+// same entries yields the same sample in every walk.
+func WithMaxEntriesPerDir(n int, s Selection) Option {
+	return func(w *Walker) {
+		w.maxPerDir = max(n, 0)
+		w.selection = s
+	}
+}
+
+// String returns the name of the selection.
+func (s Selection) String() string {
+	switch s {
+	case SelectFirst:
+		return "SelectFirst"
+	case SelectRandom:
+		return "SelectRandom"
+	}
+	return "unknown"
+}
+
+// selectEntries returns at most n of dirs, the entries of the directory
+// name, chosen as configured with WithMaxEntriesPerDir. The entries chosen
+// keep their order.
+func (w *Walker) selectEntries(name string, dirs []fs.DirEntry, n int) []fs.DirEntry {
+	n = max(n, 0)
+	if len(dirs) <= n {
+		return dirs
+	}
+	if w.selection != SelectRandom {
+		return dirs[:n]
+	}
+	return sample(pathRand(name), dirs, n)
+}
+
+// sample returns n of s chosen at random with r, in their order in s,
+// reusing the storage of s.
+func sample[T any](r *rand.Rand, s []T, n int) []T {
+	// Selection sampling: keep each element with the probability of filling
+	// the remaining picks from the remaining elements.
+	kept := s[:0]
+	for i, v := range s {
+		if r.IntN(len(s)-i) < n-len(kept) {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// pathRand returns a random generator seeded with the path name.
+func pathRand(name string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return rand.New(rand.NewPCG(h.Sum64(), 0))
+}
+
+// selectPage returns the entries of dirs, a page of a directory, that keep
+// the entries of the directory within the limit given selected, the number
+// of entries of the previous pages, which it updates. It returns the token
+// of the next page, or "" once the limit is reached.
+func (w *Walker) selectPage(dirs []fs.DirEntry, next string, selected *int) ([]fs.DirEntry, string) {
+	dirs = dirs[:min(len(dirs), w.maxPerDir-*selected)]
+	*selected += len(dirs)
+	if *selected >= w.maxPerDir {
+		next = ""
+	}
+	return dirs, next
+}
@@ -0,0 +1,75 @@
+package bfwalk
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithMaxEntriesPerDir(t *testing.T) {
+	memFS := fstest.MapFS{}
+	for i := range 10 {
+		memFS[fmt.Sprintf("root/f%d", i)] = &fstest.MapFile{}
+		memFS[fmt.Sprintf("root/f0/f%d", i)] = &fstest.MapFile{}
+	}
+	memFS["root/f0"] = &fstest.MapFile{Mode: fs.ModeDir}
+
+	got, err := Collect(memFS, "root", WithMaxEntriesPerDir(3, SelectFirst))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"root", "root/f0", "root/f1", "root/f2", "root/f0/f0", "root/f0/f1", "root/f0/f2"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	sample := func() []string {
+		t.Helper()
+		got, err := Collect(memFS, "root", WithMaxEntriesPerDir(4, SelectRandom), WithPruneNames("f0"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return got
+	}
+	got = sample()
+	if len(got) != 5 || got[0] != "root" {
+		t.Fatalf("expected root and 4 entries, got %v", got)
+	}
+	if !slices.IsSorted(got[1:]) {
+		t.Errorf("expected entries in lexical order, got %v", got)
+	}
+	if again := sample(); !slices.Equal(again, got) {
+		t.Errorf("expected the same sample in every walk, got %v and %v", got, again)
+	}
+}
+
+func TestWithMaxEntriesPerDirPages(t *testing.T) {
+	memFS := fstest.MapFS{}
+	for i := range 10 {
+		memFS[fmt.Sprintf("root/f%d", i)] = &fstest.MapFile{}
+	}
+
+	for _, tt := range []struct {
+		s     Selection
+		pages []string
+	}{
+		{SelectFirst, []string{"page root 0", "page root 2"}},
+		{SelectRandom, []string{"page root 0", "page root 2", "page root 4", "page root 6", "page root 8"}},
+	} {
+		t.Run(tt.s.String(), func(t *testing.T) {
+			fsys := &pagerFS{memFS, new([]string)}
+			got, err := Collect(fsys, "root", WithPageSize(2), WithMaxEntriesPerDir(3, tt.s))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != 4 {
+				t.Errorf("expected root and 3 entries, got %v", got)
+			}
+			if !slices.Equal(*fsys.events, tt.pages) {
+				t.Errorf("expected pages %v, got %v", tt.pages, *fsys.events)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package bfwalk
+
+import "io/fs"
+
+// WithMinSize makes the Walker pass to the walk callback only the regular
+// files of at least size bytes. Directories and other files are still
+// visited, and the roots of the walk are always visited.
+//
+// The file info of every entry is read as each directory is read, as with
+// [WithStat]. Files whose info cannot be read are visited, and the error is
+// returned by Info. If the option is given more than once, the last size
+// given applies.
+func WithMinSize(size int64) Option {
+	return func(w *Walker) {
+		w.minSize = size
+	}
+}
+
+// WithMaxSize makes the Walker pass to the walk callback only the regular
+// files of at most size bytes, like [WithMinSize]. If the option is given
+// more than once, the last size given applies.
+func WithMaxSize(size int64) Option {
+	return func(w *Walker) {
+		w.maxSize = size
+	}
+}
+
+// sizeFiltered reports whether the Walker filters files by size.
+func (w *Walker) sizeFiltered() bool {
+	return w.minSize > 0 || w.maxSize >= 0
+}
+
+// withFileFilter makes the Walker leave out of the walk the files, other
+// than directories, for which keep returns false, reading the file info of
+// every entry as each directory is read.
+func withFileFilter(keep func(d fs.DirEntry, info fs.FileInfo) bool) Option {
+	return func(w *Walker) {
+		w.keep = append(w.keep, keep)
+	}
+}
+
+// filtered reports whether d is left out of the walk by a file filter.
+func (w *Walker) filtered(d fs.DirEntry) bool {
+	if d.IsDir() {
+		return w.staleDir(d)
+	}
+	info, err := d.Info()
+	if err != nil {
+		return false
+	}
+	if n := info.Size(); d.Type().IsRegular() && (n < w.minSize || w.maxSize >= 0 && n > w.maxSize) {
+		return true
+	}
+	for _, keep := range w.keep {
+		if !keep(d, info) {
+			return true
+		}
+	}
+	return false
+}
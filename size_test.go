@@ -0,0 +1,50 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithSize(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/empty":        {},
+		"root/small":        {Data: make([]byte, 10)},
+		"root/big":          {Data: make([]byte, 1000)},
+		"root/dir/medium":   {Data: make([]byte, 100)},
+		"root/dir/huge":     {Data: make([]byte, 5000)},
+		"root/dir/sub/tiny": {Data: make([]byte, 1)},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{"min", []Option{WithMinSize(100)}, []string{"root", "root/big", "root/dir", "root/dir/huge", "root/dir/medium", "root/dir/sub"}},
+		{"max", []Option{WithMaxSize(10)}, []string{"root", "root/dir", "root/empty", "root/small", "root/dir/sub", "root/dir/sub/tiny"}},
+		{"max zero", []Option{WithMaxSize(0)}, []string{"root", "root/dir", "root/empty", "root/dir/sub"}},
+		{"range", []Option{WithMinSize(10), WithMaxSize(1000)}, []string{"root", "root/big", "root/dir", "root/small", "root/dir/medium", "root/dir/sub"}},
+		{"last wins", []Option{WithMaxSize(10), WithMinSize(5000), WithMaxSize(5000), WithMinSize(1000)}, []string{"root", "root/big", "root/dir", "root/dir/huge", "root/dir/sub"}},
+	}
+	for _, tt := range tests {
+		got, err := Collect(fsys, "root", tt.opts...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+
+	// Files over a size, shallowest first
+	got, err := Find(fsys, "root", func(path string, d fs.DirEntry) bool { return !d.IsDir() }, WithMinSize(1000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"root/big", "root/dir/huge"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,224 @@
+package bfwalk
+
+import (
+	"bytes"
+	"hash"
+	"io/fs"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A Manifest is a snapshot of a file tree taken with [Snapshot], recording
+// the path, type, size and modification time of every entry and, if
+// requested, the digest of every regular file. It can be serialized as
+// JSON, compared with another snapshot with [Manifest.Diff], and used to
+// find what changed since it was taken.
+type Manifest struct {
+	Root    string          `json:"root"`    // root the tree was walked from
+	Entries []ManifestEntry `json:"entries"` // entries in breadth-first order
+}
+
+// A ManifestEntry is a single file or directory recorded in a [Manifest].
+type ManifestEntry struct {
+	Path    string      `json:"path"`             // slash-separated path relative to the root, "." for the root
+	Mode    fs.FileMode `json:"mode"`             // type and permission bits
+	Size    int64       `json:"size"`             // length in bytes, as reported by the file system
+	ModTime time.Time   `json:"modTime"`          // modification time
+	Digest  []byte      `json:"digest,omitempty"` // digest of the contents of regular files, with SnapshotHashes
+}
+
+// A SnapshotOption configures [Snapshot].
+type SnapshotOption func(*snapshotConfig)
+
+type snapshotConfig struct {
+	hasher func() hash.Hash
+}
+
+// SnapshotHashes makes [Snapshot] record the digest of the contents of
+// every regular file, computed with hash functions returned by h, such as
+// [SHA256], so that [Manifest.Diff] compares contents rather than
+// modification times.
+func SnapshotHashes(h func() hash.Hash) SnapshotOption {
+	return func(c *snapshotConfig) {
+		c.hasher = h
+	}
+}
+
+// Snapshot walks the file tree rooted at root breadth-first and returns a
+// [Manifest] of every file and directory in it, including root. The first
+// error encountered stops the walk and is returned, together with a
+// manifest of the entries recorded before it.
+func Snapshot(fsys fs.FS, root string, opts ...SnapshotOption) (*Manifest, error) {
+	var c snapshotConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	m := &Manifest{Root: root}
+	err := NewWalker(WithRelativePaths()).WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		m.Entries = append(m.Entries, ManifestEntry{
+			Path:    name,
+			Mode:    info.Mode(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+	if err == nil && c.hasher != nil {
+		err = m.hash(fsys, c.hasher)
+	}
+	return m, err
+}
+
+// hash records the digest of every regular file of m, read from fsys, on a
+// pool of goroutines.
+func (m *Manifest) hash(fsys fs.FS, h func() hash.Hash) error {
+	files := make(chan int)
+	errs := make(chan error, 1)
+	var wg sync.WaitGroup
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range files {
+				e := &m.Entries[i]
+				digest, err := hashFile(fsys, path.Join(m.Root, e.Path), h())
+				if err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+					continue
+				}
+				e.Digest = digest
+			}
+		}()
+	}
+	for i, e := range m.Entries {
+		if e.Mode.IsRegular() {
+			files <- i
+		}
+	}
+	close(files)
+	wg.Wait()
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return nil
+	}
+}
+
+// Lookup returns the entry of m at path, relative to the root, and whether
+// there is one. It searches the entries of m on every call; [Manifest.Diff]
+// and [WalkChanged] index them once per call instead.
+func (m *Manifest) Lookup(path string) (ManifestEntry, bool) {
+	i := slices.IndexFunc(m.Entries, func(e ManifestEntry) bool {
+		return e.Path == path
+	})
+	if i < 0 {
+		return ManifestEntry{}, false
+	}
+	return m.Entries[i], true
+}
+
+// byPath returns the entries of m indexed by path.
+func (m *Manifest) byPath() map[string]ManifestEntry {
+	index := make(map[string]ManifestEntry, len(m.Entries))
+	for _, e := range m.Entries {
+		index[e.Path] = e
+	}
+	return index
+}
+
+// Diff returns the changes that turn the tree recorded in m into the tree
+// recorded in other, in breadth-first order, as [Diff] does for two file
+// systems. The entries of the changes are [ManifestEntry] values described
+// as [fs.DirEntry] values.
+//
+// Entries are modified if their type or size differ, or if both have a
+// digest, if their digests differ, and otherwise if their modification
+// times differ. Directories are modified only if their type differs.
+func (m *Manifest) Diff(other *Manifest) []Change {
+	var changes []Change
+	mIndex, otherIndex := m.byPath(), other.byPath()
+	for _, a := range m.Entries {
+		b, ok := otherIndex[a.Path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Removed, a.Path, a.dirEntry(), nil})
+		case a.modified(b):
+			changes = append(changes, Change{Modified, a.Path, a.dirEntry(), b.dirEntry()})
+		}
+	}
+	for _, b := range other.Entries {
+		if _, ok := mIndex[b.Path]; !ok {
+			changes = append(changes, Change{Added, b.Path, nil, b.dirEntry()})
+		}
+	}
+	slices.SortStableFunc(changes, func(x, y Change) int {
+		return compareBFS(x.Path, y.Path)
+	})
+	return changes
+}
+
+// modified reports whether b differs from a, as described by
+// [Manifest.Diff].
+func (a ManifestEntry) modified(b ManifestEntry) bool {
+	switch {
+	case a.Mode.Type() != b.Mode.Type():
+		return true
+	case a.Mode.IsDir():
+		return false
+	case a.Size != b.Size:
+		return true
+	case a.Digest != nil && b.Digest != nil:
+		return !bytes.Equal(a.Digest, b.Digest)
+	}
+	return !a.ModTime.Equal(b.ModTime)
+}
+
+// compareBFS compares the slash-separated paths a and b in the order a
+// breadth-first walk of a tree in lexical order visits them.
+func compareBFS(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == ".":
+		return -1
+	case b == ".":
+		return 1
+	}
+	if n := strings.Count(a, "/") - strings.Count(b, "/"); n != 0 {
+		return n
+	}
+	return slices.Compare(strings.Split(a, "/"), strings.Split(b, "/"))
+}
+
+// dirEntry returns e described as a directory entry.
+func (e ManifestEntry) dirEntry() fs.DirEntry {
+	return fs.FileInfoToDirEntry(manifestInfo{e})
+}
+
+// manifestInfo is the [fs.FileInfo] of a [ManifestEntry].
+type manifestInfo struct {
+	e ManifestEntry
+}
+
+func (i manifestInfo) Name() string       { return path.Base(i.e.Path) }
+func (i manifestInfo) Size() int64        { return i.e.Size }
+func (i manifestInfo) Mode() fs.FileMode  { return i.e.Mode }
+func (i manifestInfo) ModTime() time.Time { return i.e.ModTime }
+func (i manifestInfo) IsDir() bool        { return i.e.Mode.IsDir() }
+func (i manifestInfo) Sys() any           { return nil }
@@ -0,0 +1,137 @@
+package bfwalk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	mtime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"root/a.txt":     {Data: []byte("hello"), ModTime: mtime, Mode: 0o644},
+		"root/dir":       {Mode: fs.ModeDir | 0o755, ModTime: mtime},
+		"root/dir/b.txt": {Data: []byte("world"), ModTime: mtime, Mode: 0o600},
+	}
+	m, err := Snapshot(fsys, "root", SnapshotHashes(SHA256))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, e := range m.Entries {
+		got = append(got, fmt.Sprintf("%s %v %d %x", e.Path, e.Mode, e.Size, e.Digest))
+	}
+	expected := []string{
+		". dr-xr-xr-x 0 ",
+		"a.txt -rw-r--r-- 5 2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		"dir drwxr-xr-x 0 ",
+		"dir/b.txt -rw------- 5 486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7",
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, got)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded Manifest
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changes := m.Diff(&decoded); len(changes) != 0 {
+		t.Errorf("expected no changes after a JSON round trip, got %v", changes)
+	}
+	if e, ok := decoded.Lookup("dir/b.txt"); !ok || e.Size != 5 || !e.ModTime.Equal(mtime) {
+		t.Errorf("unexpected entry %+v, %v", e, ok)
+	}
+	if _, ok := decoded.Lookup("missing"); ok {
+		t.Error("found missing entry")
+	}
+
+	decoded.Entries = append(decoded.Entries, ManifestEntry{Path: "dir/c.txt"})
+	if _, ok := decoded.Lookup("dir/c.txt"); !ok {
+		t.Error("entry added after Lookup not found")
+	}
+	if changes := m.Diff(&decoded); len(changes) != 1 || changes[0].Kind != Added || changes[0].Path != "dir/c.txt" {
+		t.Errorf("expected dir/c.txt to be added, got %v", changes)
+	}
+
+	_, err = Snapshot(errFS{fsys, map[string]error{"root/dir": fs.ErrPermission}}, "root")
+	if err != fs.ErrPermission {
+		t.Errorf("expected %v, got %v", fs.ErrPermission, err)
+	}
+}
+
+func TestManifestDiff(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	before := fstest.MapFS{
+		"keep":       {Data: []byte("same"), ModTime: t0},
+		"touched":    {Data: []byte("same"), ModTime: t0},
+		"grown":      {Data: []byte("a"), ModTime: t0},
+		"old/x":      {ModTime: t0},
+		"a/deep/y":   {ModTime: t0},
+		"becomesdir": {ModTime: t0},
+	}
+	after := fstest.MapFS{
+		"keep":         {Data: []byte("same"), ModTime: t0},
+		"touched":      {Data: []byte("same"), ModTime: t1},
+		"grown":        {Data: []byte("ab"), ModTime: t0},
+		"new/z":        {ModTime: t0},
+		"a/deep/y":     {ModTime: t0},
+		"a.b/c":        {ModTime: t0},
+		"becomesdir/w": {ModTime: t0},
+	}
+	describe := func(changes []Change) []string {
+		var s []string
+		for _, c := range changes {
+			s = append(s, c.Kind.String()+" "+c.Path)
+		}
+		return s
+	}
+
+	a, err := Snapshot(before, ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := Snapshot(after, ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := describe(a.Diff(b))
+	expected := []string{
+		"added a.b",
+		"modified becomesdir",
+		"modified grown",
+		"added new",
+		"removed old",
+		"modified touched",
+		"added a.b/c",
+		"added becomesdir/w",
+		"added new/z",
+		"removed old/x",
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, got)
+	}
+	// The order matches a walk of the union of the trees
+	changes, err := Diff(before, after, ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fromFS := describe(changes); !slices.Equal(got, fromFS) {
+		t.Errorf("expected the order of Diff:\n  %v\ngot\n: %v", fromFS, got)
+	}
+
+	// With digests, contents are compared instead of modification times
+	a, _ = Snapshot(before, ".", SnapshotHashes(SHA256))
+	b, _ = Snapshot(after, ".", SnapshotHashes(SHA256))
+	if got := describe(a.Diff(b)); slices.Contains(got, "modified touched") {
+		t.Errorf("expected touched to be unchanged, got %v", got)
+	}
+}
@@ -0,0 +1,58 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"runtime"
+	"sync"
+)
+
+// WithStat makes the Walker read the file info of every entry as each
+// directory is read, so that calling Info on the entries passed to the walk
+// callback returns immediately.
+//
+// Entries of large directories are stat'ed concurrently, which hides the
+// latency of file systems where Info makes a system or network call per
+// entry. An error getting the file info is returned by Info, as usual.
+func WithStat() Option {
+	return func(w *Walker) {
+		w.stat = true
+	}
+}
+
+// statBatch is the minimum number of entries each goroutine stats.
+const statBatch = 64
+
+// statEntries reads the file info of each entry of dirs into its cache,
+// where the entries are [CachedEntry] values, calling throttle before each.
+func statEntries(dirs []fs.DirEntry, throttle func()) {
+	eachEntry(dirs, func(d fs.DirEntry) {
+		throttle()
+		d.Info()
+	})
+}
+
+// eachEntry calls fn for each entry of dirs, spreading large directories
+// across goroutines.
+func eachEntry(dirs []fs.DirEntry, fn func(d fs.DirEntry)) {
+	run := func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			fn(dirs[i])
+		}
+	}
+
+	workers := min(runtime.GOMAXPROCS(0), len(dirs)/statBatch)
+	if workers <= 1 {
+		run(0, len(dirs))
+	} else {
+		var wg sync.WaitGroup
+		chunk := (len(dirs) + workers - 1) / workers
+		for lo := 0; lo < len(dirs); lo += chunk {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				run(lo, min(lo+chunk, len(dirs)))
+			}()
+		}
+		wg.Wait()
+	}
+}
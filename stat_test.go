@@ -0,0 +1,65 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithStat(t *testing.T) {
+	for _, n := range []int{3, 500} {
+		fsys := countingFS{MapFS: generateFS("data", n, 1).(fstest.MapFS), stats: new(atomic.Int64)}
+
+		var visited int
+		err := NewWalker(WithStat()).WalkDir(fsys, "data", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			visited++
+			before := fsys.stats.Load()
+			info, err := d.Info()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if info.Name() != d.Name() {
+				t.Errorf("expected info for %s, got %s", d.Name(), info.Name())
+			}
+			if path != "data" && fsys.stats.Load() != before {
+				t.Errorf("Info for %s was not read in advance", path)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if visited != n*11+1 {
+			t.Errorf("expected %d entries, got %d", n*11+1, visited)
+		}
+	}
+}
+
+// countingFS is a file system that counts the calls to Info on the entries
+// it lists.
+type countingFS struct {
+	fstest.MapFS
+	stats *atomic.Int64
+}
+
+func (f countingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	dirs, err := f.MapFS.ReadDir(name)
+	for i, d := range dirs {
+		dirs[i] = countingEntry{d, f.stats}
+	}
+	return dirs, err
+}
+
+type countingEntry struct {
+	fs.DirEntry
+	stats *atomic.Int64
+}
+
+func (e countingEntry) Info() (fs.FileInfo, error) {
+	e.stats.Add(1)
+	return e.DirEntry.Info()
+}
@@ -0,0 +1,91 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// A StopReason tells why a walk stopped.
+type StopReason int32
+
+const (
+	// Running means the walk is still in progress, or has not started.
+	Running StopReason = iota
+
+	// Completed means the walk visited the whole tree.
+	Completed
+
+	// SkippedAll means the walk callback returned [fs.SkipAll].
+	SkippedAll
+
+	// LimitReached means the limit set with [WithMaxResults] was reached.
+	LimitReached
+
+	// DeadlinePassed means the deadline set with [WithTimeout] or
+	// [WithDeadline] passed.
+	DeadlinePassed
+
+	// Canceled means the context of the walk was done.
+	Canceled
+
+	// Failed means the walk callback returned any other error.
+	Failed
+
+	// BudgetExceeded means the budget set with [WithMaxVisited] was
+	// exceeded.
+	BudgetExceeded
+)
+
+// String returns the name of the stop reason.
+func (r StopReason) String() string {
+	switch r {
+	case Running:
+		return "running"
+	case Completed:
+		return "completed"
+	case SkippedAll:
+		return "skipped all"
+	case LimitReached:
+		return "limit reached"
+	case DeadlinePassed:
+		return "deadline passed"
+	case Canceled:
+		return "canceled"
+	case Failed:
+		return "failed"
+	case BudgetExceeded:
+		return "budget exceeded"
+	}
+	return "unknown"
+}
+
+// WalkDirStats walks the file tree rooted at root like [WalkDir], with a
+// [Walker] configured with opts, and returns the statistics of the walk
+// alongside its error. The statistics count the entries visited before the
+// walk stopped, however it stopped, and [Stats].Stopped tells why.
+func WalkDirStats(fsys fs.FS, root string, fn fs.WalkDirFunc, opts ...Option) (Stats, error) {
+	w := NewWalker(opts...)
+	err := w.WalkDir(fsys, root, fn)
+	return w.Stats(), err
+}
+
+// stopped records why the walk stopped, unless that is already known.
+func (w *Walker) stopped(r StopReason) {
+	w.stats.stop.CompareAndSwap(int32(Running), int32(r))
+}
+
+// stopReason returns why the walk that returned err stopped, when the walk
+// itself did not record it.
+func (w *Walker) stopReason(err error) StopReason {
+	switch {
+	case err == nil:
+		return Completed
+	case errors.Is(err, ErrDeadlineExceeded):
+		return DeadlinePassed
+	case errors.Is(err, ErrBudgetExceeded):
+		return BudgetExceeded
+	case w.ctx != nil && w.ctx.Err() != nil && errors.Is(err, w.ctx.Err()):
+		return Canceled
+	}
+	return Failed
+}
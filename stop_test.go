@@ -0,0 +1,79 @@
+package bfwalk
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestWalkDirStatsStopped(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/dirA/file1.txt": {},
+		"root/dirB/file1.txt": {},
+		"root/file1.txt":      {},
+	}
+	errStop := errors.New("stop")
+	stopAt := func(n int, err error) fs.WalkDirFunc {
+		visited := 0
+		return func(path string, d fs.DirEntry, walkErr error) error {
+			visited++
+			if visited == n {
+				return err
+			}
+			return nil
+		}
+	}
+
+	cases := []struct {
+		name    string
+		fn      fs.WalkDirFunc
+		opts    []Option
+		err     error
+		visited int64
+		stopped StopReason
+	}{
+		{"completed", stopAt(0, nil), nil, nil, 6, Completed},
+		{"skip all", stopAt(3, fs.SkipAll), nil, nil, 3, SkippedAll},
+		{"max results", stopAt(0, nil), []Option{WithMaxResults(4)}, nil, 4, LimitReached},
+		{"deadline", stopAt(0, nil), []Option{WithDeadline(time.Now().Add(-time.Second))}, ErrDeadlineExceeded, 1, DeadlinePassed},
+		{"error", stopAt(2, errStop), nil, errStop, 2, Failed},
+		{"budget", stopAt(0, nil), []Option{WithMaxVisited(5)}, ErrBudgetExceeded, 5, BudgetExceeded},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := WalkDirStats(fsys, "root", c.fn, c.opts...)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("expected error %v, got %v", c.err, err)
+			}
+			if s.Visited != c.visited || s.Stopped != c.stopped {
+				t.Errorf("expected %d visited and %v, got %d and %v", c.visited, c.stopped, s.Visited, s.Stopped)
+			}
+		})
+	}
+}
+
+func TestWalkerStatsCanceled(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/dirA/file1.txt": {},
+		"root/dirB/file1.txt": {},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := NewWalker()
+	err := w.WalkDirContext(ctx, fsys, "root", func(ctx context.Context, path string, d fs.DirEntry, err error) error {
+		if path == "root/dirA" {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	if s := w.Stats(); s.Visited != 3 || s.Stopped != Canceled {
+		t.Errorf("expected 3 visited and %v, got %d and %v", Canceled, s.Visited, s.Stopped)
+	}
+}
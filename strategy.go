@@ -0,0 +1,55 @@
+package bfwalk
+
+// A Strategy is the order in which a [Walker] descends into directories.
+type Strategy int
+
+const (
+	// BFS walks the tree breadth-first, visiting every entry at one depth
+	// before any entry below it. It is the default.
+	BFS Strategy = iota
+
+	// DFS walks the tree depth-first like [fs.WalkDir], walking the
+	// contents of each directory as soon as it is visited.
+	DFS
+)
+
+// WithStrategy makes the Walker descend into directories in the order given
+// by s.
+//
+// With [DFS], the queue of directories waiting to be read is not used:
+// [WithPriority] has no effect, [Walker.Checkpoint] returns an error, and
+// the levels reported by [Walker.Levels] and [WithLevelHooks] may be
+// entered more than once.
+func WithStrategy(s Strategy) Option {
+	return func(w *Walker) {
+		w.dfsDepth = -1
+		if s == DFS {
+			w.dfsDepth = 0
+		}
+	}
+}
+
+// WithHybridStrategy makes the Walker walk breadth-first down to depth, and
+// then depth-first within each directory at that depth, in the order those
+// directories are read. This bounds the number of queued directories on
+// very wide trees while still visiting shallow entries first.
+//
+// A depth of 0 is the same as the [DFS] strategy. As with [DFS], the
+// directories below depth are not queued and [Walker.Checkpoint] returns an
+// error.
+func WithHybridStrategy(depth int) Option {
+	return func(w *Walker) {
+		w.dfsDepth = max(depth, 0)
+	}
+}
+
+// String returns the name of the strategy.
+func (s Strategy) String() string {
+	switch s {
+	case BFS:
+		return "BFS"
+	case DFS:
+		return "DFS"
+	}
+	return "unknown"
+}
@@ -0,0 +1,146 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithStrategyDFS(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":          {Data: []byte("")},
+		"root/dirA/file1.txt":     {Data: []byte("")},
+		"root/dirB/afile1.txt":    {Data: []byte("")},
+		"root/dirB/file1.txt":     {Data: []byte("")},
+		"root/dirB/sub/file1.txt": {Data: []byte("")},
+		"root/dirB/zfile1.txt":    {Data: []byte("")},
+		"root/dirC/file1.txt":     {Data: []byte("")},
+	}
+
+	// DFS must visit entries exactly like fs.WalkDir
+	tests := map[string]func(path string, d fs.DirEntry, err error) error{
+		"all": func(path string, d fs.DirEntry, err error) error {
+			return err
+		},
+		"skip dir": func(path string, d fs.DirEntry, err error) error {
+			if path == "root/dirB" {
+				return fs.SkipDir
+			}
+			return err
+		},
+		"skip parent": func(path string, d fs.DirEntry, err error) error {
+			if path == "root/dirB/file1.txt" {
+				return fs.SkipDir
+			}
+			return err
+		},
+		"skip all": func(path string, d fs.DirEntry, err error) error {
+			if path == "root/dirB/sub/file1.txt" {
+				return fs.SkipAll
+			}
+			return err
+		},
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			var expected, visited []string
+			expectedErr := fs.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+				expected = append(expected, path)
+				return fn(path, d, err)
+			})
+			w := NewWalker(WithStrategy(DFS))
+			err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+				visited = append(visited, path)
+				return fn(path, d, err)
+			})
+			if err != expectedErr {
+				t.Errorf("expected error %v, got %v", expectedErr, err)
+			}
+			if !slices.Equal(visited, expected) {
+				t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+			}
+		})
+	}
+}
+
+func TestWithStrategyDFSWalkDirs(t *testing.T) {
+	memFS := fstest.MapFS{
+		"a/dir/file1.txt": {Data: []byte("")},
+		"b/dir/file1.txt": {Data: []byte("")},
+	}
+
+	var visited []string
+	w := NewWalker(WithStrategy(DFS))
+	err := w.WalkDirs(memFS, []string{"b", "a"}, func(path string, d fs.DirEntry, err error) error {
+		visited = append(visited, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"b",
+		"b/dir",
+		"b/dir/file1.txt",
+		"a",
+		"a/dir",
+		"a/dir/file1.txt",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+func TestWithStrategyDFSCheckpoint(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt": {Data: []byte("")},
+	}
+
+	w := NewWalker(WithStrategy(DFS))
+	err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		_, err = w.Checkpoint()
+		return err
+	})
+	if err == nil {
+		t.Errorf("expected checkpoint error")
+	}
+}
+
+func TestWithHybridStrategy(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":          {Data: []byte("")},
+		"root/dirA/sub/file1.txt": {Data: []byte("")},
+		"root/dirA/zfile1.txt":    {Data: []byte("")},
+		"root/dirB/sub/file1.txt": {Data: []byte("")},
+	}
+
+	var visited []string
+	w := NewWalker(WithHybridStrategy(1))
+	err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		visited = append(visited, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"root",
+		"root/dirA",
+		"root/dirB",
+		"root/file1.txt",
+		"root/dirA/sub",
+		"root/dirA/sub/file1.txt",
+		"root/dirA/zfile1.txt",
+		"root/dirB/sub",
+		"root/dirB/sub/file1.txt",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+	if queued := w.Stats().Queued; queued != 0 {
+		t.Errorf("expected no queued directories, got %d", queued)
+	}
+}
@@ -0,0 +1,105 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// A SyncFS is a file system that can be read, written and removed from, as
+// used by [Sync]. Package osfs provides an implementation backed by a
+// directory of the operating system.
+type SyncFS interface {
+	fs.FS
+	WriteFS
+
+	// RemoveAll removes name and everything it contains, doing nothing if
+	// it does not exist.
+	RemoveAll(name string) error
+}
+
+// A SyncOption configures [Sync].
+type SyncOption func(*syncConfig)
+
+type syncConfig struct {
+	dryRun bool
+}
+
+// SyncDryRun makes [Sync] return the changes it would make without making
+// them.
+func SyncDryRun() SyncOption {
+	return func(c *syncConfig) {
+		c.dryRun = true
+	}
+}
+
+// Sync makes the file tree rooted at root in dst match the one in src, and
+// returns the changes made, in the order they were made. Entries only in src
+// are copied to dst as by [Copy], entries only in dst are removed, and
+// entries that differ are replaced with those of src. The changes are those
+// [Diff] reports with [DiffContents] from dst to src, so files are compared
+// by size and contents rather than modification time, which writing to dst
+// does not preserve.
+//
+// Entries of src that [Copy] does not copy, such as symbolic links, are left
+// out, along with any changes they would require. Entries removed from
+// within a directory that was removed or replaced are reported, but gone
+// with their directory. The first error
+// encountered stops the sync and is returned, together with the changes
+// made before it.
+func Sync(dst SyncFS, src fs.FS, root string, opts ...SyncOption) ([]Change, error) {
+	var c syncConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	changes, err := Diff(dst, src, root, DiffContents())
+	if err != nil {
+		return nil, err
+	}
+
+	var made []Change
+	var removed []string // paths removed from dst so far
+	for _, ch := range changes {
+		if ch.B != nil && !ch.B.IsDir() && !ch.B.Type().IsRegular() {
+			continue // Not copied
+		}
+		if ch.Kind == Removed && removedWith(removed, ch.Path) {
+			made = append(made, ch)
+			continue // Removed with its directory
+		}
+		if !c.dryRun {
+			if err := syncChange(dst, src, root, ch); err != nil {
+				return made, err
+			}
+		}
+		if ch.Kind == Removed || ch.Kind == Modified && ch.A.Type() != ch.B.Type() {
+			removed = append(removed, ch.Path)
+		}
+		made = append(made, ch)
+	}
+	return made, nil
+}
+
+// removedWith reports whether name is inside one of the removed paths.
+func removedWith(removed []string, name string) bool {
+	for _, dir := range removed {
+		if dir == "." || strings.HasPrefix(name, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// syncChange makes the change ch to dst.
+func syncChange(dst SyncFS, src fs.FS, root string, ch Change) error {
+	// Entries changing type are removed first, so that files are not
+	// written through the symbolic links they replace.
+	if ch.Kind == Removed || ch.Kind == Modified && ch.A.Type() != ch.B.Type() {
+		if err := dst.RemoveAll(ch.Path); err != nil {
+			return err
+		}
+	}
+	if ch.Kind == Removed {
+		return nil
+	}
+	return copyEntry(dst, src, ch.Path, ch.B, ch.Path == root)
+}
@@ -0,0 +1,118 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// memSyncFS is a SyncFS over a MapFS that records the operations done.
+type memSyncFS struct {
+	memWriteFS
+}
+
+func (m memSyncFS) RemoveAll(name string) error {
+	*m.ops = append(*m.ops, "remove "+name)
+	for p := range m.MapFS {
+		if p == name || strings.HasPrefix(p, name+"/") {
+			delete(m.MapFS, p)
+		}
+	}
+	return nil
+}
+
+func syncTrees() (dst memSyncFS, src fstest.MapFS) {
+	src = fstest.MapFS{
+		"root/same.txt":        {Data: []byte("same")},
+		"root/changed.txt":     {Data: []byte("new contents")},
+		"root/new/file.txt":    {Data: []byte("new")},
+		"root/wasfile/sub.txt": {Data: []byte("sub")},
+		"root/wasdir":          {Data: []byte("file")},
+		"root/link":            {Data: []byte("same.txt"), Mode: fs.ModeSymlink},
+	}
+	dst = memSyncFS{newMemWriteFS()}
+	maps.Copy(dst.MapFS, fstest.MapFS{
+		"root/same.txt":        {Data: []byte("same")},
+		"root/changed.txt":     {Data: []byte("old contents")},
+		"root/wasfile":         {Data: []byte("file")},
+		"root/wasdir/file.txt": {Data: []byte("file")},
+		"root/extra/file.txt":  {Data: []byte("extra")},
+	})
+	return dst, src
+}
+
+func changeList(changes []Change) []string {
+	list := make([]string, len(changes))
+	for i, c := range changes {
+		list[i] = c.Kind.String() + " " + c.Path
+	}
+	return list
+}
+
+func TestSync(t *testing.T) {
+	dst, src := syncTrees()
+	expected := []string{
+		"modified root/changed.txt",
+		"removed root/extra",
+		"added root/new",
+		"modified root/wasdir",
+		"modified root/wasfile",
+		"removed root/extra/file.txt",
+		"added root/new/file.txt",
+		"removed root/wasdir/file.txt",
+		"added root/wasfile/sub.txt",
+	}
+
+	planned, err := Sync(dst, src, "root", SyncDryRun())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := changeList(planned); !slices.Equal(got, expected) {
+		t.Errorf("expected plan:\n  %v\ngot\n: %v", expected, got)
+	}
+	if len(*dst.ops) != 0 {
+		t.Errorf("dry run changed dst: %v", *dst.ops)
+	}
+
+	made, err := Sync(dst, src, "root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := changeList(made); !slices.Equal(got, expected) {
+		t.Errorf("expected changes:\n  %v\ngot\n: %v", expected, got)
+	}
+	for _, op := range []string{"remove root/extra/file.txt", "remove root/wasdir/file.txt"} {
+		if slices.Contains(*dst.ops, op) {
+			t.Errorf("expected entries of removed directories to go with them, got %q", op)
+		}
+	}
+	changes, err := Diff(dst.MapFS, src, "root", DiffContents())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := changeList(changes); !slices.Equal(got, []string{"added root/link"}) {
+		t.Errorf("expected only the link to differ after sync, got %v", got)
+	}
+
+	made, err = Sync(dst, src, "root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(made) != 0 {
+		t.Errorf("expected no changes on second sync, got %v", changeList(made))
+	}
+}
+
+func TestSyncNewRoot(t *testing.T) {
+	src := fstest.MapFS{"a/root/file.txt": {Data: []byte("data")}}
+	dst := memSyncFS{newMemWriteFS()}
+	if _, err := Sync(dst, src, "a/root"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f := dst.MapFS["a/root/file.txt"]; f == nil || string(f.Data) != "data" {
+		t.Errorf("expected file to be synced, got %v", dst.MapFS)
+	}
+}
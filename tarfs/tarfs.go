@@ -0,0 +1,305 @@
+// Package tarfs provides an [fs.FS] over the contents of a tar archive, so
+// that archives can be walked with [bfwalk.WalkDir] without extracting them.
+package tarfs
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// maxLinks is the maximum number of symbolic links followed when opening a
+// path.
+const maxLinks = 40
+
+// FS is a file system holding the contents of a tar archive. It implements
+// [fs.ReadDirFS], [fs.ReadFileFS] and [fs.StatFS], and provides Lstat and
+// ReadLink for inspecting symbolic links.
+type FS struct {
+	files map[string]*file
+}
+
+// file is an entry of the archive.
+type file struct {
+	info     fs.FileInfo
+	data     []byte
+	link     string   // target of a symbolic link
+	children []string // sorted names of the entries of a directory
+}
+
+// New reads the tar archive from r, which may be compressed with gzip, and
+// returns a file system holding its contents.
+//
+// The whole archive is read into memory. Entry names are cleaned and made
+// relative, entries outside the archive root are skipped, and missing parent
+// directories are created. As when extracting, a later entry with the same
+// name replaces an earlier one. Hard links share the contents of their
+// target, and symbolic links are followed by Open and Stat when their target
+// is inside the archive.
+func New(r io.Reader) (*FS, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	} else {
+		r = br
+	}
+
+	fsys := &FS{files: map[string]*file{".": newDir(".")}}
+	var hardlinks []*tar.Header
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		name, ok := cleanName(hdr.Name)
+		if !ok || name == "." {
+			continue
+		}
+		hdr.Name = name
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			fsys.add(name, &file{info: hdr.FileInfo()})
+		case tar.TypeReg, tar.TypeChar, tar.TypeBlock, tar.TypeFifo:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			fsys.add(name, &file{info: hdr.FileInfo(), data: data})
+		case tar.TypeSymlink:
+			fsys.add(name, &file{info: hdr.FileInfo(), link: hdr.Linkname})
+		case tar.TypeLink:
+			hardlinks = append(hardlinks, hdr)
+		}
+	}
+	for _, hdr := range hardlinks {
+		target, ok := cleanName(hdr.Linkname)
+		if f := fsys.files[target]; ok && f != nil && f.info.Mode().IsRegular() {
+			hdr.Typeflag, hdr.Size = tar.TypeReg, int64(len(f.data))
+			fsys.add(hdr.Name, &file{info: hdr.FileInfo(), data: f.data})
+		}
+	}
+	for _, f := range fsys.files {
+		slices.Sort(f.children)
+	}
+	return fsys, nil
+}
+
+// cleanName returns name as a valid [fs.FS] path, reporting false if it
+// refers outside the root.
+func cleanName(name string) (string, bool) {
+	name = path.Clean(strings.TrimLeft(name, "/"))
+	return name, fs.ValidPath(name)
+}
+
+// add adds f at name, replacing any entry already there and creating missing
+// parent directories.
+func (fsys *FS) add(name string, f *file) {
+	if old, ok := fsys.files[name]; ok {
+		if old.info.IsDir() && f.info.IsDir() {
+			f.children = old.children
+		}
+	} else {
+		dir := path.Dir(name)
+		if _, ok := fsys.files[dir]; !ok || !fsys.files[dir].info.IsDir() {
+			fsys.add(dir, newDir(dir))
+		}
+		parent := fsys.files[dir]
+		parent.children = append(parent.children, path.Base(name))
+	}
+	fsys.files[name] = f
+}
+
+func newDir(name string) *file {
+	hdr := &tar.Header{Name: name, Typeflag: tar.TypeDir, Mode: 0o755}
+	return &file{info: hdr.FileInfo()}
+}
+
+// lookup returns the entry for name and its path with symbolic links
+// resolved, following a symbolic link in the last element of name only if
+// follow is set.
+func (fsys *FS) lookup(op, name string, follow bool) (*file, string, error) {
+	if !fs.ValidPath(name) {
+		return nil, "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	resolved, links := ".", 0
+	elems := strings.Split(name, "/")
+	if name == "." {
+		elems = nil
+	}
+	f := fsys.files["."]
+	for i := 0; i < len(elems); i++ {
+		if !f.info.IsDir() {
+			return nil, "", &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		next := path.Join(resolved, elems[i])
+		f = fsys.files[next]
+		if f == nil {
+			return nil, "", &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		if f.link == "" || i == len(elems)-1 && !follow {
+			resolved = next
+			continue
+		}
+		if links++; links > maxLinks {
+			return nil, "", &fs.PathError{Op: op, Path: name, Err: errors.New("too many links")}
+		}
+		target := f.link
+		if !path.IsAbs(target) {
+			target = path.Join(resolved, target)
+		}
+		target, ok := cleanName(target)
+		if !ok {
+			return nil, "", &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		// Restart from the root with the link replaced by its target
+		rest := append(strings.Split(target, "/"), elems[i+1:]...)
+		if target == "." {
+			rest = elems[i+1:]
+		}
+		elems, i, resolved, f = rest, -1, ".", fsys.files["."]
+	}
+	return f, resolved, nil
+}
+
+// Open opens the named file, following symbolic links.
+func (fsys *FS) Open(name string) (fs.File, error) {
+	f, resolved, err := fsys.lookup("open", name, true)
+	if err != nil {
+		return nil, err
+	}
+	info := renamed(f.info, path.Base(name))
+	if f.info.IsDir() {
+		return &openDir{fsys: fsys, info: info, name: resolved, entries: f.children}, nil
+	}
+	return &openFile{info: info, Reader: bytes.NewReader(f.data)}, nil
+}
+
+// ReadDir reads the named directory and returns its entries sorted by
+// filename.
+func (fsys *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	f, resolved, err := fsys.lookup("readdir", name, true)
+	if err != nil {
+		return nil, err
+	}
+	if !f.info.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	return fsys.entries(resolved, f.children), nil
+}
+
+// ReadFile reads the named file and returns its contents.
+func (fsys *FS) ReadFile(name string) ([]byte, error) {
+	f, _, err := fsys.lookup("read", name, true)
+	if err != nil {
+		return nil, err
+	}
+	if f.info.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errors.New("is a directory")}
+	}
+	return slices.Clone(f.data), nil
+}
+
+// Stat returns the file info of the named file, following symbolic links.
+func (fsys *FS) Stat(name string) (fs.FileInfo, error) {
+	f, _, err := fsys.lookup("stat", name, true)
+	if err != nil {
+		return nil, err
+	}
+	return renamed(f.info, path.Base(name)), nil
+}
+
+// Lstat returns the file info of the named file without following a
+// symbolic link in its last element.
+func (fsys *FS) Lstat(name string) (fs.FileInfo, error) {
+	f, _, err := fsys.lookup("lstat", name, false)
+	if err != nil {
+		return nil, err
+	}
+	return renamed(f.info, path.Base(name)), nil
+}
+
+// ReadLink returns the target of the named symbolic link.
+func (fsys *FS) ReadLink(name string) (string, error) {
+	f, _, err := fsys.lookup("readlink", name, false)
+	if err != nil {
+		return "", err
+	}
+	if f.link == "" {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return f.link, nil
+}
+
+// entries returns the entries named names of the directory dir.
+func (fsys *FS) entries(dir string, names []string) []fs.DirEntry {
+	entries := make([]fs.DirEntry, len(names))
+	for i, name := range names {
+		entries[i] = fs.FileInfoToDirEntry(fsys.files[path.Join(dir, name)].info)
+	}
+	return entries
+}
+
+// renamed returns info with the name changed to name.
+func renamed(info fs.FileInfo, name string) fs.FileInfo {
+	if info.Name() == name {
+		return info
+	}
+	return renamedInfo{info, name}
+}
+
+type renamedInfo struct {
+	fs.FileInfo
+	name string
+}
+
+func (i renamedInfo) Name() string { return i.name }
+
+type openFile struct {
+	info fs.FileInfo
+	*bytes.Reader
+}
+
+func (f *openFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *openFile) Close() error               { return nil }
+
+type openDir struct {
+	fsys    *FS
+	info    fs.FileInfo
+	name    string
+	entries []string
+}
+
+func (d *openDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *openDir) Close() error               { return nil }
+
+func (d *openDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *openDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	names := d.entries
+	if n > 0 {
+		if len(names) == 0 {
+			return nil, io.EOF
+		}
+		names = names[:min(n, len(names))]
+	}
+	d.entries = d.entries[len(names):]
+	return d.fsys.entries(d.name, names), nil
+}
@@ -0,0 +1,122 @@
+package tarfs
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/eriicafes/bfwalk"
+)
+
+func archive(t *testing.T, compress bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var tw *tar.Writer
+	var zw *gzip.Writer
+	if compress {
+		zw = gzip.NewWriter(&buf)
+		tw = tar.NewWriter(zw)
+	} else {
+		tw = tar.NewWriter(&buf)
+	}
+
+	entries := []struct {
+		hdr  tar.Header
+		data string
+	}{
+		{tar.Header{Name: "./root/", Typeflag: tar.TypeDir, Mode: 0o755}, ""},
+		{tar.Header{Name: "./root/file1.txt", Typeflag: tar.TypeReg, Mode: 0o644}, "hello"},
+		{tar.Header{Name: "root/dirB/sub/file1.txt", Typeflag: tar.TypeReg, Mode: 0o644}, "nested"},
+		{tar.Header{Name: "root/dirA/file1.txt", Typeflag: tar.TypeReg, Mode: 0o644}, "old"},
+		{tar.Header{Name: "root/dirA/file1.txt", Typeflag: tar.TypeReg, Mode: 0o644}, "new"},
+		{tar.Header{Name: "root/dirA/hard.txt", Typeflag: tar.TypeLink, Linkname: "root/file1.txt"}, ""},
+		{tar.Header{Name: "root/link", Typeflag: tar.TypeSymlink, Linkname: "dirB/sub"}, ""},
+		{tar.Header{Name: "../escape.txt", Typeflag: tar.TypeReg, Mode: 0o644}, "outside"},
+	}
+	for _, e := range entries {
+		e.hdr.Size = int64(len(e.data))
+		if err := tw.WriteHeader(&e.hdr); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := tw.Write([]byte(e.data)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestNew(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		fsys, err := New(bytes.NewReader(archive(t, compress)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var visited []string
+		err = bfwalk.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			visited = append(visited, path)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []string{
+			".",
+			"root",
+			"root/dirA",
+			"root/dirB",
+			"root/file1.txt",
+			"root/link",
+			"root/dirA/file1.txt",
+			"root/dirA/hard.txt",
+			"root/dirB/sub",
+			"root/dirB/sub/file1.txt",
+		}
+		if !slices.Equal(visited, expected) {
+			t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+		}
+
+		for name, data := range map[string]string{
+			"root/dirA/file1.txt": "new",
+			"root/dirA/hard.txt":  "hello",
+			"root/link/file1.txt": "nested",
+		} {
+			b, err := fs.ReadFile(fsys, name)
+			if err != nil || string(b) != data {
+				t.Errorf("%s: expected %q, got %q (%v)", name, data, b, err)
+			}
+		}
+
+		if _, err := fsys.Open("root/dirB/sub/../sub"); err == nil {
+			t.Errorf("expected error for invalid path")
+		}
+
+		if err := fstest.TestFS(fsys, "root/file1.txt", "root/dirA/hard.txt", "root/dirB/sub/file1.txt"); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	if _, err := New(bytes.NewReader([]byte{0x1f, 0x8b, 0, 0})); err == nil {
+		t.Errorf("expected error for invalid gzip stream")
+	}
+	if _, err := New(bytes.NewReader(bytes.Repeat([]byte("x"), 1024))); err == nil {
+		t.Errorf("expected error for invalid tar stream")
+	}
+}
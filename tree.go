@@ -0,0 +1,61 @@
+package bfwalk
+
+import (
+	"io/fs"
+	"path"
+)
+
+// A Node is an entry in a file tree built by [BuildTree].
+type Node struct {
+	Name     string      // base name of the entry
+	Path     string      // path of the entry, as passed to fs.WalkDirFunc
+	Entry    fs.DirEntry // entry describing the file or directory
+	Depth    int         // number of path elements below the root
+	Parent   *Node       // parent directory, nil for the root
+	Children []*Node     // immediate children, in breadth-first order
+}
+
+// BuildTree walks the file tree rooted at root and returns it as a graph of
+// [Node] values, with the root node at the top.
+//
+// Children of each directory are ordered as they are visited by [WalkDir].
+// The first error encountered stops the walk and is returned.
+func BuildTree(fsys fs.FS, root string) (*Node, error) {
+	return buildTree(fsys, root, -1, false)
+}
+
+// buildTree builds the tree rooted at root, descending at most maxDepth
+// levels when maxDepth is not negative and leaving out non-directories
+// other than root when dirsOnly is set.
+func buildTree(fsys fs.FS, root string, maxDepth int, dirsOnly bool) (*Node, error) {
+	var top *Node
+	dirs := make(map[string]*Node)
+	err := WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if dirsOnly && !d.IsDir() && top != nil {
+			return nil
+		}
+		node := &Node{Name: d.Name(), Path: name, Entry: d}
+		if top == nil {
+			top = node
+		} else {
+			parent := dirs[path.Dir(name)]
+			node.Parent = parent
+			node.Depth = parent.Depth + 1
+			parent.Children = append(parent.Children, node)
+		}
+		if d.IsDir() {
+			if node.Depth == maxDepth {
+				return fs.SkipDir
+			}
+			dirs[name] = node
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return top, nil
+}
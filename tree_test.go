@@ -0,0 +1,53 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBuildTree(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":          {Data: []byte("")},
+		"root/dirA/file1.txt":     {Data: []byte("")},
+		"root/dirB/file1.txt":     {Data: []byte("")},
+		"root/dirB/sub/file1.txt": {Data: []byte("")},
+	}
+
+	tree, err := BuildTree(memFS, "root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tree.Path != "root" || tree.Depth != 0 || tree.Parent != nil {
+		t.Fatalf("unexpected root node: %+v", tree)
+	}
+	var names []string
+	for _, c := range tree.Children {
+		names = append(names, c.Name)
+		if c.Parent != tree || c.Depth != 1 {
+			t.Errorf("unexpected parent or depth for %s", c.Path)
+		}
+	}
+	expected := []string{"dirA", "dirB", "file1.txt"}
+	if !slices.Equal(names, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, names)
+	}
+
+	sub := tree.Children[1].Children[1]
+	if sub.Path != "root/dirB/sub" || sub.Depth != 2 || !sub.Entry.IsDir() {
+		t.Errorf("unexpected node: %+v", sub)
+	}
+	if len(sub.Children) != 1 || sub.Children[0].Path != "root/dirB/sub/file1.txt" {
+		t.Errorf("unexpected children of %s: %v", sub.Path, sub.Children)
+	}
+}
+
+func TestBuildTreeError(t *testing.T) {
+	_, err := BuildTree(fstest.MapFS{}, "missing")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected %v, got %v", fs.ErrNotExist, err)
+	}
+}
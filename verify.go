@@ -0,0 +1,94 @@
+package bfwalk
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// A DivergenceError reports the first call of a walk callback at which two
+// walks of the same tree differ, as found by [VerifyDeterministic] and
+// [Trace.Verify].
+type DivergenceError struct {
+	Index int        // index of the first call that differs
+	Want  *TraceCall // call of the first or recorded walk, nil if it ended first
+	Got   *TraceCall // call of the walk being verified, nil if it ended first
+}
+
+func (e *DivergenceError) Error() string {
+	return fmt.Sprintf("bfwalk: walk diverges at call %d: got %s, want %s", e.Index, describeCall(e.Got), describeCall(e.Want))
+}
+
+// describeCall returns a description of the recorded call c.
+func describeCall(c *TraceCall) string {
+	if c == nil {
+		return "end of walk"
+	}
+	s := fmt.Sprintf("%q (depth %d, type %v", c.Path, c.Depth, c.Type)
+	if c.Err != "" {
+		s += ", error " + c.Err
+	}
+	return s + ")"
+}
+
+// VerifyDeterministic walks the file tree rooted at root runs times, with a
+// [Walker] configured with opts, and returns a [*DivergenceError] for the
+// first call of any walk after the first that differs from the first walk,
+// or nil if every walk makes the same calls. Paths, depths, parents, entry
+// types and errors passed to the callback are compared.
+//
+// Errors passed to the callback are recorded and do not stop the walks; an
+// error that stops a walk, such as [ErrDeadlineExceeded], is returned as
+// is. Fewer than two runs are treated as two.
+func VerifyDeterministic(fsys fs.FS, root string, runs int, opts ...Option) error {
+	want, err := recordWalk(fsys, root, opts)
+	if err != nil {
+		return err
+	}
+	for range max(runs, 2) - 1 {
+		if err := want.Verify(fsys, root, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Verify walks the file tree rooted at root with a [Walker] configured with
+// opts and returns a [*DivergenceError] for the first call that differs
+// from the calls recorded in t, as [VerifyDeterministic] does, so that the
+// order of a walk can be checked against one recorded earlier.
+func (t *Trace) Verify(fsys fs.FS, root string, opts ...Option) error {
+	got, err := recordWalk(fsys, root, opts)
+	if err != nil {
+		return err
+	}
+	return diverges(t, got)
+}
+
+// recordWalk walks the file tree rooted at root, recording every call of
+// the walk callback.
+func recordWalk(fsys fs.FS, root string, opts []Option) (*Trace, error) {
+	w := NewWalker(opts...)
+	t := new(Trace)
+	err := w.WalkDir(fsys, root, w.Record(t, func(path string, d fs.DirEntry, err error) error {
+		return nil
+	}))
+	return t, err
+}
+
+// diverges returns a [*DivergenceError] for the first call of got that
+// differs from want, or nil.
+func diverges(want, got *Trace) error {
+	for i := range max(len(want.Calls), len(got.Calls)) {
+		var w, g *TraceCall
+		if i < len(want.Calls) {
+			w = &want.Calls[i]
+		}
+		if i < len(got.Calls) {
+			g = &got.Calls[i]
+		}
+		if w == nil || g == nil || *w != *g {
+			return &DivergenceError{i, w, g}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package bfwalk
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestVerifyDeterministic(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/a/file": {},
+		"root/b":      {},
+	}
+	if err := VerifyDeterministic(fsys, "root", 3); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	flaky := &flakyFS{MapFS: fsys, name: "root/a", failures: 1, err: errors.New("unavailable")}
+	err := VerifyDeterministic(flaky, "root", 2)
+	var div *DivergenceError
+	if !errors.As(err, &div) {
+		t.Fatalf("expected a *DivergenceError, got %v", err)
+	}
+	if div.Index != 3 || div.Want.Path != "root/a" || div.Want.Err != "unavailable" || div.Got.Path != "root/a/file" {
+		t.Errorf("unexpected divergence %+v", div)
+	}
+	expected := `bfwalk: walk diverges at call 3: got "root/a/file" (depth 2, type ----------), want "root/a" (depth 1, type d---------, error unavailable)`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	err = VerifyDeterministic(fsys, "root", 2, WithDeadline(time.Now().Add(-time.Second)))
+	if err != ErrDeadlineExceeded {
+		t.Errorf("expected %v, got %v", ErrDeadlineExceeded, err)
+	}
+}
+
+func TestTraceVerify(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/a/file": {},
+		"root/b":      {},
+	}
+	w := NewWalker()
+	var trace Trace
+	err := w.WalkDir(fsys, "root", w.Record(&trace, func(path string, d fs.DirEntry, err error) error {
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := trace.Verify(fsys, "root"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	fsys["root/c"] = &fstest.MapFile{}
+	err = trace.Verify(fsys, "root")
+	var div *DivergenceError
+	if !errors.As(err, &div) {
+		t.Fatalf("expected a *DivergenceError, got %v", err)
+	}
+	if div.Index != 3 || div.Want.Path != "root/a/file" || div.Got.Path != "root/c" {
+		t.Errorf("unexpected divergence %+v", div)
+	}
+	expected := `bfwalk: walk diverges at call 3: got "root/c" (depth 1, type ----------), want "root/a/file" (depth 2, type ----------)`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
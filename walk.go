@@ -2,7 +2,11 @@ package bfwalk
 
 import (
 	"io/fs"
+	"log/slog"
 	"path"
+	"slices"
+	"strings"
+	"time"
 )
 
 // WalkDir walks the file tree rooted at root, calling fn for each file or
@@ -21,71 +25,321 @@ import (
 // WalkDir does not follow symbolic links found in directories,
 // but if root itself is a symbolic link, its target will be walked.
 func WalkDir(fsys fs.FS, root string, fn fs.WalkDirFunc) error {
-	info, err := fs.Stat(fsys, root)
-	if err != nil {
-		err = fn(root, nil, err)
-	} else {
-		d := fs.FileInfoToDirEntry(info)
-		err = fn(root, d, nil)
-		// Walk root if it is a directory and err is nil
-		if err == nil && d.IsDir() {
-			entry := namedEntry{root, d}
-			err = walkDir(fsys, []namedEntry{entry}, fn)
-		}
-	}
-	if err == fs.SkipDir || err == fs.SkipAll {
-		return nil
-	}
-	return err
+	return NewWalker().WalkDir(fsys, root, fn)
 }
 
-type namedEntry struct {
-	name string
-	d    fs.DirEntry
+// WalkDirs walks the file trees rooted at each of roots as a single
+// breadth-first traversal, calling fn for each file or directory in the
+// trees, including the roots.
+//
+// All roots are visited first, in the order given, then the entries of all
+// roots, then the entries of all their subdirectories, and so on. Otherwise
+// WalkDirs behaves like [WalkDir] called for each root in turn.
+func WalkDirs(fsys fs.FS, roots []string, fn fs.WalkDirFunc) error {
+	return NewWalker().WalkDirs(fsys, roots, fn)
 }
 
-// walkDir recursively descends path breadth first, calling walkDirFn.
-func walkDir(fsys fs.FS, queue []namedEntry, walkDirFn fs.WalkDirFunc) error {
-	if len(queue) == 0 {
-		return nil
-	}
-	name, d := queue[0].name, queue[0].d
-	queue = queue[1:] // Pop first entry
+// WalkAll walks the whole of fsys, such as an [embed.FS], like [WalkDir]
+// with root ".": fn is called with "." for the root and with paths such as
+// "dir/file" for the entries below it, without a leading "./". Roots such
+// as "", "/" and "./" are not valid paths of an [fs.FS].
+func WalkAll(fsys fs.FS, fn fs.WalkDirFunc) error {
+	return NewWalker().WalkAll(fsys, fn)
+}
 
-	dirs, err := fs.ReadDir(fsys, name)
-	if err != nil {
-		// Second call, to report ReadDir error.
-		err = walkDirFn(name, d, err)
-		if err != nil {
-			if err == fs.SkipDir && d.IsDir() {
-				err = nil
+type namedEntry struct {
+	name   string
+	d      fs.DirEntry
+	root   string       // root the entry was found under
+	depth  int          // number of path elements below root
+	ignore *ignoreRules // rules for the entries of the parent, with WithIgnoreFiles
+}
+
+// walkDir descends the queued directories breadth first, calling walkDirFn.
+func (w *Walker) walkDir(fsys fs.FS, walkDirFn fs.WalkDirFunc) error {
+	w.startPrefetch(fsys)
+	defer w.stopPrefetch()
+	for {
+		if w.queued() > 0 {
+			if err := w.interrupted(); err != nil {
+				return w.interruption(err)
 			}
+		}
+		w.fill()
+		dir, ok := w.dequeue()
+		if !ok {
+			return nil
+		}
+		if err := w.visitDir(fsys, dir, "", nil, walkDirFn); err != nil {
 			return err
 		}
 	}
+}
 
-	var subqueue []namedEntry
-	for _, d1 := range dirs {
-		name1 := path.Join(name, d1.Name())
-		err := walkDirFn(name1, d1, nil)
+// visitDir reads the directory dir and calls walkDirFn for each of its
+// entries, queueing subdirectories to be walked after those already queued.
+// Entries up to and including the one named after are skipped, and found
+// holds subdirectories queued by a previous visit; both are used to resume a
+// partially visited directory.
+func (w *Walker) visitDir(fsys fs.FS, dir namedEntry, after string, found []namedEntry, walkDirFn fs.WalkDirFunc) error {
+	name, d := dir.name, dir.d
+	// Subdirectories are gathered in a block of the arena, given back once
+	// they are queued.
+	w.dir, w.last, w.subqueue = dir, after, append(w.arena.take(len(found)), found...)
+	defer func() {
+		w.arena.release(w.subqueue)
+		w.dir, w.last, w.subqueue = namedEntry{}, "", nil
+	}()
+	if w.sampling {
+		weight := w.weight
+		w.weight = w.dirWeight(dir)
+		defer func() { w.weight = weight }()
+	}
+
+	// Entries are resumed by name when they are listed in lexical order,
+	// and by position otherwise.
+	_, paged := fsys.(ReadDirPager)
+	lexical := !w.unsorted && w.order == nil && !paged
+
+	token, visited, selected := "", 0, 0
+	for {
+		r := w.readDir(fsys, dir, token)
+		if err := w.spend(r.listed); err != nil {
+			return err
+		}
+		dirs, next, err := r.dirs, r.next, r.err
+		if w.maxPerDir > 0 {
+			dirs, next = w.selectPage(dirs, next, &selected)
+		}
+		if err != nil && w.denied(dir, err) {
+			err, next = nil, ""
+		}
 		if err != nil {
-			if err == fs.SkipAll {
+			// Second call, to report ReadDir error.
+			err = w.visit(walkDirFn, dir, err)
+			if err != nil {
+				if err == fs.SkipDir && d.IsDir() {
+					err = nil
+				}
 				return err
 			}
-			if err == fs.SkipDir {
-				if d1.IsDir() {
-					continue // Skip current directory
-				} else {
-					subqueue = nil
-					break // Skip parent directory
+			next = "" // Pages after a failed read cannot be listed
+		}
+
+		skipped := false
+		for _, d1 := range dirs {
+			visited++
+			if after != "" {
+				if !lexical {
+					if d1.Name() == after {
+						after = ""
+					}
+					continue // Visited before resuming
 				}
+				if d1.Name() <= after {
+					continue // Visited before resuming
+				}
+			}
+			name1 := path.Join(name, d1.Name())
+			entry := namedEntry{name1, d1, dir.root, dir.depth + 1, r.ignore}
+			w.last = d1.Name()
+			if w.dedupePaths && w.isPathSeen(name1) || w.dedupe && w.isLinkSeen(entry) {
+				visited-- // Visited before, under this path or another
+				continue
+			}
+			var err error
+			if w.matches(d1) {
+				w.visiting = entry
+				err = w.visit(walkDirFn, entry, nil)
+				w.visiting = namedEntry{}
+			} else {
+				visited-- // Filtered out, but descended into
 			}
+			if err != nil {
+				if err == fs.SkipDir {
+					if d1.IsDir() {
+						continue // Skip current directory
+					} else {
+						w.subqueue = w.subqueue[:0]
+						skipped = true
+						break // Skip parent directory
+					}
+				}
+				return err
+			}
+			if !d1.IsDir() || !w.shouldDescend(entry) {
+				continue
+			}
+			if w.dfsDepth >= 0 && entry.depth > w.dfsDepth {
+				if err := w.interrupted(); err != nil {
+					return w.interruption(err)
+				}
+				if err := w.visitDir(fsys, entry, "", nil, walkDirFn); err != nil {
+					return err
+				}
+				continue
+			}
+			w.subqueue = append(w.arena.grow(w.subqueue), entry)
+		}
+		if skipped || next == "" {
+			break
+		}
+		token = next
+	}
+	if w.sampling {
+		w.subqueue = w.sampleDirs(dir, w.subqueue)
+	}
+	w.enqueue(w.subqueue...)
+	w.dirDone(dir, visited)
+	return nil
+}
+
+// dirDone calls the directory-complete hooks for the directory dir, of
+// which the given number of entries were visited.
+func (w *Walker) dirDone(dir namedEntry, entries int) {
+	if len(w.onDirDone) == 0 {
+		return
+	}
+	reported := w.report(dir.root, dir.name)
+	for _, fn := range w.onDirDone {
+		fn(reported, entries)
+	}
+}
+
+// readDir reads the page of entries of the directory dir that starts at
+// token and prepares the entries to be visited. File systems that do not
+// implement [ReadDirPager] are read in a single page.
+func (w *Walker) readDir(fsys fs.FS, dir namedEntry, token string) readResult {
+	r, ok := w.prefetched(dir, token)
+	if !ok {
+		r = w.fetch(fsys, dir, token)
+	}
+	if w.debugLog {
+		attrs := []slog.Attr{slog.Duration("duration", r.elapsed), slog.Int("entries", len(r.dirs))}
+		if r.err != nil {
+			attrs = append(attrs, slog.Any("error", r.err))
+		}
+		w.debug("read directory", dir, attrs...)
+	}
+	if w.metrics != nil {
+		w.metrics.ReadDir(r.elapsed, len(r.dirs), r.err)
+	}
+	if len(w.onReadDir) > 0 {
+		reported := w.report(dir.root, dir.name)
+		for _, fn := range w.onReadDir {
+			fn(reported, r.elapsed, len(r.dirs), r.err)
 		}
-		if d1.IsDir() {
-			subqueue = append(subqueue, namedEntry{name1, d1})
+	}
+	for _, o := range w.observers {
+		if o.ReadDir != nil {
+			o.ReadDir(w.report(dir.root, dir.name), r.elapsed, len(r.dirs), r.err)
+		}
+	}
+	return r
+}
+
+// fetch reads a page of the directory dir and prepares its entries. It may
+// be called ahead of the walk, from another goroutine.
+func (w *Walker) fetch(fsys fs.FS, dir namedEntry, token string) readResult {
+	w.stats.inFlight.Add(1)
+	defer w.stats.inFlight.Add(-1)
+	defer w.stats.readDirs.Add(1)
+
+	start := time.Now()
+	dirs, next, err := w.readDirRetry(fsys, dir, token)
+	elapsed := time.Since(start)
+	listed := len(dirs)
+	cacheEntries(dirs)
+	var ignore *ignoreRules
+	if len(w.ignoreNames) > 0 {
+		all := dirs
+		if _, globbed := w.globber(fsys); globbed || token != "" || next != "" || err != nil {
+			all = nil // Ignore files may be listed in other pages, or not globbed
+		}
+		ignore = w.ignores(fsys, dir, all)
+		dirs = slices.DeleteFunc(dirs, func(d fs.DirEntry) bool {
+			return ignore.ignored(path.Join(dir.name, d.Name()), d, w.norm)
+		})
+	}
+	if w.includeSet {
+		dirs = slices.DeleteFunc(dirs, func(d fs.DirEntry) bool {
+			return !w.included(relPath(dir.root, path.Join(dir.name, d.Name())), d)
+		})
+	}
+	if len(w.prune) > 0 {
+		dirs = slices.DeleteFunc(dirs, w.pruned)
+	}
+	if len(w.exts) > 0 {
+		dirs = slices.DeleteFunc(dirs, w.otherExt)
+	}
+	filter := len(w.keep) > 0 || w.pruneStale || w.sizeFiltered()
+	if w.stat || filter {
+		statEntries(dirs, w.throttle)
+	}
+	if filter {
+		dirs = slices.DeleteFunc(dirs, w.filtered)
+	}
+	if w.sniff {
+		sniffEntries(fsys, dir.name, dirs, w.throttle)
+	}
+	if len(w.mimeTypes) > 0 {
+		dirs = slices.DeleteFunc(dirs, w.mimeRejected)
+	}
+	if w.order != nil {
+		slices.SortStableFunc(dirs, w.order)
+	}
+	if w.maxPerDir > 0 {
+		dirs = w.selectEntries(dir.name, dirs, w.maxPerDir)
+	}
+	return readResult{dirs, listed, next, ignore, err, elapsed}
+}
+
+// readEntries reads the page of entries of the directory dir that starts at
+// token, listing only the entries that may be kept by the include patterns
+// when they can be globbed.
+func (w *Walker) readEntries(fsys fs.FS, dir namedEntry, token string) ([]fs.DirEntry, string, error) {
+	if g, ok := w.globber(fsys); ok {
+		dirs, err := w.globDir(g, dir)
+		return dirs, "", err
+	}
+	return w.readPage(fsys, dir.name, token)
+}
+
+// readPage reads a page of entries of the directory name, sorting the
+// entries by filename unless the Walker is unsorted or the file system lists
+// directories in pages.
+func (w *Walker) readPage(fsys fs.FS, name, token string) ([]fs.DirEntry, string, error) {
+	if pager, ok := fsys.(ReadDirPager); ok {
+		if w.order != nil || w.maxPerDir > 0 && w.selection == SelectRandom {
+			// The whole directory must be read to sort or sample it
+			return readAllPages(pager, name, w.pageSize)
 		}
+		return pager.ReadDirPage(name, token, w.pageSize)
 	}
-	queue = append(queue, subqueue...)
+	dirs, err := listDir(fsys, name, !w.unsorted)
+	return dirs, "", err
+}
 
-	return walkDir(fsys, queue, walkDirFn)
+// depthOf returns the number of path elements name is below root, where name
+// is a path reported while walking root.
+func depthOf(root, name string) int {
+	if name == root {
+		return 0
+	}
+	if root == "." {
+		return strings.Count(name, "/") + 1
+	}
+	return strings.Count(name[len(root):], "/")
+}
+
+// relPath returns name relative to root, where name is a path reported while
+// walking root.
+func relPath(root, name string) string {
+	switch {
+	case name == root:
+		return "."
+	case root == ".":
+		return name
+	}
+	return name[len(root)+1:]
 }
@@ -1,6 +1,7 @@
 package bfwalk
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"path/filepath"
@@ -155,6 +156,192 @@ func TestWalkDirSkipDir(t *testing.T) {
 	}
 }
 
+func TestWalkDirs(t *testing.T) {
+	memFS := fstest.MapFS{
+		"a/file1.txt":      {Data: []byte("")},
+		"a/sub/file1.txt":  {Data: []byte("")},
+		"b/file1.txt":      {Data: []byte("")},
+		"c/sub/file1.txt":  {Data: []byte("")},
+		"c/sub/file2.txt":  {Data: []byte("")},
+		"skip/file1.txt":   {Data: []byte("")},
+		"single/file1.txt": {Data: []byte("")},
+	}
+
+	var visited []string
+	err := WalkDirs(memFS, []string{"b", "a", "skip", "c", "single/file1.txt"}, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		if path == "skip" {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"b",
+		"a",
+		"skip",
+		"c",
+		"single/file1.txt",
+		"b/file1.txt",
+		"a/file1.txt",
+		"a/sub",
+		"c/sub",
+		"a/sub/file1.txt",
+		"c/sub/file1.txt",
+		"c/sub/file2.txt",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+func TestWalkAll(t *testing.T) {
+	memFS := fstest.MapFS{
+		"index.html":     {Data: []byte("")},
+		"static/app.css": {Data: []byte("")},
+	}
+
+	var visited []string
+	err := WalkAll(memFS, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{".", "index.html", "static", "static/app.css"}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+
+	visited = nil
+	err = NewWalker(WithPathPrefix("public")).WalkAll(memFS, func(path string, d fs.DirEntry, err error) error {
+		visited = append(visited, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []string{"public", filepath.Join("public", "index.html"), filepath.Join("public", "static"), filepath.Join("public", "static", "app.css")}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+func TestWalkDirError(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":      {Data: []byte("")},
+		"root/dirA/file1.txt": {Data: []byte("")},
+	}
+
+	errStop := errors.New("stop")
+	var visited []string
+	err := WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		visited = append(visited, path)
+		if path == "root/dirA" {
+			return errStop
+		}
+		return err
+	})
+	if err != errStop {
+		t.Fatalf("expected %v, got %v", errStop, err)
+	}
+
+	expected := []string{
+		"root",
+		"root/dirA",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+func TestWalkDirReadDirError(t *testing.T) {
+	errRead := errors.New("read failed")
+	fsys := errFS{
+		MapFS: fstest.MapFS{
+			"root/dirA/file1.txt": {Data: []byte("")},
+			"root/dirB/file1.txt": {Data: []byte("")},
+		},
+		errs: map[string]error{"root/dirA": errRead},
+	}
+
+	var visited []string
+	err := WalkDir(fsys, "root", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if err != errRead {
+				t.Errorf("unexpected error for %s: %v", path, err)
+			}
+			visited = append(visited, "error:"+path)
+			return fs.SkipDir
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"root",
+		"root/dirA",
+		"root/dirB",
+		"error:root/dirA",
+		"root/dirB/file1.txt",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+// errFS is a file system that fails to open or read the directories in errs.
+type errFS struct {
+	fstest.MapFS
+	errs map[string]error
+}
+
+func (f errFS) Open(name string) (fs.File, error) {
+	if err, ok := f.errs[name]; ok {
+		return nil, err
+	}
+	return f.MapFS.Open(name)
+}
+
+func (f errFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if err, ok := f.errs[name]; ok {
+		return nil, err
+	}
+	return f.MapFS.ReadDir(name)
+}
+
+func TestDepthOf(t *testing.T) {
+	cases := []struct {
+		root, name string
+		depth      int
+	}{
+		{".", ".", 0},
+		{".", "a", 1},
+		{".", "a/b", 2},
+		{"root", "root", 0},
+		{"root", "root/a", 1},
+		{"root/sub", "root/sub/a/b", 2},
+	}
+	for _, c := range cases {
+		if got := depthOf(c.root, c.name); got != c.depth {
+			t.Errorf("depthOf(%q, %q): expected %d, got %d", c.root, c.name, c.depth, got)
+		}
+	}
+}
+
 func BenchmarkWalk(b *testing.B) {
 	smFsys := generateFS("data", 3, 2)
 	lgFsys := generateFS("data", 100, 5)
@@ -168,6 +355,7 @@ func BenchmarkWalk(b *testing.B) {
 		{"BreadthFirst Small", smFsys, WalkDir},
 		{"Std Large", lgFsys, fs.WalkDir},
 		{"BreadthFirst Large", lgFsys, WalkDir},
+		{"Walker Large", lgFsys, NewWalker().WalkDir},
 	}
 
 	for _, c := range cases {
@@ -0,0 +1,478 @@
+package bfwalk
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// A Walker walks file trees breadth-first.
+//
+// A Walker records statistics about the walk in progress, which may be
+// observed from another goroutine with [Walker.Stats]. A Walker must not be
+// used for more than one walk at a time, except with [Walker.Run]: walks do
+// not change the configuration of a Walker, and Run keeps the state of each
+// walk apart from it.
+type Walker struct {
+	config
+
+	stats walkStats
+
+	// State of the walk in progress.
+	until     time.Time          // deadline of the walk
+	queue     deque[namedEntry]  // directories waiting to be read
+	pq        prioQueue          // directories waiting to be read, with priority
+	dir       namedEntry         // directory whose entries are being visited
+	last      string             // name of the last entry visited in dir
+	subqueue  []namedEntry       // directories found in dir so far
+	arena     entryArena         // blocks gathering the subdirectories of each directory
+	visiting  namedEntry         // entry of dir being visited
+	current   namedEntry         // entry passed to the callback or descend functions
+	ctx       context.Context    // context of the walk, if any
+	debugLog  bool               // whether logger has debug logging enabled
+	level     int                // breadth level being visited, or -1
+	levelN    int                // entries visited in level so far
+	prefetch  *prefetcher        // reads of directories ahead of the walk
+	links     map[fileID]string  // paths of files with several hard links
+	seen      map[string]bool    // names of the entries visited, with WithDedupePaths
+	spent     int                // entries read, with WithMaxVisited
+	maxDepth  int                // deepest level visited
+	weight    float64            // weight of dir in the walk estimates
+	weights   map[string]float64 // weights of the directories queued, with WithSampling
+	observers []WalkObserver     // observers of the walk, with WithWalkObserver
+	deepening bool               // whether the walk is an IDWalk
+}
+
+// config is the configuration of a [Walker], set by its options and left
+// unchanged by walks.
+type config struct {
+	relative    bool
+	prefix      string
+	unsorted    bool
+	stat        bool
+	sniff       bool
+	order       Order
+	prefer      []string
+	foldCase    bool
+	exts        []string
+	normalize   func(string) string
+	rawPaths    bool
+	timeout     time.Duration
+	deadline    time.Time
+	limit       *limiter
+	retry       retryPolicy
+	pageSize    int
+	descend     []func(path string, d fs.DirEntry) bool
+	prune       []func(d fs.DirEntry) bool
+	filters     []Expr
+	ignoreNames []string
+	include     [][]string
+	includeSet  bool
+	mimeTypes   []string
+	minSize     int64
+	maxSize     int64 // or -1
+	keep        []func(d fs.DirEntry, info fs.FileInfo) bool
+	modAfter    time.Time
+	pruneStale  bool
+	trustDirs   bool
+	maxResults  int
+	maxVisited  int
+	maxPerDir   int
+	selection   Selection
+	sampling    bool
+	sampleP     float64
+	fanOut      int
+	metrics     MetricsSink
+	onStart     []func()
+	onEnd       []func(err error)
+	onReadDir   []func(path string, elapsed time.Duration, entries int, err error)
+	observe     []func(ctx context.Context) WalkObserver
+	logger      *slog.Logger
+	onLevel     []func(depth int)
+	onLevelEnd  []func(depth, visited int)
+	onDirDone   []func(path string, entries int)
+	priority    func(path string, d fs.DirEntry) int
+	dfsDepth    int // depth below which to walk depth-first, or -1
+	concurrency int
+	dedupe      bool
+	dedupePaths bool
+	skipDenied  bool
+	reportStops bool
+	stopState   bool
+	onLink      []func(path, first string)
+	onQueue     []queueHook
+	subscribers []func(Event)
+}
+
+// An Option configures a [Walker].
+type Option func(*Walker)
+
+// NewWalker returns a new [Walker] configured with opts.
+func NewWalker(opts ...Option) *Walker {
+	w := &Walker{config: config{pageSize: DefaultPageSize, dfsDepth: -1, maxSize: -1}}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if len(w.prefer) > 0 {
+		w.order = w.preferNames(w.order)
+	}
+	if w.foldCase || w.normalize != nil {
+		for i, pat := range w.include {
+			elems := make([]string, len(pat))
+			for j, elem := range pat {
+				elems[j] = w.norm(elem)
+				if w.foldCase {
+					elems[j] = strings.ToLower(elems[j])
+				}
+			}
+			w.include[i] = elems
+		}
+	}
+	return w
+}
+
+// WithRelativePaths makes the Walker report paths relative to the root being
+// walked, so that root itself is reported as "." and root/dir/file as
+// dir/file. Paths are still slash-separated.
+func WithRelativePaths() Option {
+	return func(w *Walker) {
+		w.relative = true
+	}
+}
+
+// WithUnsorted makes the Walker visit the entries of each directory in the
+// order the file system lists them, instead of lexical order.
+//
+// This avoids sorting every directory, and on file systems such as
+// [os.DirFS] that list entries incrementally, avoids the sort done by
+// [fs.ReadDir], at the cost of a traversal order that may differ between
+// walks. Directories are still visited breadth-first.
+func WithUnsorted() Option {
+	return func(w *Walker) {
+		w.unsorted = true
+	}
+}
+
+// WithShouldDescend makes the Walker call fn for each directory it visits,
+// after the walk callback returns nil, to decide whether to read the
+// directory and walk its contents. Directories for which fn returns false
+// are visited but not descended into, as if the callback returned
+// [fs.SkipDir], which keeps pruning decisions out of the walk callback.
+//
+// fn is passed the path as reported to the walk callback. If the option is
+// given more than once, directories are descended only if every fn returns
+// true.
+func WithShouldDescend(fn func(path string, d fs.DirEntry) bool) Option {
+	return func(w *Walker) {
+		w.descend = append(w.descend, fn)
+	}
+}
+
+// WithOnDirComplete makes the Walker call fn once every immediate entry of
+// a directory has been passed to the walk callback, with the path of the
+// directory as reported to the callback and the number of entries visited.
+//
+// fn is not called for directories the walk callback skips with
+// [fs.SkipDir] when they cannot be read, nor for the directory being read
+// when the walk is stopped. Only entries passed to the walk callback are
+// counted: entries left out by [WithFilter], [WithDedupePaths] or
+// [WithDedupeHardlinks], and entries left unvisited because the callback
+// returned [fs.SkipDir] for a file, are not. When resuming from a
+// checkpoint, entries visited before the checkpoint was taken are counted.
+// If the option is given more than once, each fn is called in order.
+func WithOnDirComplete(fn func(path string, entries int)) Option {
+	return func(w *Walker) {
+		w.onDirDone = append(w.onDirDone, fn)
+	}
+}
+
+// WithPathPrefix makes the Walker report native paths joined to prefix with
+// [filepath.Join], instead of slash-separated paths.
+//
+// This turns paths reported while walking os.DirFS(dir) into paths ready to
+// pass to [os.Open] when prefix is dir, and into absolute paths when dir is
+// absolute. It applies after [WithRelativePaths].
+func WithPathPrefix(prefix string) Option {
+	return func(w *Walker) {
+		w.prefix = prefix
+	}
+}
+
+// WalkDir walks the file tree rooted at root, calling fn for each file or
+// directory in the tree, including root.
+//
+// It behaves like the package-level [WalkDir] function, with the traversal
+// adjusted by the options the Walker was created with.
+func (w *Walker) WalkDir(fsys fs.FS, root string, fn fs.WalkDirFunc) error {
+	return w.WalkDirs(fsys, []string{root}, fn)
+}
+
+// WalkAll walks the whole of fsys like the package-level [WalkAll]
+// function, with the traversal adjusted by the options the Walker was
+// created with.
+func (w *Walker) WalkAll(fsys fs.FS, fn fs.WalkDirFunc) error {
+	return w.WalkDir(fsys, ".", fn)
+}
+
+// WalkDirs walks the file trees rooted at each of roots as a single
+// breadth-first traversal, calling fn for each file or directory in the
+// trees, including the roots.
+//
+// All roots are visited first, in the order given, then the entries of all
+// roots, then the entries of all their subdirectories, and so on. With the
+// [DFS] strategy, each root is instead walked in turn. Returning
+// [fs.SkipDir] for a root skips only that root.
+func (w *Walker) WalkDirs(fsys fs.FS, roots []string, fn fs.WalkDirFunc) (err error) {
+	w.start()
+	defer func() { w.finish(err) }()
+	for _, root := range roots {
+		err := w.visitRoot(fsys, root, fn)
+		if err == fs.SkipDir {
+			continue
+		}
+		if err == fs.SkipAll {
+			return nil
+		}
+		if err == nil && w.dfsDepth == 0 {
+			err = w.walkDir(fsys, fn) // Walk each root in turn
+		}
+		if err != nil {
+			if err == fs.SkipAll {
+				return nil
+			}
+			return err
+		}
+	}
+	err = w.walkDir(fsys, fn)
+	if err == fs.SkipDir || err == fs.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// visitRoot calls fn for root and queues it to be walked if it is a
+// directory.
+func (w *Walker) visitRoot(fsys fs.FS, root string, fn fs.WalkDirFunc) error {
+	if w.dedupePaths && w.isPathSeen(root) {
+		return fs.SkipDir
+	}
+	w.throttle()
+	info, err := stat(fsys, root)
+	if err != nil {
+		return w.visit(fn, namedEntry{root, nil, root, 0, nil}, err)
+	}
+	if err := w.spend(1); err != nil {
+		return err
+	}
+	d := NewCachedEntry(fs.FileInfoToDirEntry(info))
+	if w.sniff && d.Type().IsRegular() {
+		sniff(fsys, root, d)
+	}
+	entry := namedEntry{root, d, root, 0, nil}
+	err = w.visit(fn, entry, nil)
+	// Walk root if it is a directory and err is nil
+	if err == nil && d.IsDir() && w.shouldDescend(entry) {
+		w.enqueue(entry)
+	}
+	return err
+}
+
+// reset clears the state left by a previous walk.
+func (w *Walker) reset() {
+	w.stats.reset()
+	w.queue, w.pq = deque[namedEntry]{}, prioQueue{}
+	w.observers = nil
+	w.links, w.seen = nil, nil
+	w.spent, w.maxDepth = 0, 0
+	w.weight, w.weights = 1, nil
+	w.level, w.deepening = -1, false
+	w.until = w.deadline
+	if w.timeout > 0 {
+		w.until = time.Now().Add(w.timeout)
+	}
+}
+
+// visit calls fn for a single entry and records it in the walk statistics.
+func (w *Walker) visit(fn fs.WalkDirFunc, e namedEntry, err error) error {
+	w.current = e
+	level := e.depth
+	if err != nil && e.d != nil {
+		level++ // Read error, reported alongside the directory's contents
+	}
+	w.enterLevel(level)
+	w.maxDepth = max(w.maxDepth, e.depth)
+	w.stats.visited.Add(1)
+	if w.metrics != nil {
+		w.metrics.EntryVisited(err)
+	}
+	if err == nil && w.sampling {
+		weight := 1.0
+		if e.name != e.root {
+			weight = w.weight
+		}
+		w.estimate(e.d, weight)
+	}
+	switch {
+	case err != nil:
+		w.stats.errors.Add(1)
+	case e.d.IsDir():
+		w.stats.dirs.Add(1)
+	default:
+		w.stats.files.Add(1)
+	}
+	if err != nil && w.debugLog {
+		w.debug("walk error", e, slog.Any("error", err))
+	}
+	if len(w.subscribers) > 0 {
+		w.emitVisit(e, err)
+	}
+	err = fn(w.report(e.root, e.name), e.d, err)
+	if err == fs.SkipAll {
+		w.stopped(SkippedAll)
+	}
+	if w.debugLog {
+		switch {
+		case err == fs.SkipAll:
+			w.debug("stop walk", e)
+		case err == fs.SkipDir && e.d != nil && e.d.IsDir():
+			w.debug("skip directory", e)
+		case err == fs.SkipDir:
+			w.debug("skip remaining entries of parent", e)
+		}
+	}
+	if (err == nil || err == fs.SkipDir) && w.maxResults > 0 && w.stats.visited.Load() >= int64(w.maxResults) {
+		w.stopped(LimitReached)
+		return fs.SkipAll
+	}
+	return err
+}
+
+// report returns the path reported for the entry name found under root.
+func (w *Walker) report(root, name string) string {
+	if w.relative {
+		name = relPath(root, name)
+	}
+	if w.prefix != "" {
+		name = filepath.Join(w.prefix, filepath.FromSlash(name))
+	}
+	if w.rawPaths {
+		return name
+	}
+	return w.norm(name)
+}
+
+// shouldDescend reports whether the directory e should be walked. It makes
+// e the current entry, so that the descend functions see it even when it
+// was filtered out of the walk callback.
+func (w *Walker) shouldDescend(e namedEntry) bool {
+	if w.includeSet && !w.includeDescends(relPath(e.root, e.name)) {
+		return false
+	}
+	if len(w.descend) == 0 {
+		return true
+	}
+	w.current = e
+	reported := w.report(e.root, e.name)
+	for _, fn := range w.descend {
+		if !fn(reported, e.d) {
+			return false
+		}
+	}
+	return true
+}
+
+// pruned reports whether the directory entry d should be left out of the
+// walk.
+func (w *Walker) pruned(d fs.DirEntry) bool {
+	d = w.normEntry(d)
+	for _, fn := range w.prune {
+		if fn(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// Stats holds counters describing a walk.
+type Stats struct {
+	Visited  int64 // entries passed to the callback, including errors
+	Dirs     int64 // directories passed to the callback
+	Files    int64 // non-directories passed to the callback
+	Errors   int64 // errors passed to the callback
+	ReadDirs int64 // completed directory reads
+	Retries  int64 // directory reads retried after an error
+	InFlight int64 // directory reads currently in progress
+	Queued   int64 // directories waiting to be read
+	Denied   int64 // directories left unread by [WithSkipPermissionErrors]
+	MaxQueue int64 // most directories waiting to be read at once
+
+	// Estimates of the size of the whole tree from a walk with
+	// [WithSampling], or 0 without sampling.
+	EstimatedDirs  float64
+	EstimatedFiles float64
+	EstimatedBytes float64 // total size of regular files
+
+	// Stopped tells why the walk stopped, or is Running while it is in
+	// progress.
+	Stopped StopReason
+}
+
+// Stats returns a snapshot of the statistics of the current or most recent
+// walk. It is safe to call Stats from another goroutine while a walk is in
+// progress.
+func (w *Walker) Stats() Stats {
+	return w.stats.snapshot()
+}
+
+type walkStats struct {
+	visited, dirs, files, errors atomic.Int64
+	readDirs, retries            atomic.Int64
+	inFlight, queued, denied     atomic.Int64
+	maxQueue                     atomic.Int64
+	estDirs, estFiles, estBytes  atomic.Uint64 // float64 bits
+	stop                         atomic.Int32  // StopReason
+}
+
+func (s *walkStats) reset() {
+	for _, c := range s.counters() {
+		c.Store(0)
+	}
+	s.estDirs.Store(0)
+	s.estFiles.Store(0)
+	s.estBytes.Store(0)
+	s.stop.Store(int32(Running))
+}
+
+func (s *walkStats) counters() []*atomic.Int64 {
+	return []*atomic.Int64{
+		&s.visited, &s.dirs, &s.files, &s.errors,
+		&s.readDirs, &s.retries, &s.inFlight, &s.queued, &s.denied,
+		&s.maxQueue,
+	}
+}
+
+func (s *walkStats) snapshot() Stats {
+	return Stats{
+		Visited:  s.visited.Load(),
+		Dirs:     s.dirs.Load(),
+		Files:    s.files.Load(),
+		Errors:   s.errors.Load(),
+		ReadDirs: s.readDirs.Load(),
+		Retries:  s.retries.Load(),
+		InFlight: s.inFlight.Load(),
+		Queued:   s.queued.Load(),
+		Denied:   s.denied.Load(),
+		MaxQueue: s.maxQueue.Load(),
+
+		EstimatedDirs:  math.Float64frombits(s.estDirs.Load()),
+		EstimatedFiles: math.Float64frombits(s.estFiles.Load()),
+		EstimatedBytes: math.Float64frombits(s.estBytes.Load()),
+
+		Stopped: StopReason(s.stop.Load()),
+	}
+}
@@ -0,0 +1,305 @@
+package bfwalk
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWalkerStats(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":          {Data: []byte("")},
+		"root/dirA/file1.txt":     {Data: []byte("")},
+		"root/dirB/file1.txt":     {Data: []byte("")},
+		"root/dirB/sub/file1.txt": {Data: []byte("")},
+	}
+
+	w := NewWalker()
+	var visited int64
+	err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited++
+		if s := w.Stats(); s.Visited != visited {
+			t.Errorf("expected %d visited during walk, got %d", visited, s.Visited)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := Stats{
+		Visited:  8,
+		Dirs:     4,
+		Files:    4,
+		ReadDirs: 4,
+		MaxQueue: 2,
+		Stopped:  Completed,
+	}
+	if got := w.Stats(); got != expected {
+		t.Errorf("expected:\n  %+v\ngot\n: %+v", expected, got)
+	}
+}
+
+func TestWalkerStatsConcurrentRead(t *testing.T) {
+	fsys := generateFS("data", 20, 3)
+
+	w := NewWalker()
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				if s := w.Stats(); s.InFlight < 0 || s.Queued < 0 {
+					t.Errorf("invalid stats during walk: %+v", s)
+				}
+			}
+		}
+	}()
+	err := w.WalkDir(fsys, "data", func(path string, d fs.DirEntry, err error) error {
+		return err
+	})
+	close(done)
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := w.Stats(); s.InFlight != 0 || s.Queued != 0 {
+		t.Errorf("expected no pending work after walk, got %+v", s)
+	}
+}
+
+func TestWalkerRelativePaths(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":      {Data: []byte("")},
+		"root/dirA/file1.txt": {Data: []byte("")},
+		"other/file1.txt":     {Data: []byte("")},
+	}
+
+	cases := []struct {
+		name     string
+		roots    []string
+		expected []string
+	}{
+		{"Root", []string{"root"}, []string{".", "dirA", "file1.txt", "dirA/file1.txt"}},
+		{"Dot", []string{"."}, []string{".", "other", "root", "other/file1.txt", "root/dirA", "root/file1.txt", "root/dirA/file1.txt"}},
+		{"Nested", []string{"root/dirA"}, []string{".", "file1.txt"}},
+		{"Multiple", []string{"root", "other"}, []string{".", ".", "dirA", "file1.txt", "file1.txt", "dirA/file1.txt"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var visited []string
+			err := NewWalker(WithRelativePaths()).WalkDirs(memFS, c.roots, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				visited = append(visited, path)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(visited, c.expected) {
+				t.Errorf("expected:\n  %v\ngot\n: %v", c.expected, visited)
+			}
+		})
+	}
+}
+
+func TestWalkerPathPrefix(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "root", "dirA"), 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "root", "dirA", "file1.txt"), nil, 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{"Prefix", []Option{WithPathPrefix(dir)}, []string{
+			filepath.Join(dir, "root"),
+			filepath.Join(dir, "root", "dirA"),
+			filepath.Join(dir, "root", "dirA", "file1.txt"),
+		}},
+		{"Relative", []Option{WithRelativePaths(), WithPathPrefix(dir)}, []string{
+			dir,
+			filepath.Join(dir, "dirA"),
+			filepath.Join(dir, "dirA", "file1.txt"),
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var visited []string
+			err := NewWalker(c.opts...).WalkDir(os.DirFS(dir), "root", func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				visited = append(visited, path)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(visited, c.expected) {
+				t.Errorf("expected:\n  %v\ngot\n: %v", c.expected, visited)
+			}
+		})
+	}
+}
+
+func TestWalkerUnsorted(t *testing.T) {
+	fsys := generateFS("data", 20, 3)
+
+	var sorted, unsorted []string
+	WalkDir(fsys, "data", func(path string, d fs.DirEntry, err error) error {
+		sorted = append(sorted, path)
+		return err
+	})
+	err := NewWalker(WithUnsorted()).WalkDir(fsys, "data", func(path string, d fs.DirEntry, err error) error {
+		if depth := depthOf("data", path); len(unsorted) > 0 && depth < depthOf("data", unsorted[len(unsorted)-1]) {
+			t.Errorf("%s visited after a deeper entry", path)
+		}
+		unsorted = append(unsorted, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	slices.Sort(sorted)
+	slices.Sort(unsorted)
+	if !slices.Equal(sorted, unsorted) {
+		t.Errorf("expected the same entries as a sorted walk")
+	}
+}
+
+func TestWalkerShouldDescend(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":               {Data: []byte("")},
+		"root/dirA/file1.txt":          {Data: []byte("")},
+		"root/node_modules/file1.txt":  {Data: []byte("")},
+		"root/dirB/.git/file1.txt":     {Data: []byte("")},
+		"root/dirB/sub/file1.txt":      {Data: []byte("")},
+		"root/dirB/sub/deep/file1.txt": {Data: []byte("")},
+	}
+
+	var visited []string
+	w := NewWalker(
+		WithShouldDescend(func(path string, d fs.DirEntry) bool {
+			return d.Name() != "node_modules"
+		}),
+		WithShouldDescend(func(path string, d fs.DirEntry) bool {
+			return d.Name() != ".git" && path != "root/dirB/sub/deep"
+		}),
+	)
+	err := w.WalkDir(memFS, "root", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"root",
+		"root/dirA",
+		"root/dirB",
+		"root/file1.txt",
+		"root/node_modules",
+		"root/dirA/file1.txt",
+		"root/dirB/.git",
+		"root/dirB/sub",
+		"root/dirB/sub/deep",
+		"root/dirB/sub/file1.txt",
+	}
+	if !slices.Equal(visited, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+	}
+}
+
+func TestWalkerOnDirComplete(t *testing.T) {
+	memFS := fstest.MapFS{
+		"root/file1.txt":          {Data: []byte("")},
+		"root/dirA/file1.txt":     {Data: []byte("")},
+		"root/dirA/file2.txt":     {Data: []byte("")},
+		"root/dirB/sub/file1.txt": {Data: []byte("")},
+		"root/dirB/sub/skip.txt":  {Data: []byte("")},
+		"root/dirB/sub/zzz.txt":   {Data: []byte("")},
+	}
+
+	expected := []string{
+		"root",
+		"root/dirA",
+		"root/dirB",
+		"root/file1.txt",
+		"done root 3",
+		"root/dirA/file1.txt",
+		"root/dirA/file2.txt",
+		"done root/dirA 2",
+		"root/dirB/sub",
+		"done root/dirB 1",
+		"root/dirB/sub/file1.txt",
+		"root/dirB/sub/skip.txt",
+		"done root/dirB/sub 2",
+	}
+	for _, walk := range []func(*Walker, fs.FS, string, fs.WalkDirFunc) error{(*Walker).WalkDir, (*Walker).IDWalk} {
+		var events []string
+		w := NewWalker(WithOnDirComplete(func(path string, entries int) {
+			events = append(events, fmt.Sprintf("done %s %d", path, entries))
+		}))
+		err := walk(w, memFS, "root", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			events = append(events, path)
+			if d.Name() == "skip.txt" {
+				return fs.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(events, expected) {
+			t.Errorf("expected:\n  %v\ngot\n: %v", expected, events)
+		}
+	}
+
+	// Entries filtered out or seen before are not counted
+	expected = []string{"done root 3", "done root/dirA 1", "done root/dirB 1", "done root/dirB/sub 3"}
+	for _, walk := range []func(*Walker, fs.FS, string, fs.WalkDirFunc) error{(*Walker).WalkDir, (*Walker).IDWalk} {
+		var events []string
+		w := NewWalker(WithFilter(Not(Name("file2.txt"))), WithDedupePaths(), WithOnDirComplete(func(path string, entries int) {
+			events = append(events, fmt.Sprintf("done %s %d", path, entries))
+		}))
+		err := walk(w, repeatFS{memFS}, "root", func(path string, d fs.DirEntry, err error) error {
+			return err
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(events, expected) {
+			t.Errorf("expected:\n  %v\ngot\n: %v", expected, events)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Package watch keeps a breadth-first walk of a directory up to date as the
+// directory changes.
+//
+// [Watch] walks a directory with [bfwalk.WalkDir], watches every directory
+// it visits for changes with fsnotify, and walks the changed parts of the
+// tree again, breadth-first, as changes are reported.
+package watch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eriicafes/bfwalk"
+	"github.com/fsnotify/fsnotify"
+)
+
+// An Option configures [Watch].
+type Option func(*config)
+
+type config struct {
+	debounce time.Duration
+}
+
+// WithDebounce sets how long Watch waits for further changes after a change
+// is reported before walking the changed paths, so that bursts of changes
+// are walked once. The default is 100ms.
+func WithDebounce(d time.Duration) Option {
+	return func(c *config) {
+		c.debounce = d
+	}
+}
+
+// Watch walks the directory root, calling fn for each file or directory in
+// the tree, and then calls fn again for each part of the tree that changes
+// until ctx is done.
+//
+// Paths passed to fn are slash-separated and relative to root, as when
+// walking os.DirFS(root) from ".". When a file changes, fn is called for
+// that file; when a directory is created, fn is called for each entry of
+// the new subtree in breadth-first order. Removed entries are reported to
+// fn with a nil [fs.DirEntry] and an error satisfying
+// errors.Is(err, fs.ErrNotExist), which fn may return without ending Watch.
+// Changes reported together are walked shallowest first.
+//
+// Returning [fs.SkipDir] from fn for a directory leaves it unwatched, and
+// [fs.SkipAll] ends the walk in progress but not Watch. Any other error
+// returned by fn ends Watch and is returned. Watch returns nil when ctx is
+// done.
+func Watch(ctx context.Context, root string, fn fs.WalkDirFunc, opts ...Option) error {
+	c := config{debounce: 100 * time.Millisecond}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	fsys := os.DirFS(root)
+	walk := func(name string) error {
+		return bfwalk.WalkDir(fsys, name, func(name string, d fs.DirEntry, err error) error {
+			removed := d == nil && errors.Is(err, fs.ErrNotExist)
+			err = fn(name, d, err)
+			if removed && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err == nil && d != nil && d.IsDir() {
+				err = watcher.Add(filepath.Join(root, filepath.FromSlash(name)))
+			}
+			return err
+		})
+	}
+	if err := walk("."); err != nil {
+		return err
+	}
+
+	changed := make(map[string]bool)
+	timer := time.NewTimer(c.debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-watcher.Errors:
+			return err
+		case event := <-watcher.Events:
+			rel, err := filepath.Rel(root, event.Name)
+			if err != nil {
+				return err
+			}
+			changed[filepath.ToSlash(rel)] = true
+			timer.Reset(c.debounce)
+		case <-timer.C:
+			for _, name := range pending(changed) {
+				if err := walk(name); err != nil {
+					return err
+				}
+			}
+			clear(changed)
+		}
+	}
+}
+
+// pending returns the changed paths that are not inside another changed
+// path, ordered breadth-first.
+func pending(changed map[string]bool) []string {
+	var names []string
+	for name := range changed {
+		if !changedParent(changed, name) {
+			names = append(names, name)
+		}
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if da, db := strings.Count(a, "/"), strings.Count(b, "/"); da != db {
+			return da - db
+		}
+		return strings.Compare(a, b)
+	})
+	return names
+}
+
+func changedParent(changed map[string]bool, name string) bool {
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if changed[dir] {
+			return true
+		}
+	}
+	return false
+}
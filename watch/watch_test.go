@@ -0,0 +1,93 @@
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "file1.txt"))
+	writeFile(t, filepath.Join(root, "dirA", "file1.txt"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	visits := make(chan string)
+	done := make(chan error)
+	go func() {
+		done <- Watch(ctx, root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				path = "removed:" + path
+			}
+			visits <- path
+			return err
+		}, WithDebounce(20*time.Millisecond))
+	}()
+
+	expect := func(expected ...string) {
+		t.Helper()
+		var visited []string
+		for range expected {
+			select {
+			case path := <-visits:
+				visited = append(visited, path)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out, expected:\n  %v\ngot\n: %v", expected, visited)
+			}
+		}
+		if !slices.Equal(visited, expected) {
+			t.Errorf("expected:\n  %v\ngot\n: %v", expected, visited)
+		}
+	}
+
+	expect(".", "dirA", "file1.txt", "dirA/file1.txt")
+
+	if err := os.MkdirAll(filepath.Join(root, "dirB", "sub"), 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	writeFile(t, filepath.Join(root, "dirB", "file1.txt"))
+	expect("dirB", "dirB/file1.txt", "dirB/sub")
+
+	writeFile(t, filepath.Join(root, "dirB", "sub", "file1.txt"))
+	expect("dirB/sub/file1.txt")
+
+	if err := os.Remove(filepath.Join(root, "dirA", "file1.txt")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect("removed:dirA/file1.txt")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPending(t *testing.T) {
+	changed := map[string]bool{
+		"b/c/d": true,
+		"b/c":   true,
+		"a/x":   true,
+		"z":     true,
+		"b/a":   true,
+	}
+	expected := []string{"z", "a/x", "b/a", "b/c"}
+	if got := pending(changed); !slices.Equal(got, expected) {
+		t.Errorf("expected:\n  %v\ngot\n: %v", expected, got)
+	}
+}
+
+func writeFile(t *testing.T, name string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(name, nil, 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}